@@ -9,68 +9,30 @@ package main
 import (
 	"log"
 	"runtime"
+	"time"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
 
-	"camera/camera"
-	"camera/shader"
-	"camera/win"
-)
+	"gfx"
+	"gfx/camera"
+	"gfx/input"
+	"gfx/ray"
+	"gfx/resource"
+	"gfx/texture"
 
-const (
-	screenWidth  = 600 //窗口宽度
-	screenHeight = 600 //窗口高度
+	"camera/scene"
+	"camera/win"
 )
 
-//立方体数组
-var vertices = []float32{
-	-0.5, -0.5, -0.5, 1.0, 0.0, 0.0,
-	0.5, -0.5, -0.5, 1.0, 0.0, 0.0,
-	0.5, 0.5, -0.5, 1.0, 0.0, 0.0,
-	0.5, 0.5, -0.5, 1.0, 0.0, 0.0,
-	-0.5, 0.5, -0.5, 1.0, 0.0, 0.0,
-	-0.5, -0.5, -0.5, 1.0, 0.0, 0.0,
-
-	-0.5, -0.5, 0.5, 0.0, 1.0, 0.0,
-	0.5, -0.5, 0.5, 0.0, 1.0, 0.0,
-	0.5, 0.5, 0.5, 0.0, 1.0, 0.0,
-	0.5, 0.5, 0.5, 0.0, 1.0, 0.0,
-	-0.5, 0.5, 0.5, 0.0, 1.0, 0.0,
-	-0.5, -0.5, 0.5, 0.0, 1.0, 0.0,
-
-	-0.5, 0.5, 0.5, 0.0, 0.0, 1.0,
-	-0.5, 0.5, -0.5, 0.0, 0.0, 1.0,
-	-0.5, -0.5, -0.5, 0.0, 0.0, 1.0,
-	-0.5, -0.5, -0.5, 0.0, 0.0, 1.0,
-	-0.5, -0.5, 0.5, 0.0, 0.0, 1.0,
-	-0.5, 0.5, 0.5, 0.0, 0.0, 1.0,
-
-	0.5, 0.5, 0.5, 0.5, 0.0, 0.0,
-	0.5, 0.5, -0.5, 0.5, 0.0, 0.0,
-	0.5, -0.5, -0.5, 0.5, 0.0, 0.0,
-	0.5, -0.5, -0.5, 0.5, 0.0, 0.0,
-	0.5, -0.5, 0.5, 0.5, 0.0, 0.0,
-	0.5, 0.5, 0.5, 0.5, 0.0, 0.0,
-
-	-0.5, -0.5, -0.5, 0.0, 0.5, 0.0,
-	0.5, -0.5, -0.5, 0.0, 0.5, 0.0,
-	0.5, -0.5, 0.5, 0.0, 0.5, 0.0,
-	0.5, -0.5, 0.5, 0.0, 0.5, 0.0,
-	-0.5, -0.5, 0.5, 0.0, 0.5, 0.0,
-	-0.5, -0.5, -0.5, 0.0, 0.5, 0.0,
-
-	-0.5, 0.5, -0.5, 0.0, 0.0, 0.5,
-	0.5, 0.5, -0.5, 0.0, 0.0, 0.5,
-	0.5, 0.5, 0.5, 0.0, 0.0, 0.5,
-	0.5, 0.5, 0.5, 0.0, 0.0, 0.5,
-	-0.5, 0.5, 0.5, 0.0, 0.0, 0.5,
-	-0.5, 0.5, -0.5, 0.0, 0.0, 0.5,
+// 第一人称、轨道与轨迹球摄像机, 运行时按C键(NextCamera动作)切换
+var cameras = []camera.Controller{
+	camera.GetCamera(mgl32.Vec3{0.0, 0.0, 3.0}),
+	camera.NewOrbit(mgl32.Vec3{0.0, 0.0, 0.0}, mgl32.Vec3{0.0, 0.0, 3.0}),
+	camera.NewArcball(mgl32.Vec3{0.0, 0.0, 0.0}, mgl32.Vec3{0.0, 0.0, 3.0}),
 }
 
-var cam = camera.GetCamera(mgl32.Vec3{0.0, 0.0, 3.0})
-
 func init() {
 	// GLFW event handling must run on the main OS thread
 	runtime.LockOSThread()
@@ -82,7 +44,14 @@ func main() {
 	}
 	defer glfw.Terminate()
 
-	window := win.NewWindow(screenWidth, screenHeight, "Camera", cam)
+	// 在例程目录中运行时读取磁盘上的资源, 否则使用编译进程序的副本
+	assets := resource.DirOrEmbedded(".", embedded)
+	// 按键绑定, 修改src/input.json即可改键
+	actions, err := input.LoadActionMapFS(assets, "src/input.json")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	window := win.NewWindow(scene.ScreenWidth, scene.ScreenHeight, "Camera", actions, cameras...)
 	//-----------------------------------------
 	//鼠标设置
 	//-----------------------------------------
@@ -90,53 +59,96 @@ func main() {
 	if err := gl.Init(); err != nil {
 		panic(err)
 	}
-	gl.Viewport(0, 0, screenWidth, screenHeight)
+	gl.Viewport(0, 0, scene.ScreenWidth, scene.ScreenHeight)
 
 	//加载着色器
-	camShader, err := shader.NewShader("src/task-camera.vs", "src/task-camera.fs")
+	camShader, err := gfx.NewProgramFromFS(assets, "src/task-camera.vs", "src/task-camera.fs")
 	if err != nil {
 		log.Panic(err)
 	}
+	if err := scene.Layout.Check(camShader); err != nil {
+		log.Panic(err)
+	}
+	if err := camShader.CheckUniforms("model"); err != nil {
+		log.Panic(err)
+	}
+	// 修改着色器源码后自动重新编译
+	watcher := gfx.NewShaderWatcher(500 * time.Millisecond)
+	defer watcher.Close()
+	if err := watcher.Watch(camShader); err != nil {
+		log.Panic(err)
+	}
 
-	var VAO, VBO uint32
-	gl.GenVertexArrays(1, &VAO)
-	gl.BindVertexArray(VAO)
-	gl.GenBuffers(1, &VBO)
+	// 相机矩阵放在uniform缓冲中, 每帧上传一次, 所有使用Camera块的着色器共享
+	cameraBlock, err := gfx.NewUniformBuffer(gfx.CameraBinding, &gfx.CameraBlock{})
+	if err != nil {
+		log.Panic(err)
+	}
+	if err := camShader.BindUniformBlock("Camera", cameraBlock); err != nil {
+		log.Panic(err)
+	}
+
+	// 上传顶点数据并按顶点布局设置属性指针
+	cube, err := gfx.NewMesh(gl.TRIANGLES, scene.Layout, scene.Vertices, nil)
+	if err != nil {
+		log.Panic(err)
+	}
+	// 拾取用的三角形顶点(模型空间)
+	cubePositions := ray.Positions(scene.Vertices, scene.Layout.Floats())
 
-	gl.BindBuffer(gl.ARRAY_BUFFER, VBO)
-	// 将顶点数据绑定至当前默认的缓冲中
-	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
-	// 设置顶点属性指针
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 6*4, gl.PtrOffset(0))
-	gl.EnableVertexAttribArray(0)
-	gl.VertexAttribPointer(1, 3, gl.FLOAT, false, 6*4, gl.PtrOffset(12))
-	gl.EnableVertexAttribArray(1)
+	// 天空盒: 全景图转换为立方体贴图, 代替纯色背景
+	skyMap, err := texture.NewCubemapFromFS(assets, "src/sky.png", texture.TextureOptions{})
+	if err != nil {
+		log.Panic(err)
+	}
+	skybox, err := gfx.NewSkybox(skyMap)
+	if err != nil {
+		log.Panic(err)
+	}
 
 	gl.Enable(gl.DEPTH_TEST)
 	for !window.ShouldClose() {
 		window.StartProcessInput()
+		for _, e := range window.GamepadEvents() {
+			log.Println(e)
+		}
+		watcher.Update()
 		gl.ClearColor(0.0, 0.34, 0.57, 1.0)
 		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT) //清理颜色缓冲和深度缓冲
 
 		camShader.Use()
-		model := mgl32.HomogRotate3D(float32(glfw.GetTime()), mgl32.Vec3{0.5, 1.0, 0.0})
+		model := scene.Model(glfw.GetTime())
 		//-------------------------------------
 		// Transform坐标变换矩
-		view := cam.GetViewMatrix()
+		view := window.Camera().GetViewMatrix()
 
-		projection := mgl32.Perspective(45.0, float32(screenWidth/screenHeight), 0.1, 10.0)
+		projection := window.Camera().GetProjectionMatrix()
 		// 向着色器中传入参数
+		if err := cameraBlock.Update(&gfx.CameraBlock{View: view, Projection: projection}); err != nil {
+			log.Panic(err)
+		}
 		camShader.SetMat4("model", model)
-		camShader.SetMat4("view", view)
-		camShader.SetMat4("projection", projection)
 
-		//绘制
-		gl.BindVertexArray(VAO)
-		gl.DrawArrays(gl.TRIANGLES, 0, 36)
-		gl.BindVertexArray(0)
+		// 点击时把射线变换到模型空间与立方体求交, 每个面由两个三角形组成
+		if pick, ok := window.ClickRay(); ok {
+			if hit, ok := pick.Transform(model.Inv()).IntersectTriangles(cubePositions, nil); ok {
+				log.Printf("picked cube face %d at distance %.2f", hit.Triangle/2, hit.T)
+			}
+		}
+
+		//绘制, 立方体完全在视锥外时跳过
+		frustum := window.Camera().Frustum()
+		if frustum.TestBox(scene.BoundsMin, scene.BoundsMax, model) != camera.Outside {
+			cube.Draw()
+		}
+		// 天空盒最后绘制, 只填充没有被物体覆盖的像素
+		skybox.Draw(view, projection)
 
 	}
-	//释放VAOVBO
-	gl.DeleteVertexArrays(1, &VAO)
-	gl.DeleteBuffers(1, &VBO)
+	//释放VAO、VBO
+	cube.Delete()
+	cameraBlock.Delete()
+	skybox.Delete()
+	skyMap.Delete()
+	camShader.Delete()
 }
@@ -0,0 +1,120 @@
+/*
+摄像机场景的顶点数据、变换矩阵与软件渲染版本
+main.go 使用同一份数据通过OpenGL绘制
+*/
+
+package scene
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx"
+	"gfx/camera"
+	"gfx/soft"
+)
+
+const (
+	ScreenWidth  = 600 //窗口宽度
+	ScreenHeight = 600 //窗口高度
+)
+
+//立方体数组
+var Vertices = []float32{
+	-0.5, -0.5, -0.5, 1.0, 0.0, 0.0,
+	0.5, -0.5, -0.5, 1.0, 0.0, 0.0,
+	0.5, 0.5, -0.5, 1.0, 0.0, 0.0,
+	0.5, 0.5, -0.5, 1.0, 0.0, 0.0,
+	-0.5, 0.5, -0.5, 1.0, 0.0, 0.0,
+	-0.5, -0.5, -0.5, 1.0, 0.0, 0.0,
+
+	-0.5, -0.5, 0.5, 0.0, 1.0, 0.0,
+	0.5, -0.5, 0.5, 0.0, 1.0, 0.0,
+	0.5, 0.5, 0.5, 0.0, 1.0, 0.0,
+	0.5, 0.5, 0.5, 0.0, 1.0, 0.0,
+	-0.5, 0.5, 0.5, 0.0, 1.0, 0.0,
+	-0.5, -0.5, 0.5, 0.0, 1.0, 0.0,
+
+	-0.5, 0.5, 0.5, 0.0, 0.0, 1.0,
+	-0.5, 0.5, -0.5, 0.0, 0.0, 1.0,
+	-0.5, -0.5, -0.5, 0.0, 0.0, 1.0,
+	-0.5, -0.5, -0.5, 0.0, 0.0, 1.0,
+	-0.5, -0.5, 0.5, 0.0, 0.0, 1.0,
+	-0.5, 0.5, 0.5, 0.0, 0.0, 1.0,
+
+	0.5, 0.5, 0.5, 0.5, 0.0, 0.0,
+	0.5, 0.5, -0.5, 0.5, 0.0, 0.0,
+	0.5, -0.5, -0.5, 0.5, 0.0, 0.0,
+	0.5, -0.5, -0.5, 0.5, 0.0, 0.0,
+	0.5, -0.5, 0.5, 0.5, 0.0, 0.0,
+	0.5, 0.5, 0.5, 0.5, 0.0, 0.0,
+
+	-0.5, -0.5, -0.5, 0.0, 0.5, 0.0,
+	0.5, -0.5, -0.5, 0.0, 0.5, 0.0,
+	0.5, -0.5, 0.5, 0.0, 0.5, 0.0,
+	0.5, -0.5, 0.5, 0.0, 0.5, 0.0,
+	-0.5, -0.5, 0.5, 0.0, 0.5, 0.0,
+	-0.5, -0.5, -0.5, 0.0, 0.5, 0.0,
+
+	-0.5, 0.5, -0.5, 0.0, 0.0, 0.5,
+	0.5, 0.5, -0.5, 0.0, 0.0, 0.5,
+	0.5, 0.5, 0.5, 0.0, 0.0, 0.5,
+	0.5, 0.5, 0.5, 0.0, 0.0, 0.5,
+	-0.5, 0.5, 0.5, 0.0, 0.0, 0.5,
+	-0.5, 0.5, -0.5, 0.0, 0.0, 0.5,
+}
+
+//立方体在模型空间中的包围盒
+var (
+	BoundsMin = mgl32.Vec3{-0.5, -0.5, -0.5}
+	BoundsMax = mgl32.Vec3{0.5, 0.5, 0.5}
+)
+
+//Model 随时间t(秒)旋转的模型矩阵
+func Model(t float64) mgl32.Mat4 {
+	return mgl32.HomogRotate3D(float32(t), mgl32.Vec3{0.5, 1.0, 0.0})
+}
+
+//Layout 顶点布局: 位置与颜色交错存放, 对应src/task-camera.vs的输入
+var Layout = gfx.NewVertexLayout(
+	gfx.FloatAttrib("aPos", 3),
+	gfx.FloatAttrib("PosColor", 3),
+)
+
+//Render 使用软件光栅化绘制与main.go相同的一帧
+//cam 摄像机, 其投影参数决定视角与宽高比, t 为程序运行时间(秒)
+func Render(ctx *soft.Context, cam camera.Controller, t float64) error {
+	VAO := ctx.GenVertexArray()
+	ctx.BindVertexArray(VAO)
+	VBO := ctx.GenBuffer()
+	ctx.BindBuffer(soft.ARRAY_BUFFER, VBO)
+	ctx.BufferData(soft.ARRAY_BUFFER, Vertices)
+	if err := Layout.ApplySoft(ctx); err != nil {
+		return err
+	}
+
+	ctx.Enable(soft.DEPTH_TEST)
+
+	// 对应src/task-camera.vs与src/task-camera.fs
+	mvp := cam.GetViewProjectionMatrix().Mul4(Model(t))
+	program := &soft.Program{
+		Varyings: 3,
+		Vertex: func(in []mgl32.Vec4, out []float32) mgl32.Vec4 {
+			out[0], out[1], out[2] = in[1].X(), in[1].Y(), in[1].Z()
+			return mvp.Mul4x1(in[0])
+		},
+		Fragment: func(in []float32) mgl32.Vec4 {
+			return mgl32.Vec4{in[0], in[1], in[2], 1.0}
+		},
+	}
+
+	ctx.ClearColor(0.0, 0.34, 0.57, 1.0)
+	ctx.Clear(soft.COLOR_BUFFER_BIT | soft.DEPTH_BUFFER_BIT)
+	ctx.UseProgram(program)
+	ctx.BindVertexArray(VAO)
+	ctx.DrawArrays(soft.TRIANGLES, 0, int32(len(Vertices)/Layout.Floats()))
+	ctx.BindVertexArray(0)
+
+	ctx.DeleteVertexArray(VAO)
+	ctx.DeleteBuffer(VBO)
+	return nil
+}
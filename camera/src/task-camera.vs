@@ -3,8 +3,11 @@ layout (location = 0) in vec3 aPos;
 layout (location = 1) in vec3 PosColor;
 out vec3 positionColor;
 uniform mat4 model;
-uniform mat4 view;
-uniform mat4 projection;
+// 相机矩阵由uniform缓冲提供, 见gfx.CameraBlock
+layout (std140) uniform Camera {
+	mat4 view;
+	mat4 projection;
+};
 
 void main()
 {
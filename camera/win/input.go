@@ -1,9 +1,11 @@
 package win
 
 import (
+	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 
-	"camera/camera"
+	"gfx/camera"
+	"gfx/input"
 )
 
 func (w *Window) processInput() {
@@ -12,17 +14,51 @@ func (w *Window) processInput() {
 	w.deltaTime = currentFrame - w.lastFrame
 	w.lastFrame = currentFrame
 
-	if w.gWin.GetKey(glfw.KeyEscape) == glfw.Press {
+	im := w.winInput
+	im.pollGamepad()
+	im.actions.Update()
+	if im.actions.Pressed("Quit") {
 		w.gWin.SetShouldClose(true)
-	} else if w.gWin.GetKey(glfw.KeyW) == glfw.Press {
-		w.winInput.cam.ProcessKeyboard(camera.FORWARD, w.deltaTime)
-	} else if w.gWin.GetKey(glfw.KeyS) == glfw.Press {
-		w.winInput.cam.ProcessKeyboard(camera.BACKWARD, w.deltaTime)
-	} else if w.gWin.GetKey(glfw.KeyA) == glfw.Press {
-		w.winInput.cam.ProcessKeyboard(camera.LEFT, w.deltaTime)
-	} else if w.gWin.GetKey(glfw.KeyD) == glfw.Press {
-		w.winInput.cam.ProcessKeyboard(camera.RIGHT, w.deltaTime)
 	}
+	if im.actions.Pressed("NextCamera") {
+		im.current = (im.current + 1) % len(im.cams)
+		im.cam = im.cams[im.current]
+	}
+	if im.actions.Pressed("ToggleProjection") {
+		for _, cam := range im.cams {
+			cam.Lens().Orthographic = !cam.Lens().Orthographic
+		}
+	}
+	// 各方向独立判断, 可以同时按住多个键斜向移动; 摇杆按推动的幅度减速
+	for action, direction := range moveActions {
+		if v := im.actions.Value(action); v > 0 {
+			im.cam.ProcessKeyboard(direction, w.deltaTime*float64(v))
+		}
+	}
+	// 手柄的视角与缩放按帧时间积分, 换算成与鼠标相同的单位; yoffset为正时抬头, LookUp取正号
+	lookX := im.actions.Value("LookRight") - im.actions.Value("LookLeft")
+	lookY := im.actions.Value("LookUp") - im.actions.Value("LookDown")
+	if lookX != 0 || lookY != 0 {
+		im.cam.ProcessMouseMovement(float64(lookX)*gamepadLookSpeed*w.deltaTime,
+			float64(lookY)*gamepadLookSpeed*w.deltaTime, true)
+	}
+	if zoom := im.actions.Value("ZoomIn") - im.actions.Value("ZoomOut"); zoom != 0 {
+		im.cam.ProcessMouseScroll(float64(zoom) * gamepadZoomSpeed * w.deltaTime)
+	}
+}
+
+// 手柄推到底时的速度
+const (
+	gamepadLookSpeed = 600.0 //视角: 每秒相当于鼠标移动的像素数
+	gamepadZoomSpeed = 6.0   //缩放: 每秒相当于滚轮滚动的格数
+)
+
+//moveActions 移动动作对应的摄像机方向
+var moveActions = map[string]uint32{
+	"MoveForward":  camera.FORWARD,
+	"MoveBackward": camera.BACKWARD,
+	"MoveLeft":     camera.LEFT,
+	"MoveRight":    camera.RIGHT,
 }
 
 type inputManager struct {
@@ -30,10 +66,78 @@ type inputManager struct {
 	lastX      float64
 	lastY      float64
 
-	xoffset     float64
-	yoffset     float64
-	cam         *camera.Camera
-	keysPressed [glfw.KeyLast]bool
+	xoffset float64
+	yoffset float64
+	cams    []camera.Controller
+	cam     camera.Controller
+	current int
+	actions *input.ActionMap
+	gamepad *input.Gamepad
+}
+
+//pollGamepad 读取正在使用的手柄状态写入动作映射
+func (im *inputManager) pollGamepad() {
+	id, ok := im.gamepad.Active()
+	if !ok {
+		return
+	}
+	gs := glfw.Joystick(id).GetGamepadState()
+	if gs == nil {
+		return
+	}
+	state := input.GamepadState{Axes: gs.Axes}
+	for i, action := range gs.Buttons {
+		state.Buttons[i] = action == glfw.Press
+	}
+	im.gamepad.Apply(state)
+}
+
+//手柄热插拔, 只接受GLFW有标准映射的手柄; 断开时无法再查询设备, 直接交给Gamepad处理
+func (im *inputManager) joystickCallback(joy glfw.Joystick, event glfw.PeripheralEvent) {
+	switch event {
+	case glfw.Connected:
+		if joy.IsGamepad() {
+			im.gamepad.Connect(int(joy), joy.GetGamepadName())
+		}
+	case glfw.Disconnected:
+		im.gamepad.Disconnect(int(joy))
+	}
+}
+
+//鼠标按键交给动作映射, 点击拾取由Window.ClickRay查询
+func (im *inputManager) mouseButtonCallback(window *glfw.Window, button glfw.MouseButton,
+	action glfw.Action, mods glfw.ModifierKey) {
+
+	if name, ok := mouseButtonNames[button]; ok {
+		im.actions.SetMouseButton(name, action == glfw.Press)
+	}
+}
+
+//按键交给动作映射, 按住不放时的重复事件不改变状态
+func (im *inputManager) keyCallback(window *glfw.Window, key glfw.Key, scancode int,
+	action glfw.Action, mods glfw.ModifierKey) {
+
+	if action == glfw.Repeat {
+		return
+	}
+	if name, ok := keyNames[key]; ok {
+		im.actions.SetKey(name, action == glfw.Press)
+	}
+}
+
+//窗口失去焦点时收不到松开事件, 清除所有按下的键
+func (im *inputManager) focusCallback(window *glfw.Window, focused bool) {
+	if !focused {
+		im.actions.Reset()
+	}
+}
+
+//窗口大小改变时更新视口与所有摄像机的宽高比
+func (im *inputManager) framebufferSizeCallback(window *glfw.Window, width, height int) {
+	gl.Viewport(0, 0, int32(width), int32(height))
+	for _, cam := range im.cams {
+		cam.Lens().SetViewport(width, height)
+	}
 }
 
 func (im *inputManager) mouseCallback(window *glfw.Window, xpos, ypos float64) {
@@ -0,0 +1,63 @@
+package win
+
+import (
+	"fmt"
+
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+//keyNames GLFW按键对应的输入源名字, 即配置文件中 "key:名字" 的名字部分
+var keyNames = map[glfw.Key]string{
+	glfw.KeySpace:        "Space",
+	glfw.KeyApostrophe:   "Apostrophe",
+	glfw.KeyComma:        "Comma",
+	glfw.KeyMinus:        "Minus",
+	glfw.KeyPeriod:       "Period",
+	glfw.KeySlash:        "Slash",
+	glfw.KeySemicolon:    "Semicolon",
+	glfw.KeyEqual:        "Equal",
+	glfw.KeyLeftBracket:  "LeftBracket",
+	glfw.KeyBackslash:    "Backslash",
+	glfw.KeyRightBracket: "RightBracket",
+	glfw.KeyGraveAccent:  "GraveAccent",
+	glfw.KeyEscape:       "Escape",
+	glfw.KeyEnter:        "Enter",
+	glfw.KeyTab:          "Tab",
+	glfw.KeyBackspace:    "Backspace",
+	glfw.KeyInsert:       "Insert",
+	glfw.KeyDelete:       "Delete",
+	glfw.KeyRight:        "Right",
+	glfw.KeyLeft:         "Left",
+	glfw.KeyDown:         "Down",
+	glfw.KeyUp:           "Up",
+	glfw.KeyPageUp:       "PageUp",
+	glfw.KeyPageDown:     "PageDown",
+	glfw.KeyHome:         "Home",
+	glfw.KeyEnd:          "End",
+	glfw.KeyLeftShift:    "LeftShift",
+	glfw.KeyLeftControl:  "LeftControl",
+	glfw.KeyLeftAlt:      "LeftAlt",
+	glfw.KeyRightShift:   "RightShift",
+	glfw.KeyRightControl: "RightControl",
+	glfw.KeyRightAlt:     "RightAlt",
+}
+
+//mouseButtonNames GLFW鼠标按键对应的输入源名字
+var mouseButtonNames = map[glfw.MouseButton]string{
+	glfw.MouseButtonLeft:   "Left",
+	glfw.MouseButtonRight:  "Right",
+	glfw.MouseButtonMiddle: "Middle",
+}
+
+func init() {
+	// 字母、数字与功能键按顺序排列, 不逐个列出
+	for k := glfw.KeyA; k <= glfw.KeyZ; k++ {
+		keyNames[k] = string(rune('A' + k - glfw.KeyA))
+	}
+	for k := glfw.Key0; k <= glfw.Key9; k++ {
+		keyNames[k] = string(rune('0' + k - glfw.Key0))
+	}
+	for k := glfw.KeyF1; k <= glfw.KeyF12; k++ {
+		keyNames[k] = fmt.Sprintf("F%d", k-glfw.KeyF1+1)
+	}
+}
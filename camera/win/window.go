@@ -5,7 +5,9 @@ import (
 
 	"github.com/go-gl/glfw/v3.3/glfw"
 
-	"camera/camera"
+	"gfx/camera"
+	"gfx/input"
+	"gfx/ray"
 )
 
 //Window 窗口结构体
@@ -19,8 +21,10 @@ type Window struct {
 }
 
 //NewWindow 窗口结构体Window构造函数
-func NewWindow(width, height int, title string, cam *camera.Camera) *Window {
-	glfw.WindowHint(glfw.Resizable, glfw.False)
+//actions 按键绑定, 使用的动作见processInput与ClickRay
+//cams 可供切换的摄像机, NextCamera动作依次切换, 初始使用第一个
+func NewWindow(width, height int, title string, actions *input.ActionMap, cams ...camera.Controller) *Window {
+	glfw.WindowHint(glfw.Resizable, glfw.True)
 	glfw.WindowHint(glfw.ContextVersionMajor, 4)
 	glfw.WindowHint(glfw.ContextVersionMinor, 1)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
@@ -38,11 +42,30 @@ func NewWindow(width, height int, title string, cam *camera.Camera) *Window {
 		firstMouse: true,
 		lastX:      x,
 		lastY:      y,
-		cam:        cam,
+		cams:       cams,
+		cam:        cams[0],
+		actions:    actions,
+		gamepad:    input.NewGamepad(actions),
+	}
+	checkBindings(actions)
+	// 启动前已经连接的手柄不会触发回调, 先扫描一遍
+	for joy := glfw.Joystick1; joy <= glfw.JoystickLast; joy++ {
+		if joy.Present() && joy.IsGamepad() {
+			im.gamepad.Connect(int(joy), joy.GetGamepadName())
+		}
+	}
+	glfw.SetJoystickCallback(im.joystickCallback)
+	gWindow.SetKeyCallback(im.keyCallback)
+	gWindow.SetFocusCallback(im.focusCallback)
+	gWindow.SetFramebufferSizeCallback(im.framebufferSizeCallback)
+	// 此时OpenGL还没有初始化, 只更新摄像机的宽高比
+	fbWidth, fbHeight := gWindow.GetFramebufferSize()
+	for _, cam := range cams {
+		cam.Lens().SetViewport(fbWidth, fbHeight)
 	}
-	// gWindow.SetKeyCallback(im.keyCallback)
 	gWindow.SetCursorPosCallback(im.mouseCallback)
 	gWindow.SetScrollCallback(im.scrollCallback)
+	gWindow.SetMouseButtonCallback(im.mouseButtonCallback)
 
 	return &Window{
 		width:  width,
@@ -65,6 +88,40 @@ func (w *Window) Height() int {
 	return w.height
 }
 
+//Camera 返回当前使用的摄像机
+func (w *Window) Camera() camera.Controller {
+	return w.winInput.cam
+}
+
+//Actions 返回动作映射, 可以在运行时修改绑定
+func (w *Window) Actions() *input.ActionMap {
+	return w.winInput.actions
+}
+
+//Gamepad 返回手柄, 可以调整死区与响应曲线
+func (w *Window) Gamepad() *input.Gamepad {
+	return w.winInput.gamepad
+}
+
+//GamepadEvents 取走上次调用以来手柄的连接与断开事件
+func (w *Window) GamepadEvents() []input.GamepadEvent {
+	return w.winInput.gamepad.Events()
+}
+
+//ClickRay 本帧按下Pick动作时光标处发出的世界空间射线, 没有按下时ok为false
+//光标隐藏(鼠标控制视角)时射线从窗口中心发出
+func (w *Window) ClickRay() (r ray.Ray, ok bool) {
+	if !w.winInput.actions.Pressed("Pick") {
+		return ray.Ray{}, false
+	}
+	width, height := w.gWin.GetSize()
+	x, y := float64(width)/2, float64(height)/2
+	if w.gWin.GetInputMode(glfw.CursorMode) != glfw.CursorDisabled {
+		x, y = w.winInput.lastX, w.winInput.lastY
+	}
+	return w.winInput.cam.ScreenPointToRay(x, y, width, height), true
+}
+
 //ShouldClose 询问窗口是否需要关闭
 func (w *Window) ShouldClose() bool {
 	return w.gWin.ShouldClose()
@@ -79,3 +136,27 @@ func (w *Window) StartProcessInput() {
 	//检测键盘输入
 	w.processInput()
 }
+
+//checkBindings 提示配置中本窗口不认识的输入源名字, 这些绑定永远不会触发
+func checkBindings(actions *input.ActionMap) {
+	known := map[input.Source]bool{}
+	for _, name := range keyNames {
+		known[input.Source{Device: input.Key, Name: name}] = true
+	}
+	for _, name := range mouseButtonNames {
+		known[input.Source{Device: input.MouseButton, Name: name}] = true
+	}
+	for _, name := range input.ButtonNames {
+		known[input.Source{Device: input.GamepadButton, Name: name}] = true
+	}
+	for _, name := range input.AxisNames {
+		known[input.Source{Device: input.GamepadAxis, Name: name}] = true
+	}
+	for _, action := range actions.Actions() {
+		for _, src := range actions.Bindings(action) {
+			if !known[input.Source{Device: src.Device, Name: src.Name}] {
+				log.Printf("input: %s is bound to unknown %s", action, src)
+			}
+		}
+	}
+}
@@ -1,9 +1,12 @@
 module cube
 
-go 1.13
+go 1.16
 
 require (
+	gfx v0.0.0
 	github.com/go-gl/gl v0.0.0-20190320180904-bf2b1f2f34d7
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20200222043503-6f7a984d4dc4
 	github.com/go-gl/mathgl v0.0.0-20190713194549-592312d8590a
 )
+
+replace gfx => ../gfx
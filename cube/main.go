@@ -1,69 +1,21 @@
 package main
 
 import (
-	"cube/shader"
-	"cube/texture"
 	"log"
 	"runtime"
+	"time"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/go-gl/mathgl/mgl32"
-)
 
-const (
-	//SCRWIDTH 窗口宽
-	SCRWIDTH = 800
-	//SCRHEIGHT 窗口高
-	SCRHEIGHT = 600
+	"cube/scene"
+	"gfx"
+	"gfx/camera"
+	"gfx/resource"
+	"gfx/texture"
 )
 
-// set up vertex data (and buffer(s)) and configure vertex attributes
-// ------------------------------------------------------------------
-var vertices = []float32{
-	-0.5, -0.5, -0.5, 0.0, 0.0,
-	0.5, -0.5, -0.5, 1.0, 0.0,
-	0.5, 0.5, -0.5, 1.0, 1.0,
-	0.5, 0.5, -0.5, 1.0, 1.0,
-	-0.5, 0.5, -0.5, 0.0, 1.0,
-	-0.5, -0.5, -0.5, 0.0, 0.0,
-
-	-0.5, -0.5, 0.5, 0.0, 0.0,
-	0.5, -0.5, 0.5, 1.0, 0.0,
-	0.5, 0.5, 0.5, 1.0, 1.0,
-	0.5, 0.5, 0.5, 1.0, 1.0,
-	-0.5, 0.5, 0.5, 0.0, 1.0,
-	-0.5, -0.5, 0.5, 0.0, 0.0,
-
-	-0.5, 0.5, 0.5, 1.0, 0.0,
-	-0.5, 0.5, -0.5, 1.0, 1.0,
-	-0.5, -0.5, -0.5, 0.0, 1.0,
-	-0.5, -0.5, -0.5, 0.0, 1.0,
-	-0.5, -0.5, 0.5, 0.0, 0.0,
-	-0.5, 0.5, 0.5, 1.0, 0.0,
-
-	0.5, 0.5, 0.5, 1.0, 0.0,
-	0.5, 0.5, -0.5, 1.0, 1.0,
-	0.5, -0.5, -0.5, 0.0, 1.0,
-	0.5, -0.5, -0.5, 0.0, 1.0,
-	0.5, -0.5, 0.5, 0.0, 0.0,
-	0.5, 0.5, 0.5, 1.0, 0.0,
-
-	-0.5, -0.5, -0.5, 0.0, 1.0,
-	0.5, -0.5, -0.5, 1.0, 1.0,
-	0.5, -0.5, 0.5, 1.0, 0.0,
-	0.5, -0.5, 0.5, 1.0, 0.0,
-	-0.5, -0.5, 0.5, 0.0, 0.0,
-	-0.5, -0.5, -0.5, 0.0, 1.0,
-
-	-0.5, 0.5, -0.5, 0.0, 1.0,
-	0.5, 0.5, -0.5, 1.0, 1.0,
-	0.5, 0.5, 0.5, 1.0, 0.0,
-	0.5, 0.5, 0.5, 1.0, 0.0,
-	-0.5, 0.5, 0.5, 0.0, 0.0,
-	-0.5, 0.5, -0.5, 0.0, 1.0,
-}
-
 func init() {
 	// GLFW event handling must run on the main OS thread
 	runtime.LockOSThread()
@@ -75,12 +27,12 @@ func main() {
 	}
 	defer glfw.Terminate()
 
-	glfw.WindowHint(glfw.Resizable, glfw.False)
+	glfw.WindowHint(glfw.Resizable, glfw.True)
 	glfw.WindowHint(glfw.ContextVersionMajor, 4)
 	glfw.WindowHint(glfw.ContextVersionMinor, 1)
 	glfw.WindowHint(glfw.OpenGLProfile, glfw.OpenGLCoreProfile)
 	glfw.WindowHint(glfw.OpenGLForwardCompatible, glfw.True)
-	window, err := glfw.CreateWindow(SCRWIDTH, SCRHEIGHT, "Cube", nil, nil)
+	window, err := glfw.CreateWindow(scene.SCRWIDTH, scene.SCRHEIGHT, "Cube", nil, nil)
 	if err != nil {
 		panic(err)
 	}
@@ -95,82 +47,104 @@ func main() {
 	// -----------------------------
 	gl.Enable(gl.DEPTH_TEST)
 
-	ourShader, err := shader.NewShader("src/cube.vs", "src/cube.fs")
+	// look for assets in the working directory first, then in the copy embedded in the binary
+	assets := resource.NewManager(".")
+	assets.AddFS(embedded)
+	defer assets.Close()
+
+	ourShader, err := assets.Program("src/cube.vs", "src/cube.fs")
 	if err != nil {
 		panic(err)
 	}
+	defer ourShader.Delete()
+	if err := scene.Layout.Check(ourShader.Program); err != nil {
+		panic(err)
+	}
+	if err := ourShader.CheckUniforms("model", "texture1", "texture2"); err != nil {
+		panic(err)
+	}
+	// recompile the shaders whenever their source files change
+	watcher := gfx.NewShaderWatcher(500 * time.Millisecond)
+	defer watcher.Close()
+	if err := watcher.Watch(ourShader.Program); err != nil {
+		panic(err)
+	}
 
-	var VBO, VAO uint32
-	gl.GenVertexArrays(1, &VAO)
-	gl.GenBuffers(1, &VBO)
-
-	gl.BindVertexArray(VAO)
-
-	gl.BindBuffer(gl.ARRAY_BUFFER, VBO)
-	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
-
-	// position attribute
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 5*4, gl.PtrOffset(0))
-	gl.EnableVertexAttribArray(0)
-	// texture coord attribute
-	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, 5*4, gl.PtrOffset(12))
-	gl.EnableVertexAttribArray(1)
+	// upload vertices with position and texture coord attributes
+	cube, err := gfx.NewMesh(gl.TRIANGLES, scene.Layout, scene.Vertices, nil)
+	if err != nil {
+		panic(err)
+	}
+	defer cube.Delete()
 
 	// load and create a texture
+	// clamp both axes; the cube spins, so mipmaps keep the minified faces smooth
+	textureOptions := texture.TextureOptions{
+		WrapS:  gl.CLAMP_TO_EDGE,
+		WrapT:  gl.CLAMP_TO_EDGE,
+		Mipmap: true,
+	}
 	// texture 1
-	texture1, err := texture.NewTextureFromFile("src/1.png",
-		gl.CLAMP_TO_EDGE, gl.CLAMP_TO_EDGE)
+	texture1, err := assets.Texture("src/1.png", textureOptions)
 	if err != nil {
 		panic(err.Error())
 	}
+	defer texture1.Delete()
 	// texture 2
-	texture2, err := texture.NewTextureFromFile("src/12.png",
-		gl.CLAMP_TO_EDGE, gl.CLAMP_TO_EDGE)
+	texture2, err := assets.Texture("src/12.png", textureOptions)
 	if err != nil {
 		panic(err.Error())
 	}
+	defer texture2.Delete()
 	ourShader.Use()
 	// create transformations
-	// var model ,view,projection mgl32.Mat4
+	// drag with the left mouse button to orbit the cube, right button to pan, scroll to zoom
+	cam := scene.NewCamera()
+	bindOrbitControls(window, cam)
 	model := mgl32.Ident4()
-	view := mgl32.LookAtV(mgl32.Vec3{3, 3, 3}, mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 1, 0})
-	projection := mgl32.Perspective(mgl32.DegToRad(35.0), float32(SCRWIDTH/SCRHEIGHT), 0.1, 10.0)
-
-	ourShader.SetMat4("view", view)
-	ourShader.SetMat4("projection", projection)
+	cameraBlock, err := gfx.NewUniformBuffer(gfx.CameraBinding, &gfx.CameraBlock{})
+	if err != nil {
+		panic(err)
+	}
+	defer cameraBlock.Delete()
+	if err := ourShader.BindUniformBlock("Camera", cameraBlock); err != nil {
+		panic(err)
+	}
 
 	angle := 0.0
 	previousTime := glfw.GetTime()
 	for !window.ShouldClose() {
 		// poll events and call their registered callbacks
 		glfw.PollEvents()
+		// the camera block binding survives a reload, model is set every frame
+		watcher.Update()
 
 		// background color
 		gl.ClearColor(0.2, 0.3, 0.3, 1.0)
 		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 		// Update
-		time := glfw.GetTime()
-		elapsed := time - previousTime
-		previousTime = time
+		now := glfw.GetTime()
+		elapsed := now - previousTime
+		previousTime = now
 
 		angle += elapsed
-		model = mgl32.HomogRotate3D(float32(angle), mgl32.Vec3{1, 0, 0})
+		model = scene.Model(angle)
+		if err := cameraBlock.Update(&gfx.CameraBlock{View: cam.GetViewMatrix(), Projection: cam.GetProjectionMatrix()}); err != nil {
+			panic(err)
+		}
 		// draw vertices
 		ourShader.SetMat4("model", model)
 		ourShader.Use()
 
 		// set texture1 to uniform0 in the fragment shader
 		texture1.Bind(gl.TEXTURE0)
-		texture1.SetUniform(ourShader.GetUniform("texture1"))
+		texture1.SetUniform(ourShader.GetUniformLocation("texture1"))
 
 		// set texture2 to uniform1 in the fragment shader
 		texture2.Bind(gl.TEXTURE1)
-		texture2.SetUniform(ourShader.GetUniform("texture1"))
-
-		gl.BindVertexArray(VAO)
-		gl.DrawArrays(gl.TRIANGLES, 0, 36)
+		texture2.SetUniform(ourShader.GetUniformLocation("texture2"))
 
-		gl.BindVertexArray(0)
+		cube.Draw()
 
 		texture1.UnBind()
 		texture2.UnBind()
@@ -180,7 +154,29 @@ func main() {
 		// swap in the rendered buffer
 		window.SwapBuffers()
 	}
-	// 删除VAO和VBO
-	gl.DeleteVertexArrays(1, &VAO)
-	gl.DeleteBuffers(1, &VBO)
-}
\ No newline at end of file
+}
+
+// bindOrbitControls drives cam with the mouse and the window size
+func bindOrbitControls(window *glfw.Window, cam *camera.Orbit) {
+	lastX, lastY := window.GetCursorPos()
+	window.SetCursorPosCallback(func(w *glfw.Window, x, y float64) {
+		dx, dy := x-lastX, y-lastY
+		lastX, lastY = x, y
+		if w.GetMouseButton(glfw.MouseButtonLeft) == glfw.Press {
+			cam.ProcessMouseMovement(dx, dy, true)
+		} else if w.GetMouseButton(glfw.MouseButtonRight) == glfw.Press {
+			// move the target by about the distance the cursor travelled on screen
+			scale := cam.Distance * 0.002
+			cam.Pan(-float32(dx)*scale, float32(dy)*scale)
+		}
+	})
+	window.SetScrollCallback(func(w *glfw.Window, xoffset, yoffset float64) {
+		cam.ProcessMouseScroll(yoffset)
+	})
+	// keep the viewport and the aspect ratio in sync with the framebuffer
+	window.SetFramebufferSizeCallback(func(w *glfw.Window, width, height int) {
+		gl.Viewport(0, 0, int32(width), int32(height))
+		cam.SetViewport(width, height)
+	})
+	cam.SetViewport(window.GetFramebufferSize())
+}
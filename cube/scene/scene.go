@@ -0,0 +1,138 @@
+/*
+纹理立方体场景的顶点数据、变换矩阵与软件渲染版本
+main.go 使用同一份数据通过OpenGL绘制
+*/
+
+package scene
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx"
+	"gfx/camera"
+	"gfx/soft"
+)
+
+const (
+	//SCRWIDTH 窗口宽
+	SCRWIDTH = 800
+	//SCRHEIGHT 窗口高
+	SCRHEIGHT = 600
+)
+
+// 立方体顶点数据: 位置(3) + 纹理坐标(2)
+var Vertices = []float32{
+	-0.5, -0.5, -0.5, 0.0, 0.0,
+	0.5, -0.5, -0.5, 1.0, 0.0,
+	0.5, 0.5, -0.5, 1.0, 1.0,
+	0.5, 0.5, -0.5, 1.0, 1.0,
+	-0.5, 0.5, -0.5, 0.0, 1.0,
+	-0.5, -0.5, -0.5, 0.0, 0.0,
+
+	-0.5, -0.5, 0.5, 0.0, 0.0,
+	0.5, -0.5, 0.5, 1.0, 0.0,
+	0.5, 0.5, 0.5, 1.0, 1.0,
+	0.5, 0.5, 0.5, 1.0, 1.0,
+	-0.5, 0.5, 0.5, 0.0, 1.0,
+	-0.5, -0.5, 0.5, 0.0, 0.0,
+
+	-0.5, 0.5, 0.5, 1.0, 0.0,
+	-0.5, 0.5, -0.5, 1.0, 1.0,
+	-0.5, -0.5, -0.5, 0.0, 1.0,
+	-0.5, -0.5, -0.5, 0.0, 1.0,
+	-0.5, -0.5, 0.5, 0.0, 0.0,
+	-0.5, 0.5, 0.5, 1.0, 0.0,
+
+	0.5, 0.5, 0.5, 1.0, 0.0,
+	0.5, 0.5, -0.5, 1.0, 1.0,
+	0.5, -0.5, -0.5, 0.0, 1.0,
+	0.5, -0.5, -0.5, 0.0, 1.0,
+	0.5, -0.5, 0.5, 0.0, 0.0,
+	0.5, 0.5, 0.5, 1.0, 0.0,
+
+	-0.5, -0.5, -0.5, 0.0, 1.0,
+	0.5, -0.5, -0.5, 1.0, 1.0,
+	0.5, -0.5, 0.5, 1.0, 0.0,
+	0.5, -0.5, 0.5, 1.0, 0.0,
+	-0.5, -0.5, 0.5, 0.0, 0.0,
+	-0.5, -0.5, -0.5, 0.0, 1.0,
+
+	-0.5, 0.5, -0.5, 0.0, 1.0,
+	0.5, 0.5, -0.5, 1.0, 1.0,
+	0.5, 0.5, 0.5, 1.0, 0.0,
+	0.5, 0.5, 0.5, 1.0, 0.0,
+	-0.5, 0.5, 0.5, 0.0, 0.0,
+	-0.5, 0.5, -0.5, 0.0, 1.0,
+}
+
+//NewCamera 初始的轨道摄像机, 从(3,3,3)看向原点, 视角35度
+func NewCamera() *camera.Orbit {
+	cam := camera.NewOrbit(mgl32.Vec3{0, 0, 0}, mgl32.Vec3{3, 3, 3})
+	cam.Zoom = 35.0
+	cam.Far = 10.0
+	cam.SetViewport(SCRWIDTH, SCRHEIGHT)
+	return cam
+}
+
+//View 初始摄像机的观察矩阵
+func View() mgl32.Mat4 {
+	return NewCamera().GetViewMatrix()
+}
+
+//Projection 初始摄像机的投影矩阵
+func Projection() mgl32.Mat4 {
+	return NewCamera().GetProjectionMatrix()
+}
+
+//Model 绕x轴旋转angle弧度的模型矩阵
+func Model(angle float64) mgl32.Mat4 {
+	return mgl32.HomogRotate3D(float32(angle), mgl32.Vec3{1, 0, 0})
+}
+
+//Layout 顶点布局: 位置与纹理坐标交错存放, 对应src/cube.vs的输入
+var Layout = gfx.NewVertexLayout(
+	gfx.FloatAttrib("aPos", 3),
+	gfx.FloatAttrib("aTexCoord", 2),
+)
+
+//Render 使用软件光栅化绘制与main.go相同的一帧
+//texture1, texture2 对应src/1.png与src/12.png, angle 为立方体旋转角度
+func Render(ctx *soft.Context, texture1, texture2 *soft.Texture, angle float64) error {
+	ctx.Enable(soft.DEPTH_TEST)
+
+	VAO := ctx.GenVertexArray()
+	VBO := ctx.GenBuffer()
+	ctx.BindVertexArray(VAO)
+	ctx.BindBuffer(soft.ARRAY_BUFFER, VBO)
+	ctx.BufferData(soft.ARRAY_BUFFER, Vertices)
+	if err := Layout.ApplySoft(ctx); err != nil {
+		return err
+	}
+
+	// 对应src/cube.vs与src/cube.fs
+	mvp := Projection().Mul4(View()).Mul4(Model(angle))
+	program := &soft.Program{
+		Varyings: 2,
+		Vertex: func(in []mgl32.Vec4, out []float32) mgl32.Vec4 {
+			out[0], out[1] = in[1].X(), in[1].Y()
+			return mvp.Mul4x1(in[0])
+		},
+		Fragment: func(in []float32) mgl32.Vec4 {
+			// linearly interpolate between both textures (80% container, 20% awesomeface)
+			c1 := texture1.Sample(in[0], in[1])
+			c2 := texture2.Sample(in[0], in[1])
+			return c1.Mul(0.8).Add(c2.Mul(0.2))
+		},
+	}
+
+	ctx.ClearColor(0.2, 0.3, 0.3, 1.0)
+	ctx.Clear(soft.COLOR_BUFFER_BIT | soft.DEPTH_BUFFER_BIT)
+	ctx.UseProgram(program)
+	ctx.BindVertexArray(VAO)
+	ctx.DrawArrays(soft.TRIANGLES, 0, int32(len(Vertices)/Layout.Floats()))
+	ctx.BindVertexArray(0)
+
+	ctx.DeleteVertexArray(VAO)
+	ctx.DeleteBuffer(VBO)
+	return nil
+}
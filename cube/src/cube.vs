@@ -5,8 +5,11 @@ layout (location = 1) in vec2 aTexCoord;
 out vec2 TexCoord;
 
 uniform mat4 model;
-uniform mat4 view;
-uniform mat4 projection;
+// 相机矩阵由uniform缓冲提供, 见gfx.CameraBlock
+layout (std140) uniform Camera {
+	mat4 view;
+	mat4 projection;
+};
 
 void main()
 {
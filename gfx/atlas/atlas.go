@@ -0,0 +1,228 @@
+/*
+纹理图集
+将多张图片打包进一张纹理, 子图之间留出间隔并把边缘像素向外复制(外扩),
+避免线性过滤或多级渐远纹理采样到相邻的子图; 按名字查询子图的纹理坐标,
+使用同一图集的精灵可以合并到一次绘制中
+*/
+
+package atlas
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx/texture"
+)
+
+//Region 图集中的一张子图
+type Region struct {
+	Name string `json:"name"`
+	// 子图在图集中的像素矩形, 原点为左上角, 不含间隔与外扩
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+
+	// 纹理坐标范围, 与texture包一致, 图集图片的第一行对应v=0
+	UVMin mgl32.Vec2 `json:"-"`
+	UVMax mgl32.Vec2 `json:"-"`
+}
+
+//Atlas 打包好的图集
+type Atlas struct {
+	Image   *image.NRGBA
+	Padding int // 子图之间的间隔像素
+	Extrude int // 子图边缘向外复制的像素
+
+	regions map[string]Region
+}
+
+//Region 按名字查找子图
+func (a *Atlas) Region(name string) (Region, bool) {
+	r, ok := a.regions[name]
+	return r, ok
+}
+
+//Regions 按名字排序的所有子图
+func (a *Atlas) Regions() []Region {
+	regions := make([]Region, 0, len(a.regions))
+	for _, r := range a.regions {
+		regions = append(regions, r)
+	}
+	sort.Slice(regions, func(i, j int) bool { return regions[i].Name < regions[j].Name })
+	return regions
+}
+
+//Texture 将图集上传为纹理
+func (a *Atlas) Texture(opts texture.TextureOptions) (*texture.Texture, error) {
+	return texture.NewTexture(a.Image, opts)
+}
+
+//addRegion 记录子图并计算纹理坐标
+func (a *Atlas) addRegion(r Region) {
+	w, h := float32(a.Image.Rect.Dx()), float32(a.Image.Rect.Dy())
+	r.UVMin = mgl32.Vec2{float32(r.X) / w, float32(r.Y) / h}
+	r.UVMax = mgl32.Vec2{float32(r.X+r.W) / w, float32(r.Y+r.H) / h}
+	if a.regions == nil {
+		a.regions = make(map[string]Region)
+	}
+	a.regions[r.Name] = r
+}
+
+//Builder 收集图片并打包为图集
+type Builder struct {
+	Padding int // 子图之间的间隔像素
+	Extrude int // 子图边缘向外复制的像素, 开启多级渐远纹理时应适当加大
+	MaxSize int // 图集的最大边长, 0时为4096
+
+	images []namedImage
+	names  map[string]bool
+}
+
+type namedImage struct {
+	name string
+	img  image.Image
+}
+
+//NewBuilder 创建图集打包器
+func NewBuilder(padding, extrude int) *Builder {
+	return &Builder{Padding: padding, Extrude: extrude, names: make(map[string]bool)}
+}
+
+//Add 加入一张图片, 名字不能重复
+func (b *Builder) Add(name string, img image.Image) error {
+	if b.names == nil {
+		b.names = make(map[string]bool)
+	}
+	if b.names[name] {
+		return fmt.Errorf("atlas: duplicate image name %q", name)
+	}
+	if img.Bounds().Empty() {
+		return fmt.Errorf("atlas: image %q is empty", name)
+	}
+	b.names[name] = true
+	b.images = append(b.images, namedImage{name, img})
+	return nil
+}
+
+//Build 打包所有图片, 图集的宽高为2的幂, 从能容纳全部面积的最小尺寸开始逐步加大
+func (b *Builder) Build() (*Atlas, error) {
+	maxSize := b.MaxSize
+	if maxSize == 0 {
+		maxSize = 4096
+	}
+	border := 2*b.Extrude + b.Padding
+
+	// 先放高的图片, 同高时按名字排序保证结果稳定
+	order := make([]namedImage, len(b.images))
+	copy(order, b.images)
+	sort.SliceStable(order, func(i, j int) bool {
+		bi, bj := order[i].img.Bounds(), order[j].img.Bounds()
+		if bi.Dy() != bj.Dy() {
+			return bi.Dy() > bj.Dy()
+		}
+		if bi.Dx() != bj.Dx() {
+			return bi.Dx() > bj.Dx()
+		}
+		return order[i].name < order[j].name
+	})
+
+	area, maxW, maxH := 0, 1, 1
+	for _, n := range order {
+		w, h := n.img.Bounds().Dx()+border, n.img.Bounds().Dy()+border
+		area += w * h
+		if w > maxW {
+			maxW = w
+		}
+		if h > maxH {
+			maxH = h
+		}
+	}
+	width, height := nextPowerOfTwo(maxW), nextPowerOfTwo(maxH)
+	for width*height < area {
+		if width <= height {
+			width *= 2
+		} else {
+			height *= 2
+		}
+	}
+
+	for width <= maxSize && height <= maxSize {
+		if places, ok := pack(order, width, height, border); ok {
+			return b.compose(order, places, width, height), nil
+		}
+		if width <= height {
+			width *= 2
+		} else {
+			height *= 2
+		}
+	}
+	return nil, fmt.Errorf("atlas: %d images do not fit in %dx%d", len(order), maxSize, maxSize)
+}
+
+//pack 依次放入每张图片(含间隔与外扩), 返回各自的左上角
+func pack(order []namedImage, width, height, border int) ([]image.Point, bool) {
+	sky := newSkyline(width, height)
+	places := make([]image.Point, len(order))
+	for i, n := range order {
+		x, y, ok := sky.insert(n.img.Bounds().Dx()+border, n.img.Bounds().Dy()+border)
+		if !ok {
+			return nil, false
+		}
+		places[i] = image.Pt(x, y)
+	}
+	return places, true
+}
+
+//compose 把图片绘制到各自的位置并外扩边缘
+func (b *Builder) compose(order []namedImage, places []image.Point, width, height int) *Atlas {
+	a := &Atlas{
+		Image:   image.NewNRGBA(image.Rect(0, 0, width, height)),
+		Padding: b.Padding,
+		Extrude: b.Extrude,
+	}
+	for i, n := range order {
+		bounds := n.img.Bounds()
+		src := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(src, src.Bounds(), n.img, bounds.Min, draw.Src)
+
+		x, y := places[i].X+b.Extrude, places[i].Y+b.Extrude
+		extrude(a.Image, src, x, y, b.Extrude)
+		a.addRegion(Region{Name: n.name, X: x, Y: y, W: bounds.Dx(), H: bounds.Dy()})
+	}
+	return a
+}
+
+//extrude 将src复制到dst的(x, y)处, 四周e个像素取最近的边缘像素
+func extrude(dst, src *image.NRGBA, x, y, e int) {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	for dy := -e; dy < h+e; dy++ {
+		sy := clamp(dy, 0, h-1)
+		for dx := -e; dx < w+e; dx++ {
+			sx := clamp(dx, 0, w-1)
+			copy(dst.Pix[dst.PixOffset(x+dx, y+dy):][:4], src.Pix[src.PixOffset(sx, sy):][:4])
+		}
+	}
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p *= 2
+	}
+	return p
+}
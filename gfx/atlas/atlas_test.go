@@ -0,0 +1,148 @@
+package atlas
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//solid 纯色图片, 颜色由序号决定, 用来检查子图的内容
+func solid(i, w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	c := color.NRGBA{uint8(i * 37), uint8(i * 91), uint8(i * 13), 255}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+//build 打包20张大小不一的图片
+func build(t *testing.T, padding, extrude int) *Atlas {
+	t.Helper()
+	b := NewBuilder(padding, extrude)
+	for i := 0; i < 20; i++ {
+		w, h := 3+i*7%23, 2+i*11%17
+		if err := b.Add(fmt.Sprintf("img%02d", i), solid(i, w, h)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Regions()) != 20 {
+		t.Fatalf("%d regions, want 20", len(a.Regions()))
+	}
+	return a
+}
+
+//TestPack 子图连同外扩与间隔不重叠且不超出图集, 内容与外扩像素正确
+func TestPack(t *testing.T) {
+	for _, tt := range []struct{ padding, extrude int }{{0, 0}, {1, 0}, {2, 1}, {1, 3}} {
+		a := build(t, tt.padding, tt.extrude)
+		bounds := a.Image.Rect
+		regions := a.Regions()
+		for i, r := range regions {
+			// 外扩部分属于子图, 间隔放在右下方
+			outer := image.Rect(r.X-tt.extrude, r.Y-tt.extrude,
+				r.X+r.W+tt.extrude+tt.padding, r.Y+r.H+tt.extrude+tt.padding)
+			if !outer.In(bounds) {
+				t.Errorf("padding %d extrude %d: %s %v outside %v", tt.padding, tt.extrude, r.Name, outer, bounds)
+			}
+			for _, o := range regions[i+1:] {
+				other := image.Rect(o.X-tt.extrude, o.Y-tt.extrude,
+					o.X+o.W+tt.extrude+tt.padding, o.Y+o.H+tt.extrude+tt.padding)
+				if outer.Overlaps(other) {
+					t.Errorf("padding %d extrude %d: %s %v overlaps %s %v",
+						tt.padding, tt.extrude, r.Name, outer, o.Name, other)
+				}
+			}
+
+			var index int
+			fmt.Sscanf(r.Name, "img%d", &index)
+			want := solid(index, 1, 1).NRGBAAt(0, 0)
+			for _, p := range []image.Point{
+				{r.X, r.Y}, {r.X + r.W - 1, r.Y + r.H - 1},
+				{r.X - tt.extrude, r.Y - tt.extrude}, {r.X + r.W - 1 + tt.extrude, r.Y + r.H - 1 + tt.extrude},
+			} {
+				if got := a.Image.NRGBAAt(p.X, p.Y); got != want {
+					t.Errorf("padding %d extrude %d: %s pixel %v = %v, want %v",
+						tt.padding, tt.extrude, r.Name, p, got, want)
+				}
+			}
+		}
+	}
+}
+
+//TestUV 纹理坐标对应子图的像素矩形
+func TestUV(t *testing.T) {
+	a := build(t, 1, 1)
+	w, h := float32(a.Image.Rect.Dx()), float32(a.Image.Rect.Dy())
+	for _, r := range a.Regions() {
+		if r.UVMin.X()*w != float32(r.X) || r.UVMin.Y()*h != float32(r.Y) ||
+			r.UVMax.X()*w != float32(r.X+r.W) || r.UVMax.Y()*h != float32(r.Y+r.H) {
+			t.Errorf("%s: uv %v-%v for %d,%d %dx%d", r.Name, r.UVMin, r.UVMax, r.X, r.Y, r.W, r.H)
+		}
+	}
+}
+
+//TestSaveLoad Save之后Load得到相同的子图、纹理坐标与图片
+func TestSaveLoad(t *testing.T) {
+	a := build(t, 2, 1)
+	file := filepath.Join(t.TempDir(), "sprites.json")
+	if err := a.Save(file); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Padding != a.Padding || loaded.Extrude != a.Extrude {
+		t.Errorf("padding %d extrude %d, want %d and %d", loaded.Padding, loaded.Extrude, a.Padding, a.Extrude)
+	}
+	for _, r := range a.Regions() {
+		got, ok := loaded.Region(r.Name)
+		if !ok {
+			t.Errorf("%s missing after Load", r.Name)
+			continue
+		}
+		if got != r {
+			t.Errorf("%s: loaded %+v, want %+v", r.Name, got, r)
+		}
+	}
+	if len(loaded.Regions()) != len(a.Regions()) {
+		t.Errorf("%d regions loaded, want %d", len(loaded.Regions()), len(a.Regions()))
+	}
+	if !loaded.Image.Rect.Eq(a.Image.Rect) || string(loaded.Image.Pix) != string(a.Image.Pix) {
+		t.Error("loaded image differs from the saved one")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	b := NewBuilder(0, 0)
+	if err := b.Add("a", solid(0, 1, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Add("a", solid(1, 1, 1)); err == nil || !strings.Contains(err.Error(), "duplicate image name") {
+		t.Errorf("duplicate name: error %v", err)
+	}
+	if err := b.Add("empty", image.NewNRGBA(image.Rect(0, 0, 0, 4))); err == nil || !strings.Contains(err.Error(), "is empty") {
+		t.Errorf("empty image: error %v", err)
+	}
+
+	b = NewBuilder(0, 0)
+	b.MaxSize = 16
+	b.Add("big", solid(0, 17, 1))
+	if _, err := b.Build(); err == nil || !strings.Contains(err.Error(), "do not fit in 16x16") {
+		t.Errorf("too big: error %v", err)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil || !strings.Contains(err.Error(), "load atlas file") {
+		t.Errorf("missing file: error %v", err)
+	}
+}
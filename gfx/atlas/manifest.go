@@ -0,0 +1,101 @@
+package atlas
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//manifest 图集的JSON描述文件
+type manifest struct {
+	Image   string   `json:"image"` // 图集图片, 相对于描述文件所在目录
+	Width   int      `json:"width"`
+	Height  int      `json:"height"`
+	Padding int      `json:"padding"`
+	Extrude int      `json:"extrude"`
+	Regions []Region `json:"regions"`
+}
+
+//Save 将图集保存为JSON描述文件file, 图片保存在同目录下的同名.png文件中
+func (a *Atlas) Save(file string) error {
+	pngFile := strings.TrimSuffix(file, filepath.Ext(file)) + ".png"
+	m := manifest{
+		Image:   filepath.Base(pngFile),
+		Width:   a.Image.Rect.Dx(),
+		Height:  a.Image.Rect.Dy(),
+		Padding: a.Padding,
+		Extrude: a.Extrude,
+		Regions: a.Regions(),
+	}
+	data, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(pngFile)
+	if err != nil {
+		return fmt.Errorf("save atlas image %s: %v", pngFile, err)
+	}
+	if err := png.Encode(f, a.Image); err != nil {
+		f.Close()
+		return fmt.Errorf("save atlas image %s: %v", pngFile, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("save atlas image %s: %v", pngFile, err)
+	}
+	if err := ioutil.WriteFile(file, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("save atlas file %s: %v", file, err)
+	}
+	return nil
+}
+
+//Load 读取Save保存的图集
+func Load(file string) (*Atlas, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("load atlas file %s: %v", file, err)
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("load atlas file %s: %v", file, err)
+	}
+
+	pngFile := filepath.Join(filepath.Dir(file), m.Image)
+	f, err := os.Open(pngFile)
+	if err != nil {
+		return nil, fmt.Errorf("load atlas image %s: %v", pngFile, err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("load atlas image %s: %v", pngFile, err)
+	}
+	bounds := img.Bounds()
+	if bounds.Dx() != m.Width || bounds.Dy() != m.Height {
+		return nil, fmt.Errorf("load atlas file %s: image is %dx%d, manifest says %dx%d",
+			file, bounds.Dx(), bounds.Dy(), m.Width, m.Height)
+	}
+
+	a := &Atlas{
+		Image:   image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy())),
+		Padding: m.Padding,
+		Extrude: m.Extrude,
+	}
+	draw.Draw(a.Image, a.Image.Rect, img, bounds.Min, draw.Src)
+	for _, r := range m.Regions {
+		if !image.Rect(r.X, r.Y, r.X+r.W, r.Y+r.H).In(a.Image.Rect) {
+			return nil, fmt.Errorf("load atlas file %s: region %q outside the image", file, r.Name)
+		}
+		if _, dup := a.regions[r.Name]; dup {
+			return nil, fmt.Errorf("load atlas file %s: duplicate region %q", file, r.Name)
+		}
+		a.addRegion(r)
+	}
+	return a, nil
+}
@@ -0,0 +1,94 @@
+package atlas
+
+//skyline 天际线装箱: 记录已占用区域的上轮廓, 每个矩形放在能使其底边最低的位置
+//(图片坐标中y向下, "底边最低"即y+h最小)
+type skyline struct {
+	width, height int
+	nodes         []skylineNode
+}
+
+//skylineNode 轮廓上的一段: 从x开始宽w, 这一段已占用到y
+type skylineNode struct {
+	x, y, w int
+}
+
+func newSkyline(width, height int) *skyline {
+	return &skyline{width: width, height: height, nodes: []skylineNode{{0, 0, width}}}
+}
+
+//insert 放入w*h的矩形, 返回左上角, 放不下时ok为false
+func (s *skyline) insert(w, h int) (x, y int, ok bool) {
+	best, bestY, bestWaste := -1, 0, 0
+	for i := range s.nodes {
+		top, fits := s.fit(i, w, h)
+		if !fits {
+			continue
+		}
+		waste := s.waste(i, w, top)
+		if best < 0 || top+h < bestY+h || (top+h == bestY+h && waste < bestWaste) {
+			best, bestY, bestWaste = i, top, waste
+		}
+	}
+	if best < 0 {
+		return 0, 0, false
+	}
+	x = s.nodes[best].x
+	s.place(best, skylineNode{x, bestY + h, w})
+	return x, bestY, true
+}
+
+//fit 矩形左边对齐第i段时的上边位置(跨过的各段中最高的占用)
+func (s *skyline) fit(i, w, h int) (int, bool) {
+	x := s.nodes[i].x
+	if x+w > s.width {
+		return 0, false
+	}
+	top := 0
+	for j := i; j < len(s.nodes) && s.nodes[j].x < x+w; j++ {
+		if s.nodes[j].y > top {
+			top = s.nodes[j].y
+		}
+	}
+	return top, top+h <= s.height
+}
+
+//waste 矩形下方留下的空隙面积
+func (s *skyline) waste(i, w, top int) int {
+	x, waste := s.nodes[i].x, 0
+	for j := i; j < len(s.nodes) && s.nodes[j].x < x+w; j++ {
+		right := s.nodes[j].x + s.nodes[j].w
+		if right > x+w {
+			right = x + w
+		}
+		waste += (right - s.nodes[j].x) * (top - s.nodes[j].y)
+	}
+	return waste
+}
+
+//place 在第i段处插入新的一段, 截掉被覆盖的部分并合并等高的相邻段
+func (s *skyline) place(i int, node skylineNode) {
+	nodes := append([]skylineNode{}, s.nodes[:i]...)
+	nodes = append(nodes, node)
+	end := node.x + node.w
+	for _, n := range s.nodes[i:] {
+		if n.x+n.w <= end {
+			continue
+		}
+		if n.x < end {
+			n.w -= end - n.x
+			n.x = end
+		}
+		nodes = append(nodes, n)
+	}
+
+	merged := nodes[:1]
+	for _, n := range nodes[1:] {
+		last := &merged[len(merged)-1]
+		if last.y == n.y {
+			last.w += n.w
+			continue
+		}
+		merged = append(merged, n)
+	}
+	s.nodes = merged
+}
@@ -0,0 +1,151 @@
+package camera
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx/ray"
+)
+
+//Arcball 轨迹球摄像机: 始终看向目标点, 朝向保存为四元数,
+//可以越过头顶任意翻转而不会出现万向节锁; 滚轮与键盘的操作与Orbit相同
+type Arcball struct {
+	Target      mgl32.Vec3 //目标点
+	Distance    float32    //到目标点的距离
+	Orientation mgl32.Quat //摄像机空间到世界空间的旋转, 摄像机位于其+Z方向
+
+	MinDistance      float32
+	MaxDistance      float32
+	MovementSpeed    float32
+	MouseSensitivity float32
+	Projection
+}
+
+//NewArcball 创建从eye看向target的轨迹球摄像机, 初始的上方向尽量接近+Y
+func NewArcball(target, eye mgl32.Vec3) *Arcball {
+	a := &Arcball{
+		Target:           target,
+		Distance:         eye.Sub(target).Len(),
+		Orientation:      mgl32.QuatIdent(),
+		MinDistance:      MINDISTANCE,
+		MaxDistance:      MAXDISTANCE,
+		MovementSpeed:    SPEED,
+		MouseSensitivity: SENSITIVITY,
+		Projection:       defaultProjection(),
+	}
+	if a.Distance > 0 {
+		// LookAtV的旋转部分是世界到摄像机空间, 取逆即为摄像机的朝向
+		view := mgl32.LookAtV(eye, target, mgl32.Vec3{0, 1, 0})
+		a.Orientation = mgl32.Mat4ToQuat(view).Inverse().Normalize()
+		a.OrthoHeight = a.heightAt(a.Distance)
+	}
+	return a
+}
+
+//GetViewProjectionMatrix 投影矩阵乘以观察矩阵
+func (a *Arcball) GetViewProjectionMatrix() mgl32.Mat4 {
+	return a.GetProjectionMatrix().Mul4(a.GetViewMatrix())
+}
+
+//Frustum 世界空间的视锥
+func (a *Arcball) Frustum() Frustum {
+	return NewFrustum(a.GetViewProjectionMatrix())
+}
+
+//ScreenPointToRay 窗口坐标(x, y)发出的世界空间射线
+func (a *Arcball) ScreenPointToRay(x, y float64, width, height int) ray.Ray {
+	return screenPointToRay(a.GetViewProjectionMatrix(), x, y, width, height)
+}
+
+//Eye 摄像机的位置
+func (a *Arcball) Eye() mgl32.Vec3 {
+	return a.Target.Add(a.Orientation.Rotate(mgl32.Vec3{0, 0, a.Distance}))
+}
+
+//Up 摄像机的上方向
+func (a *Arcball) Up() mgl32.Vec3 {
+	return a.Orientation.Rotate(mgl32.Vec3{0, 1, 0})
+}
+
+//GetViewMatrix 观察矩阵: 平移到目标点, 旋转到摄像机空间, 再后退Distance
+func (a *Arcball) GetViewMatrix() mgl32.Mat4 {
+	return mgl32.Translate3D(0, 0, -a.Distance).
+		Mul4(a.Orientation.Inverse().Mat4()).
+		Mul4(mgl32.Translate3D(-a.Target.X(), -a.Target.Y(), -a.Target.Z()))
+}
+
+//Rotate 绕摄像机空间中的axis旋转angle弧度, 摄像机随之绕目标点转动
+func (a *Arcball) Rotate(angle float32, axis mgl32.Vec3) {
+	a.Orientation = a.Orientation.Mul(mgl32.QuatRotate(angle, axis)).Normalize()
+}
+
+//Drag 经典轨迹球: from、to 为拖动前后的光标位置, 归一化到[-1,1](y向上),
+//两点投影到以屏幕中心为球心的单位球面上, 场景随光标转过两点之间的角度
+func (a *Arcball) Drag(from, to mgl32.Vec2) {
+	p0, p1 := arcballPoint(from), arcballPoint(to)
+	axis := p0.Cross(p1)
+	if axis.Len() < 1e-6 {
+		return
+	}
+	angle := float32(math.Acos(float64(mgl32.Clamp(p0.Dot(p1), -1, 1))))
+	// 场景转过angle, 相当于摄像机反向转动
+	a.Rotate(-angle, axis.Normalize())
+}
+
+//Pan 在屏幕平面内平移目标点和摄像机, dx向右, dy向上, 单位与世界坐标相同
+func (a *Arcball) Pan(dx, dy float32) {
+	right := a.Orientation.Rotate(mgl32.Vec3{1, 0, 0})
+	a.Target = a.Target.Add(right.Mul(dx)).Add(a.Up().Mul(dy))
+}
+
+//ProcessKeyboard 前后推拉摄像机, 左右平移
+func (a *Arcball) ProcessKeyboard(direction uint32, deltaTime float64) {
+	velocity := a.MovementSpeed * float32(deltaTime)
+	switch direction {
+	case FORWARD:
+		a.dolly(a.Distance - velocity)
+	case BACKWARD:
+		a.dolly(a.Distance + velocity)
+	case LEFT:
+		a.Pan(-velocity, 0)
+	case RIGHT:
+		a.Pan(velocity, 0)
+	}
+}
+
+//ProcessMouseMovement 绕垂直于鼠标移动方向的轴旋转, 转角与移动距离成正比(度),
+//方向与Orbit一致; 没有俯仰角限制, constrainPitch 被忽略
+func (a *Arcball) ProcessMouseMovement(xoffset, yoffset float64, constrainPitch bool) {
+	dx := float32(xoffset) * a.MouseSensitivity
+	dy := float32(yoffset) * a.MouseSensitivity
+	degrees := float32(math.Hypot(float64(dx), float64(dy)))
+	if degrees == 0 {
+		return
+	}
+	a.Rotate(-mgl32.DegToRad(degrees), mgl32.Vec3{dy, dx, 0}.Normalize())
+}
+
+//dolly 把到目标点的距离改为d(限制在[MinDistance, MaxDistance]内),
+//正交投影的视口高度按相同比例缩放, 使两种投影下的缩放效果一致
+func (a *Arcball) dolly(d float32) {
+	d = clampDistance(d, a.MinDistance, a.MaxDistance)
+	if a.Distance > 0 {
+		a.OrthoHeight *= d / a.Distance
+	}
+	a.Distance = d
+}
+
+//ProcessMouseScroll 按比例改变到目标点的距离, 不改变视角
+func (a *Arcball) ProcessMouseScroll(yoffset float64) {
+	a.dolly(a.Distance * (1 - ZOOMSTEP*float32(yoffset)))
+}
+
+//arcballPoint 屏幕上的点投影到轨迹球上, 球外的点落在双曲面上使旋转连续
+func arcballPoint(p mgl32.Vec2) mgl32.Vec3 {
+	d := p.Dot(p)
+	if d <= 0.5 {
+		return mgl32.Vec3{p.X(), p.Y(), float32(math.Sqrt(float64(1 - d)))}.Normalize()
+	}
+	return mgl32.Vec3{p.X(), p.Y(), 0.5 / float32(math.Sqrt(float64(d)))}.Normalize()
+}
@@ -0,0 +1,115 @@
+package camera
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//TestArcballEye 朝向由LookAtV得到, 摄像机位置与观察矩阵都与LookAtV一致
+func TestArcballEye(t *testing.T) {
+	for _, tt := range eyeTests {
+		// 正上方时LookAtV的上方向与视线平行, 没有确定的朝向
+		if tt.eye.X() == tt.target.X() && tt.eye.Z() == tt.target.Z() {
+			continue
+		}
+		a := NewArcball(tt.target, tt.eye)
+		if got := a.Eye(); !near(got, tt.eye) {
+			t.Errorf("NewArcball(%v, %v).Eye() = %v", tt.target, tt.eye, got)
+		}
+		want := mgl32.LookAtV(tt.eye, tt.target, mgl32.Vec3{0, 1, 0})
+		if got := a.GetViewMatrix(); !nearMat(got, want) {
+			t.Errorf("eye %v target %v: view %v, want %v", tt.eye, tt.target, got, want)
+		}
+		// 上方向垂直于视线, 并且尽量接近+Y
+		up, front := a.Up(), tt.target.Sub(tt.eye).Normalize()
+		if math.Abs(float64(up.Dot(front))) > 1e-5 || up.Y() <= 0 {
+			t.Errorf("eye %v target %v: up %v", tt.eye, tt.target, up)
+		}
+	}
+
+	a := NewArcball(mgl32.Vec3{1, 2, 3}, mgl32.Vec3{1, 2, 3})
+	if a.Distance != 0 || a.Eye() != (mgl32.Vec3{1, 2, 3}) || a.Orientation != mgl32.QuatIdent() {
+		t.Errorf("eye at target: distance %v eye %v orientation %v", a.Distance, a.Eye(), a.Orientation)
+	}
+}
+
+//TestArcballRotate 转动只改变摄像机绕目标点的方位, 不改变距离
+func TestArcballRotate(t *testing.T) {
+	target, eye := mgl32.Vec3{1, 0, 0}, mgl32.Vec3{1, 0, 4}
+	a := NewArcball(target, eye)
+	// 绕摄像机空间的X轴转90度后位于目标点正上方, 越过头顶后继续转动
+	a.Rotate(-math.Pi/2, mgl32.Vec3{1, 0, 0})
+	if want := (mgl32.Vec3{1, 4, 0}); !near(a.Eye(), want) {
+		t.Errorf("after 90 degrees: eye %v, want %v", a.Eye(), want)
+	}
+	a.Rotate(-math.Pi/2, mgl32.Vec3{1, 0, 0})
+	if want := (mgl32.Vec3{1, 0, -4}); !near(a.Eye(), want) {
+		t.Errorf("after 180 degrees: eye %v, want %v", a.Eye(), want)
+	}
+	if want := (mgl32.Vec3{0, -1, 0}); !near(a.Up(), want) {
+		t.Errorf("after 180 degrees: up %v, want %v", a.Up(), want)
+	}
+
+	a = NewArcball(target, eye)
+	a.Drag(mgl32.Vec2{0.2, 0.1}, mgl32.Vec2{0.2, 0.1})
+	if !near(a.Eye(), eye) {
+		t.Errorf("drag without movement: eye %v", a.Eye())
+	}
+	a.Drag(mgl32.Vec2{0, 0}, mgl32.Vec2{0.5, -0.3})
+	if d := a.Eye().Sub(target).Len(); math.Abs(float64(d-4)) > 1e-4 {
+		t.Errorf("drag: distance %v, want 4", d)
+	}
+
+	// 水平移动鼠标与Orbit转过相同的方位
+	o := NewOrbit(target, eye)
+	a = NewArcball(target, eye)
+	o.ProcessMouseMovement(200, 0, true)
+	a.ProcessMouseMovement(200, 0, true)
+	if !near(a.Eye(), o.Eye()) {
+		t.Errorf("mouse movement: arcball eye %v, orbit eye %v", a.Eye(), o.Eye())
+	}
+}
+
+func TestArcballDolly(t *testing.T) {
+	a := NewArcball(mgl32.Vec3{}, mgl32.Vec3{0, 0, 3})
+	ratio := a.OrthoHeight / a.Distance
+	for _, step := range []struct {
+		scroll float64
+		want   float32
+	}{
+		{1, 2.7},
+		{1000, MINDISTANCE},
+		{-100, MINDISTANCE * 11},
+		{-100, MINDISTANCE * 121},
+		{-100, MAXDISTANCE},
+	} {
+		a.ProcessMouseScroll(step.scroll)
+		if math.Abs(float64(a.Distance-step.want)) > 1e-4 {
+			t.Errorf("scroll %v: distance %v, want %v", step.scroll, a.Distance, step.want)
+		}
+		if r := a.OrthoHeight / a.Distance; math.Abs(float64(r-ratio)) > 1e-4 {
+			t.Errorf("scroll %v: ortho height %v at distance %v, want ratio %v", step.scroll, a.OrthoHeight, a.Distance, ratio)
+		}
+	}
+	a.ProcessKeyboard(FORWARD, 1000)
+	if a.Distance != MINDISTANCE {
+		t.Errorf("forward past the minimum: distance %v", a.Distance)
+	}
+}
+
+//TestArcballPan 沿摄像机的右方和上方平移, 与Orbit在相同视角下的结果一致
+func TestArcballPan(t *testing.T) {
+	for _, eye := range []mgl32.Vec3{{0, 0, 5}, {5, 0, 0}, {0, 5, 5}, {3, -2, 1}} {
+		a, o := NewArcball(mgl32.Vec3{}, eye), NewOrbit(mgl32.Vec3{}, eye)
+		a.Pan(1, -2)
+		o.Pan(1, -2)
+		if !near(a.Target, o.Target) {
+			t.Errorf("eye %v: arcball target %v, orbit target %v", eye, a.Target, o.Target)
+		}
+		if want := eye.Add(a.Target); !near(a.Eye(), want) {
+			t.Errorf("eye %v: moved the eye to %v, want %v", eye, a.Eye(), want)
+		}
+	}
+}
@@ -1,5 +1,8 @@
 /*
 摄像机
+Camera 为第一人称(FPS)飞行摄像机, Orbit 绕目标点旋转, Arcball 用四元数绕目标点任意旋转(没有万向节锁),
+三者都实现Controller, 输入处理代码可以在运行时切换摄像机;
+投影参数(视角、宽高比、近远平面、正交投影)由嵌入的Projection管理
 */
 
 package camera
@@ -8,6 +11,8 @@ import (
 	"math"
 
 	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx/ray"
 )
 
 // 默认的 camera 值
@@ -27,7 +32,31 @@ const (
 	RIGHT
 )
 
-//Camera 摄像机
+//Controller 由键盘、鼠标输入驱动的摄像机
+type Controller interface {
+	//GetViewMatrix 观察矩阵
+	GetViewMatrix() mgl32.Mat4
+	//GetProjectionMatrix 投影矩阵
+	GetProjectionMatrix() mgl32.Mat4
+	//GetViewProjectionMatrix 投影矩阵乘以观察矩阵
+	GetViewProjectionMatrix() mgl32.Mat4
+	//Lens 投影参数, 可用于切换正交投影或在窗口大小改变时更新宽高比
+	Lens() *Projection
+	//Frustum 世界空间的视锥, 用于剔除不可见的物体
+	Frustum() Frustum
+	//ScreenPointToRay 窗口坐标(x, y)(原点在左上角)发出的世界空间射线, width, height 为窗口大小
+	ScreenPointToRay(x, y float64, width, height int) ray.Ray
+	//Eye 摄像机在世界空间中的位置
+	Eye() mgl32.Vec3
+	//ProcessKeyboard 按方向(FORWARD等)移动deltaTime秒
+	ProcessKeyboard(direction uint32, deltaTime float64)
+	//ProcessMouseMovement 鼠标移动了(xoffset, yoffset)像素
+	ProcessMouseMovement(xoffset, yoffset float64, constrainPitch bool)
+	//ProcessMouseScroll 滚轮滚动了yoffset
+	ProcessMouseScroll(yoffset float64)
+}
+
+//Camera 第一人称摄像机
 type Camera struct {
 	Position mgl32.Vec3 //摄像头位置
 	Front    mgl32.Vec3 //摄像头方向
@@ -40,7 +69,7 @@ type Camera struct {
 
 	MovementSpeed    float32
 	MouseSensitivity float32
-	Zoom             float32
+	Projection
 }
 
 //GetCamera Camera的构造函数
@@ -53,7 +82,7 @@ func GetCamera(pos mgl32.Vec3) *Camera {
 		Front:            mgl32.Vec3{0.0, 0.0, -1.0},
 		MovementSpeed:    SPEED,
 		MouseSensitivity: SENSITIVITY,
-		Zoom:             ZOOM,
+		Projection:       defaultProjection(),
 		Position:         pos,
 		WorldUp:          mgl32.Vec3{0.0, 1.0, 0.0},
 		Yaw:              YAW,
@@ -69,6 +98,26 @@ func (c *Camera) GetViewMatrix() mgl32.Mat4 {
 	return mgl32.LookAtV(c.Position, c.Position.Add(c.Front), c.Up)
 }
 
+//GetViewProjectionMatrix 投影矩阵乘以观察矩阵
+func (c *Camera) GetViewProjectionMatrix() mgl32.Mat4 {
+	return c.GetProjectionMatrix().Mul4(c.GetViewMatrix())
+}
+
+//Frustum 世界空间的视锥
+func (c *Camera) Frustum() Frustum {
+	return NewFrustum(c.GetViewProjectionMatrix())
+}
+
+//ScreenPointToRay 窗口坐标(x, y)发出的世界空间射线
+func (c *Camera) ScreenPointToRay(x, y float64, width, height int) ray.Ray {
+	return screenPointToRay(c.GetViewProjectionMatrix(), x, y, width, height)
+}
+
+//Eye 返回c.Position
+func (c *Camera) Eye() mgl32.Vec3 {
+	return c.Position
+}
+
 //ProcessKeyboard 对应键盘移动事件
 func (c *Camera) ProcessKeyboard(direction uint32, deltaTime float64) {
 	velocity := c.MovementSpeed * float32(deltaTime)
@@ -102,7 +151,7 @@ func (c *Camera) ProcessMouseMovement(xoffset, yoffset float64, constrainPitch b
 	c.updateCameraVectors()
 }
 
-//ProcessMouseScroll 对应鼠标滚轮事件
+//ProcessMouseScroll 对应鼠标滚轮事件, 改变视角Zoom
 func (c *Camera) ProcessMouseScroll(yoffset float64) {
 	if c.Zoom >= 1.0 && c.Zoom <= 45.0 {
 		c.Zoom -= float32(yoffset)
@@ -0,0 +1,146 @@
+package camera
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//Containment 包围体与视锥的相交测试结果
+type Containment int
+
+// 相交测试结果
+const (
+	Outside      Containment = iota //完全在视锥外, 可以跳过绘制
+	Intersecting                    //与视锥边界相交
+	Inside                          //完全在视锥内
+)
+
+func (c Containment) String() string {
+	switch c {
+	case Outside:
+		return "outside"
+	case Intersecting:
+		return "intersecting"
+	case Inside:
+		return "inside"
+	}
+	return "unknown"
+}
+
+// 视锥平面的下标
+const (
+	LeftPlane = iota
+	RightPlane
+	BottomPlane
+	TopPlane
+	NearPlane
+	FarPlane
+)
+
+//Plane 平面 Normal·p + D = 0, Normal为单位向量且指向视锥内部
+type Plane struct {
+	Normal mgl32.Vec3
+	D      float32
+}
+
+//Distance 点到平面的有向距离, 在视锥内一侧为正
+func (p Plane) Distance(point mgl32.Vec3) float32 {
+	return p.Normal.Dot(point) + p.D
+}
+
+//Frustum 视锥的6个平面, 顺序为左、右、下、上、近、远
+//相交测试只逐个比较平面, 对角附近在视锥外的包围体可能报告为Intersecting(保守), 不会把可见物体判为Outside
+type Frustum struct {
+	Planes [6]Plane
+}
+
+//NewFrustum 从投影矩阵乘以观察矩阵中提取世界空间的视锥平面(裁剪空间z为[-w, w])
+//传入 投影*观察*模型 矩阵时得到模型空间的视锥
+func NewFrustum(viewProjection mgl32.Mat4) Frustum {
+	r0, r1, r2, r3 := viewProjection.Row(0), viewProjection.Row(1), viewProjection.Row(2), viewProjection.Row(3)
+	rows := [6]mgl32.Vec4{
+		r3.Add(r0), r3.Sub(r0),
+		r3.Add(r1), r3.Sub(r1),
+		r3.Add(r2), r3.Sub(r2),
+	}
+	var f Frustum
+	for i, r := range rows {
+		n := r.Vec3()
+		l := n.Len()
+		f.Planes[i] = Plane{Normal: n.Mul(1 / l), D: r.W() / l}
+	}
+	return f
+}
+
+//ContainsPoint 点是否在视锥内(含边界)
+func (f *Frustum) ContainsPoint(point mgl32.Vec3) bool {
+	for _, p := range f.Planes {
+		if p.Distance(point) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+//TestSphere 球心center、半径radius的包围球
+func (f *Frustum) TestSphere(center mgl32.Vec3, radius float32) Containment {
+	result := Inside
+	for _, p := range f.Planes {
+		d := p.Distance(center)
+		if d < -radius {
+			return Outside
+		}
+		if d < radius {
+			result = Intersecting
+		}
+	}
+	return result
+}
+
+//TestAABB 世界空间中与坐标轴对齐的包围盒[min, max]
+func (f *Frustum) TestAABB(min, max mgl32.Vec3) Containment {
+	center := min.Add(max).Mul(0.5)
+	half := max.Sub(min).Mul(0.5)
+	return f.testBox(center, func(n mgl32.Vec3) float32 {
+		return half.X()*abs(n.X()) + half.Y()*abs(n.Y()) + half.Z()*abs(n.Z())
+	})
+}
+
+//TestOBB 有向包围盒: 中心center, halfAxes的三列为盒子三个方向的半轴(含长度)
+func (f *Frustum) TestOBB(center mgl32.Vec3, halfAxes mgl32.Mat3) Containment {
+	a0, a1, a2 := halfAxes.Col(0), halfAxes.Col(1), halfAxes.Col(2)
+	return f.testBox(center, func(n mgl32.Vec3) float32 {
+		return abs(n.Dot(a0)) + abs(n.Dot(a1)) + abs(n.Dot(a2))
+	})
+}
+
+//TestBox 模型空间的包围盒[min, max]经过model变换后得到的有向包围盒,
+//常用于用网格的局部包围盒和模型矩阵判断物体是否可见
+func (f *Frustum) TestBox(min, max mgl32.Vec3, model mgl32.Mat4) Containment {
+	center := model.Mul4x1(min.Add(max).Mul(0.5).Vec4(1)).Vec3()
+	half := max.Sub(min).Mul(0.5)
+	halfAxes := model.Mat3().Mul3(mgl32.Diag3(half))
+	return f.TestOBB(center, halfAxes)
+}
+
+//testBox 中心为center的盒子, radius 返回盒子在法线n方向上的投影半径
+func (f *Frustum) testBox(center mgl32.Vec3, radius func(n mgl32.Vec3) float32) Containment {
+	result := Inside
+	for _, p := range f.Planes {
+		d := p.Distance(center)
+		r := radius(p.Normal)
+		if d < -r {
+			return Outside
+		}
+		if d < r {
+			result = Intersecting
+		}
+	}
+	return result
+}
+
+func abs(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
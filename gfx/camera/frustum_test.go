@@ -0,0 +1,135 @@
+package camera
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//testFrustum 90°视角、宽高比1、近平面1、远平面100, 摄像机在(0, 0, 10)看向原点,
+//z=0处视锥的横截面为[-10, 10]x[-10, 10]
+func testFrustum() Frustum {
+	projection := mgl32.Perspective(mgl32.DegToRad(90), 1, 1, 100)
+	view := mgl32.LookAtV(mgl32.Vec3{0, 0, 10}, mgl32.Vec3{}, mgl32.Vec3{0, 1, 0})
+	return NewFrustum(projection.Mul4(view))
+}
+
+func TestFrustumPlanes(t *testing.T) {
+	f := testFrustum()
+	s := float32(1 / math.Sqrt2)
+	want := [6]Plane{
+		LeftPlane:   {mgl32.Vec3{s, 0, -s}, 10 * s},
+		RightPlane:  {mgl32.Vec3{-s, 0, -s}, 10 * s},
+		BottomPlane: {mgl32.Vec3{0, s, -s}, 10 * s},
+		TopPlane:    {mgl32.Vec3{0, -s, -s}, 10 * s},
+		NearPlane:   {mgl32.Vec3{0, 0, -1}, 9},
+		FarPlane:    {mgl32.Vec3{0, 0, 1}, 90},
+	}
+	for i, p := range f.Planes {
+		if !p.Normal.ApproxEqualThreshold(want[i].Normal, 1e-5) || math.Abs(float64(p.D-want[i].D)) > 1e-3 {
+			t.Errorf("plane %d = %v, want %v", i, p, want[i])
+		}
+	}
+}
+
+func TestContainsPoint(t *testing.T) {
+	f := testFrustum()
+	tests := []struct {
+		point mgl32.Vec3
+		want  bool
+	}{
+		{mgl32.Vec3{0, 0, 0}, true},
+		{mgl32.Vec3{9.9, -9.9, 0}, true},
+		{mgl32.Vec3{0, 0, -89.9}, true},
+		{mgl32.Vec3{10.1, 0, 0}, false},
+		{mgl32.Vec3{0, 0, 9.5}, false},   // 近平面之前
+		{mgl32.Vec3{0, 0, -90.5}, false}, // 远平面之后
+		{mgl32.Vec3{0, 0, 20}, false},    // 摄像机背后
+	}
+	for _, tt := range tests {
+		if got := f.ContainsPoint(tt.point); got != tt.want {
+			t.Errorf("ContainsPoint(%v) = %v, want %v", tt.point, got, tt.want)
+		}
+	}
+}
+
+func TestSphere(t *testing.T) {
+	f := testFrustum()
+	tests := []struct {
+		center mgl32.Vec3
+		radius float32
+		want   Containment
+	}{
+		{mgl32.Vec3{0, 0, 0}, 1, Inside},
+		{mgl32.Vec3{0, 0, -50}, 30, Inside},
+		{mgl32.Vec3{0, 0, 0}, 100, Intersecting},
+		{mgl32.Vec3{10, 0, 0}, 0.5, Intersecting}, // 跨过右平面
+		{mgl32.Vec3{0, 0, -90}, 1, Intersecting},  // 跨过远平面
+		{mgl32.Vec3{0, 0, 8.5}, 1, Intersecting},  // 跨过近平面
+		{mgl32.Vec3{0, 12, 0}, 1, Outside},        // 上平面外, 距离√2
+		{mgl32.Vec3{0, 0, 9.5}, 0.4, Outside},     // 近平面之前
+		{mgl32.Vec3{0, 0, -92}, 1, Outside},       // 远平面之后
+		{mgl32.Vec3{-30, 0, 20}, 5, Outside},      // 摄像机背后
+	}
+	for _, tt := range tests {
+		if got := f.TestSphere(tt.center, tt.radius); got != tt.want {
+			t.Errorf("TestSphere(%v, %v) = %v, want %v", tt.center, tt.radius, got, tt.want)
+		}
+	}
+}
+
+func TestAABB(t *testing.T) {
+	f := testFrustum()
+	tests := []struct {
+		min, max mgl32.Vec3
+		want     Containment
+	}{
+		{mgl32.Vec3{-1, -1, -1}, mgl32.Vec3{1, 1, 1}, Inside},
+		{mgl32.Vec3{-5, -5, -40}, mgl32.Vec3{5, 5, -20}, Inside},
+		{mgl32.Vec3{8, -1, -1}, mgl32.Vec3{12, 1, 1}, Intersecting},
+		{mgl32.Vec3{-1, -1, -100}, mgl32.Vec3{1, 1, -50}, Intersecting},
+		{mgl32.Vec3{-100, -100, -100}, mgl32.Vec3{100, 100, 100}, Intersecting},
+		{mgl32.Vec3{12, -1, -1}, mgl32.Vec3{14, 1, 1}, Outside},
+		{mgl32.Vec3{-1, -1, 9.2}, mgl32.Vec3{1, 1, 9.8}, Outside},
+		{mgl32.Vec3{-1, -1, -120}, mgl32.Vec3{1, 1, -95}, Outside},
+	}
+	for _, tt := range tests {
+		if got := f.TestAABB(tt.min, tt.max); got != tt.want {
+			t.Errorf("TestAABB(%v, %v) = %v, want %v", tt.min, tt.max, got, tt.want)
+		}
+	}
+}
+
+//TestBox 有向包围盒按变换后的半轴计算投影半径: 绕Y轴旋转45°后在右平面方向上反而比轴对齐时小
+func TestBox(t *testing.T) {
+	f := testFrustum()
+	min, max := mgl32.Vec3{-1, -1, -1}, mgl32.Vec3{1, 1, 1}
+	rotate := mgl32.HomogRotate3DY(mgl32.DegToRad(45))
+	tests := []struct {
+		name  string
+		model mgl32.Mat4
+		want  Containment
+	}{
+		{"identity", mgl32.Ident4(), Inside},
+		{"rotated at center", rotate, Inside},
+		// 中心到右平面的距离为(10-8.8)/√2≈0.85, 轴对齐时投影半径为√2, 旋转45°后为1
+		{"near the right plane", mgl32.Translate3D(8.8, 0, 0), Intersecting},
+		{"rotated near the right plane", mgl32.Translate3D(8.8, 0, 0).Mul4(rotate), Intersecting},
+		{"outside", mgl32.Translate3D(13, 0, 0), Outside},
+		{"rotated just outside", mgl32.Translate3D(11.5, 0, 0).Mul4(rotate), Outside},
+		{"aligned at the same place", mgl32.Translate3D(11.5, 0, 0), Intersecting},
+		{"scaled across", mgl32.Translate3D(12, 0, 0).Mul4(mgl32.Scale3D(3, 1, 1)), Intersecting},
+	}
+	for _, tt := range tests {
+		if got := f.TestBox(min, max, tt.model); got != tt.want {
+			t.Errorf("%s: TestBox = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	center := mgl32.Vec3{11.5, 0, 0}
+	halfAxes := rotate.Mat3()
+	if got := f.TestOBB(center, halfAxes); got != Outside {
+		t.Errorf("TestOBB(%v) = %v, want outside", center, got)
+	}
+}
@@ -0,0 +1,151 @@
+package camera
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx/ray"
+)
+
+// 轨道摄像机的默认值
+const (
+	MINDISTANCE = 0.1   //默认的最近距离
+	MAXDISTANCE = 100.0 //默认的最远距离
+	ZOOMSTEP    = 0.1   //滚轮每格改变距离的比例
+)
+
+//Orbit 轨道摄像机: 始终看向目标点, 鼠标移动绕目标旋转, 滚轮改变距离, 键盘左右平移、前后推拉
+//Yaw 为偏航角, Pitch 为俯仰角(度), 描述摄像机相对目标点的方位, Y轴向上
+type Orbit struct {
+	Target   mgl32.Vec3 //目标点
+	Distance float32    //到目标点的距离
+	Yaw      float32
+	Pitch    float32
+
+	MinDistance      float32
+	MaxDistance      float32
+	MovementSpeed    float32
+	MouseSensitivity float32
+	Projection
+}
+
+//NewOrbit 创建从eye看向target的轨道摄像机
+func NewOrbit(target, eye mgl32.Vec3) *Orbit {
+	o := &Orbit{
+		Target:           target,
+		MinDistance:      MINDISTANCE,
+		MaxDistance:      MAXDISTANCE,
+		MovementSpeed:    SPEED,
+		MouseSensitivity: SENSITIVITY,
+		Projection:       defaultProjection(),
+	}
+	o.SetEye(eye)
+	o.OrthoHeight = o.heightAt(o.Distance)
+	return o
+}
+
+//SetEye 把摄像机移到eye, 保持目标点不变
+func (o *Orbit) SetEye(eye mgl32.Vec3) {
+	offset := eye.Sub(o.Target)
+	o.Distance = offset.Len()
+	if o.Distance == 0 {
+		return
+	}
+	o.Pitch = mgl32.RadToDeg(float32(math.Asin(float64(offset.Y() / o.Distance))))
+	o.Yaw = mgl32.RadToDeg(float32(math.Atan2(float64(offset.Z()), float64(offset.X()))))
+}
+
+//GetViewProjectionMatrix 投影矩阵乘以观察矩阵
+func (o *Orbit) GetViewProjectionMatrix() mgl32.Mat4 {
+	return o.GetProjectionMatrix().Mul4(o.GetViewMatrix())
+}
+
+//Frustum 世界空间的视锥
+func (o *Orbit) Frustum() Frustum {
+	return NewFrustum(o.GetViewProjectionMatrix())
+}
+
+//ScreenPointToRay 窗口坐标(x, y)发出的世界空间射线
+func (o *Orbit) ScreenPointToRay(x, y float64, width, height int) ray.Ray {
+	return screenPointToRay(o.GetViewProjectionMatrix(), x, y, width, height)
+}
+
+//Eye 摄像机的位置
+func (o *Orbit) Eye() mgl32.Vec3 {
+	return o.Target.Add(o.offset())
+}
+
+//GetViewMatrix 从Eye看向Target的观察矩阵
+func (o *Orbit) GetViewMatrix() mgl32.Mat4 {
+	return mgl32.LookAtV(o.Eye(), o.Target, mgl32.Vec3{0, 1, 0})
+}
+
+//Pan 在屏幕平面内平移目标点和摄像机, dx向右, dy向上, 单位与世界坐标相同
+func (o *Orbit) Pan(dx, dy float32) {
+	front := o.offset().Mul(-1).Normalize()
+	right := front.Cross(mgl32.Vec3{0, 1, 0}).Normalize()
+	up := right.Cross(front)
+	o.Target = o.Target.Add(right.Mul(dx)).Add(up.Mul(dy))
+}
+
+//ProcessKeyboard 前后推拉摄像机, 左右平移
+func (o *Orbit) ProcessKeyboard(direction uint32, deltaTime float64) {
+	velocity := o.MovementSpeed * float32(deltaTime)
+	switch direction {
+	case FORWARD:
+		o.dolly(o.Distance - velocity)
+	case BACKWARD:
+		o.dolly(o.Distance + velocity)
+	case LEFT:
+		o.Pan(-velocity, 0)
+	case RIGHT:
+		o.Pan(velocity, 0)
+	}
+}
+
+//ProcessMouseMovement 绕目标点旋转, 俯仰角总是限制在±89度以内, 避免越过头顶时翻转
+func (o *Orbit) ProcessMouseMovement(xoffset, yoffset float64, constrainPitch bool) {
+	o.Yaw += float32(xoffset) * o.MouseSensitivity
+	o.Pitch += float32(yoffset) * o.MouseSensitivity
+	if o.Pitch > 89.0 {
+		o.Pitch = 89.0
+	} else if o.Pitch < -89.0 {
+		o.Pitch = -89.0
+	}
+}
+
+//dolly 把到目标点的距离改为d(限制在[MinDistance, MaxDistance]内),
+//正交投影的视口高度按相同比例缩放, 使两种投影下的缩放效果一致
+func (o *Orbit) dolly(d float32) {
+	d = clampDistance(d, o.MinDistance, o.MaxDistance)
+	if o.Distance > 0 {
+		o.OrthoHeight *= d / o.Distance
+	}
+	o.Distance = d
+}
+
+//ProcessMouseScroll 按比例改变到目标点的距离, 不改变视角
+func (o *Orbit) ProcessMouseScroll(yoffset float64) {
+	o.dolly(o.Distance * (1 - ZOOMSTEP*float32(yoffset)))
+}
+
+//offset 目标点指向摄像机的向量
+func (o *Orbit) offset() mgl32.Vec3 {
+	yaw, pitch := float64(mgl32.DegToRad(o.Yaw)), float64(mgl32.DegToRad(o.Pitch))
+	return mgl32.Vec3{
+		float32(math.Cos(yaw) * math.Cos(pitch)),
+		float32(math.Sin(pitch)),
+		float32(math.Sin(yaw) * math.Cos(pitch)),
+	}.Mul(o.Distance)
+}
+
+func clampDistance(d, min, max float32) float32 {
+	if d < min {
+		return min
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
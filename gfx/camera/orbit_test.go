@@ -0,0 +1,162 @@
+package camera
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//eyeTests 目标点与摄像机位置, 包括不在原点的目标和位于目标正上方的摄像机
+var eyeTests = []struct {
+	target, eye mgl32.Vec3
+}{
+	{mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 0, 3}},
+	{mgl32.Vec3{0, 0, 0}, mgl32.Vec3{-4, 0, 0}},
+	{mgl32.Vec3{1, 2, 3}, mgl32.Vec3{4, 6, -2}},
+	{mgl32.Vec3{-5, 0, 2}, mgl32.Vec3{-5.5, -3, 1}},
+	{mgl32.Vec3{0, 1, 0}, mgl32.Vec3{0, 6, 0}},
+}
+
+//near 两个向量的各分量之差都不超过1e-4
+func near(a, b mgl32.Vec3) bool {
+	for i := range a {
+		if math.Abs(float64(a[i]-b[i])) > 1e-4 {
+			return false
+		}
+	}
+	return true
+}
+
+//nearMat 两个矩阵的各元素之差都不超过1e-4
+func nearMat(a, b mgl32.Mat4) bool {
+	for i := range a {
+		if math.Abs(float64(a[i]-b[i])) > 1e-4 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestOrbitEye(t *testing.T) {
+	for _, tt := range eyeTests {
+		o := NewOrbit(tt.target, tt.eye)
+		if got := o.Eye(); !near(got, tt.eye) {
+			t.Errorf("NewOrbit(%v, %v).Eye() = %v", tt.target, tt.eye, got)
+		}
+		if d := tt.eye.Sub(tt.target).Len(); math.Abs(float64(o.Distance-d)) > 1e-5 {
+			t.Errorf("NewOrbit(%v, %v): distance %v, want %v", tt.target, tt.eye, o.Distance, d)
+		}
+
+		o = NewOrbit(tt.target, tt.target.Add(mgl32.Vec3{1, 1, 1}))
+		o.SetEye(tt.eye)
+		if got := o.Eye(); !near(got, tt.eye) {
+			t.Errorf("SetEye(%v) with target %v: Eye() = %v", tt.eye, tt.target, got)
+		}
+		// 正上方时LookAtV的上方向与视线平行, 不比较观察矩阵
+		if tt.eye.X() == tt.target.X() && tt.eye.Z() == tt.target.Z() {
+			continue
+		}
+		want := mgl32.LookAtV(tt.eye, tt.target, mgl32.Vec3{0, 1, 0})
+		if got := o.GetViewMatrix(); !nearMat(got, want) {
+			t.Errorf("eye %v target %v: view %v, want %v", tt.eye, tt.target, got, want)
+		}
+	}
+
+	// 摄像机与目标点重合时保持原来的角度
+	o := NewOrbit(mgl32.Vec3{}, mgl32.Vec3{0, 0, 3})
+	o.SetEye(mgl32.Vec3{})
+	if o.Distance != 0 || o.Eye() != (mgl32.Vec3{}) || o.Yaw != 90 || o.Pitch != 0 {
+		t.Errorf("eye at target: distance %v eye %v yaw %v pitch %v", o.Distance, o.Eye(), o.Yaw, o.Pitch)
+	}
+}
+
+//TestOrbitPitch 不论constrainPitch, 俯仰角都限制在±89度以内
+func TestOrbitPitch(t *testing.T) {
+	for _, constrain := range []bool{true, false} {
+		o := NewOrbit(mgl32.Vec3{}, mgl32.Vec3{0, 0, 3})
+		o.ProcessMouseMovement(0, 300, constrain)
+		if o.Pitch != 30 {
+			t.Errorf("constrain %v: pitch %v after 30 degrees, want 30", constrain, o.Pitch)
+		}
+		o.ProcessMouseMovement(100, 1000, constrain)
+		if o.Pitch != 89 || o.Yaw != 100 {
+			t.Errorf("constrain %v: pitch %v yaw %v, want 89 100", constrain, o.Pitch, o.Yaw)
+		}
+		if eye := o.Eye(); eye.Y() >= o.Distance || eye.Y() < 2.99 {
+			t.Errorf("constrain %v: eye %v at the top", constrain, eye)
+		}
+		o.ProcessMouseMovement(0, -5000, constrain)
+		if o.Pitch != -89 {
+			t.Errorf("constrain %v: pitch %v, want -89", constrain, o.Pitch)
+		}
+	}
+}
+
+//TestOrbitDolly 距离限制在[MinDistance, MaxDistance]内, 正交视口高度与距离成比例
+func TestOrbitDolly(t *testing.T) {
+	o := NewOrbit(mgl32.Vec3{}, mgl32.Vec3{0, 0, 3})
+	ratio := o.OrthoHeight / o.Distance
+	check := func(name string, want float32) {
+		t.Helper()
+		if math.Abs(float64(o.Distance-want)) > 1e-4 {
+			t.Errorf("%s: distance %v, want %v", name, o.Distance, want)
+		}
+		if r := o.OrthoHeight / o.Distance; math.Abs(float64(r-ratio)) > 1e-4 {
+			t.Errorf("%s: ortho height %v at distance %v, want ratio %v", name, o.OrthoHeight, o.Distance, ratio)
+		}
+	}
+
+	o.ProcessMouseScroll(1)
+	check("scroll in", 2.7)
+	o.ProcessMouseScroll(-2)
+	check("scroll out", 2.7*1.2)
+	for i := 0; i < 100; i++ {
+		o.ProcessMouseScroll(5)
+	}
+	check("scroll to the minimum", MINDISTANCE)
+	o.ProcessKeyboard(BACKWARD, 1)
+	check("backward", MINDISTANCE+SPEED)
+	o.ProcessKeyboard(BACKWARD, 1000)
+	check("backward past the maximum", MAXDISTANCE)
+	o.ProcessKeyboard(FORWARD, 1000)
+	check("forward past the minimum", MINDISTANCE)
+
+	o.MinDistance, o.MaxDistance = 2, 4
+	o.ProcessMouseScroll(1)
+	check("custom minimum", 2)
+	o.ProcessKeyboard(BACKWARD, 1000)
+	check("custom maximum", 4)
+}
+
+//TestOrbitPan 目标点与摄像机沿屏幕的右方和上方一起移动, 视线方向与距离不变
+func TestOrbitPan(t *testing.T) {
+	tests := []struct {
+		eye    mgl32.Vec3
+		dx, dy float32
+		target mgl32.Vec3
+	}{
+		{mgl32.Vec3{0, 0, 5}, 1, 2, mgl32.Vec3{1, 2, 0}},
+		// 从+X看向原点时右方是-Z
+		{mgl32.Vec3{5, 0, 0}, 1, -1, mgl32.Vec3{0, -1, -1}},
+		// 从斜上方看时上方向随视线倾斜
+		{mgl32.Vec3{0, 5, 5}, 0, 1, mgl32.Vec3{0, 1 / math.Sqrt2, -1 / math.Sqrt2}},
+	}
+	for _, tt := range tests {
+		o := NewOrbit(mgl32.Vec3{}, tt.eye)
+		distance := o.Distance
+		o.Pan(tt.dx, tt.dy)
+		if !near(o.Target, tt.target) {
+			t.Errorf("eye %v: Pan(%v, %v) moved the target to %v, want %v", tt.eye, tt.dx, tt.dy, o.Target, tt.target)
+		}
+		if want := tt.eye.Add(tt.target); !near(o.Eye(), want) || o.Distance != distance {
+			t.Errorf("eye %v: Pan(%v, %v) moved the eye to %v at distance %v, want %v at %v", tt.eye, tt.dx, tt.dy, o.Eye(), o.Distance, want, distance)
+		}
+	}
+
+	o := NewOrbit(mgl32.Vec3{}, mgl32.Vec3{0, 0, 5})
+	o.ProcessKeyboard(LEFT, 0.4)
+	if want := (mgl32.Vec3{-1, 0, 0}); !near(o.Target, want) {
+		t.Errorf("keyboard left: target %v, want %v", o.Target, want)
+	}
+}
@@ -0,0 +1,77 @@
+package camera
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx/ray"
+)
+
+// 默认的投影参数
+const (
+	NEAR        = 0.1   //默认的近平面
+	FAR         = 100.0 //默认的远平面
+	ORTHOHEIGHT = 2.0   //默认的正交投影视口高度
+)
+
+//Projection 摄像机的投影参数, 嵌入在各摄像机中
+type Projection struct {
+	Zoom         float32 //透视投影的垂直视角(度)
+	Aspect       float32 //视口宽高比
+	Near         float32 //近平面距离
+	Far          float32 //远平面距离
+	Orthographic bool    //是否使用正交投影
+	OrthoHeight  float32 //正交投影时视口对应的世界空间高度, 宽度按Aspect计算
+}
+
+//defaultProjection 默认的透视投影, 宽高比为1
+func defaultProjection() Projection {
+	return Projection{
+		Zoom:        ZOOM,
+		Aspect:      1.0,
+		Near:        NEAR,
+		Far:         FAR,
+		OrthoHeight: ORTHOHEIGHT,
+	}
+}
+
+//GetProjectionMatrix 投影矩阵, 透视投影的视角由Zoom决定
+func (p *Projection) GetProjectionMatrix() mgl32.Mat4 {
+	if p.Orthographic {
+		h := p.OrthoHeight / 2
+		w := h * p.Aspect
+		return mgl32.Ortho(-w, w, -h, h, p.Near, p.Far)
+	}
+	return mgl32.Perspective(mgl32.DegToRad(p.Zoom), p.Aspect, p.Near, p.Far)
+}
+
+//SetViewport 按视口(帧缓冲)大小更新宽高比, 窗口最小化时大小为0, 保持原值
+func (p *Projection) SetViewport(width, height int) {
+	if width > 0 && height > 0 {
+		p.Aspect = float32(width) / float32(height)
+	}
+}
+
+//Lens 返回投影参数本身, 通过Controller修改投影时使用
+func (p *Projection) Lens() *Projection {
+	return p
+}
+
+//screenPointToRay 把窗口坐标反投影到近平面和远平面, 得到从近平面出发的射线
+//透视投影时射线经过摄像机位置, 正交投影时所有射线平行
+func screenPointToRay(viewProjection mgl32.Mat4, x, y float64, width, height int) ray.Ray {
+	ndcX := float32(2*x/float64(width) - 1)
+	ndcY := float32(1 - 2*y/float64(height))
+	inv := viewProjection.Inv()
+	near := inv.Mul4x1(mgl32.Vec4{ndcX, ndcY, -1, 1})
+	far := inv.Mul4x1(mgl32.Vec4{ndcX, ndcY, 1, 1})
+	origin := near.Vec3().Mul(1 / near.W())
+	target := far.Vec3().Mul(1 / far.W())
+	return ray.Ray{Origin: origin, Direction: target.Sub(origin).Normalize()}
+}
+
+//heightAt 透视投影在距离d处的视口高度, 用于让正交投影与透视投影的大小一致
+func (p *Projection) heightAt(d float32) float32 {
+	return 2 * d * float32(math.Tan(float64(mgl32.DegToRad(p.Zoom))/2))
+}
@@ -0,0 +1,243 @@
+package gltf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// accessor 的分量类型, 取值与OpenGL常量相同
+const (
+	BYTE           = 5120
+	UNSIGNED_BYTE  = 5121
+	SHORT          = 5122
+	UNSIGNED_SHORT = 5123
+	UNSIGNED_INT   = 5125
+	FLOAT          = 5126
+)
+
+//maxZeroValues 没有bufferView的accessor最多展开的分量个数
+//这种accessor的数据全为0(或由sparse覆盖), 文件中没有对应的数据限制count, 需要单独设置上限
+const maxZeroValues = 1 << 24
+
+//componentSize 分量类型占用的字节数
+func componentSize(componentType uint32) int {
+	switch componentType {
+	case BYTE, UNSIGNED_BYTE:
+		return 1
+	case SHORT, UNSIGNED_SHORT:
+		return 2
+	case UNSIGNED_INT, FLOAT:
+		return 4
+	}
+	return 0
+}
+
+//typeComponents accessor类型(SCALAR/VEC3/MAT4等)的分量个数与矩阵列数
+func typeComponents(typ string) (components, columns int) {
+	switch typ {
+	case "SCALAR":
+		return 1, 1
+	case "VEC2":
+		return 2, 1
+	case "VEC3":
+		return 3, 1
+	case "VEC4":
+		return 4, 1
+	case "MAT2":
+		return 4, 2
+	case "MAT3":
+		return 9, 3
+	case "MAT4":
+		return 16, 4
+	}
+	return 0, 0
+}
+
+//readComponent 读取一个分量的原始值, 无符号32位整数也能精确表示
+func readComponent(b []byte, componentType uint32) float64 {
+	switch componentType {
+	case BYTE:
+		return float64(int8(b[0]))
+	case UNSIGNED_BYTE:
+		return float64(b[0])
+	case SHORT:
+		return float64(int16(binary.LittleEndian.Uint16(b)))
+	case UNSIGNED_SHORT:
+		return float64(binary.LittleEndian.Uint16(b))
+	case UNSIGNED_INT:
+		return float64(binary.LittleEndian.Uint32(b))
+	case FLOAT:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	}
+	return 0
+}
+
+//normalize 将归一化整数转换到[0,1]或[-1,1]
+func normalize(v float64, componentType uint32) float64 {
+	switch componentType {
+	case BYTE:
+		return math.Max(v/127, -1)
+	case UNSIGNED_BYTE:
+		return v / 255
+	case SHORT:
+		return math.Max(v/32767, -1)
+	case UNSIGNED_SHORT:
+		return v / 65535
+	case UNSIGNED_INT:
+		return v / 4294967295
+	}
+	return v
+}
+
+//accessorData 解码后的accessor, 共count*components个分量
+type accessorData struct {
+	values     []float64
+	components int
+}
+
+func (a *accessorData) count() int {
+	return len(a.values) / a.components
+}
+
+//readAccessor 按分量类型、归一化标志、byteStride与sparse解码accessor
+func (l *loader) readAccessor(index int) (*accessorData, error) {
+	if index < 0 || index >= len(l.doc.Accessors) {
+		return nil, fmt.Errorf("accessor %d not found", index)
+	}
+	acc := l.doc.Accessors[index]
+	components, columns := typeComponents(acc.Type)
+	size := componentSize(acc.ComponentType)
+	if components == 0 || size == 0 {
+		return nil, fmt.Errorf("accessor %d: unsupported type %s/%d", index, acc.Type, acc.ComponentType)
+	}
+	// 矩阵的每一列按4字节对齐
+	rows := components / columns
+	columnSize := (rows*size + 3) &^ 3
+	elementSize := columnSize * columns
+	if columns == 1 {
+		elementSize = components * size
+	}
+
+	if acc.Count < 0 || acc.ByteOffset < 0 {
+		return nil, fmt.Errorf("accessor %d: negative count %d or byteOffset %d", index, acc.Count, acc.ByteOffset)
+	}
+
+	// 先检查bufferView的范围再切片, 格式错误的文件返回错误而不是越界panic
+	var view []byte
+	stride := elementSize
+	if acc.BufferView != nil {
+		var err error
+		var byteStride int
+		view, byteStride, err = l.bufferView(*acc.BufferView)
+		if err != nil {
+			return nil, fmt.Errorf("accessor %d: %v", index, err)
+		}
+		if byteStride != 0 {
+			if byteStride < elementSize {
+				return nil, fmt.Errorf("accessor %d: byteStride %d is smaller than the element size %d",
+					index, byteStride, elementSize)
+			}
+			stride = byteStride
+		}
+		// 最后一个元素的末尾不能超出bufferView, 用除法避免乘法溢出
+		if acc.Count > 0 && (acc.ByteOffset > len(view)-elementSize ||
+			(len(view)-elementSize-acc.ByteOffset)/stride < acc.Count-1) {
+			return nil, fmt.Errorf("accessor %d: out of bufferView bounds", index)
+		}
+	} else if acc.Count > maxZeroValues/components {
+		return nil, fmt.Errorf("accessor %d: count %d without bufferView exceeds %d values", index, acc.Count, maxZeroValues)
+	}
+
+	data := &accessorData{
+		values:     make([]float64, acc.Count*components),
+		components: components,
+	}
+	readElement := func(b []byte, dst []float64, componentType uint32, normalized bool) {
+		for c := 0; c < columns; c++ {
+			for r := 0; r < rows; r++ {
+				v := readComponent(b[c*columnSize+r*size:], componentType)
+				if normalized {
+					v = normalize(v, componentType)
+				}
+				dst[c*rows+r] = v
+			}
+		}
+	}
+
+	// 没有bufferView时初始值全为0, 通常配合sparse使用
+	if acc.BufferView != nil {
+		for i := 0; i < acc.Count; i++ {
+			offset := acc.ByteOffset + i*stride
+			readElement(view[offset:], data.values[i*components:], acc.ComponentType, acc.Normalized)
+		}
+	}
+
+	if sparse := acc.Sparse; sparse != nil {
+		indexView, _, err := l.bufferView(sparse.Indices.BufferView)
+		if err != nil {
+			return nil, fmt.Errorf("accessor %d sparse indices: %v", index, err)
+		}
+		valueView, _, err := l.bufferView(sparse.Values.BufferView)
+		if err != nil {
+			return nil, fmt.Errorf("accessor %d sparse values: %v", index, err)
+		}
+		indexSize := componentSize(sparse.Indices.ComponentType)
+		if indexSize == 0 || sparse.Indices.ComponentType == FLOAT ||
+			sparse.Count < 0 || sparse.Count > acc.Count ||
+			sparse.Indices.ByteOffset < 0 || sparse.Values.ByteOffset < 0 ||
+			sparse.Indices.ByteOffset+sparse.Count*indexSize > len(indexView) ||
+			sparse.Values.ByteOffset+sparse.Count*elementSize > len(valueView) {
+			return nil, fmt.Errorf("accessor %d: invalid sparse storage", index)
+		}
+		for i := 0; i < sparse.Count; i++ {
+			target := int(readComponent(indexView[sparse.Indices.ByteOffset+i*indexSize:], sparse.Indices.ComponentType))
+			if target >= acc.Count {
+				return nil, fmt.Errorf("accessor %d: sparse index %d out of range", index, target)
+			}
+			offset := sparse.Values.ByteOffset + i*elementSize
+			readElement(valueView[offset:], data.values[target*components:], acc.ComponentType, acc.Normalized)
+		}
+	}
+	return data, nil
+}
+
+//readIndices 读取索引accessor, 分量类型须为无符号整数
+func (l *loader) readIndices(index int) ([]uint32, error) {
+	if index >= 0 && index < len(l.doc.Accessors) {
+		acc := l.doc.Accessors[index]
+		switch acc.ComponentType {
+		case UNSIGNED_BYTE, UNSIGNED_SHORT, UNSIGNED_INT:
+		default:
+			return nil, fmt.Errorf("accessor %d: index component type %d", index, acc.ComponentType)
+		}
+	}
+	data, err := l.readAccessor(index)
+	if err != nil {
+		return nil, err
+	}
+	if data.components != 1 {
+		return nil, fmt.Errorf("accessor %d: indices must be SCALAR", index)
+	}
+	indices := make([]uint32, len(data.values))
+	for i, v := range data.values {
+		indices[i] = uint32(v)
+	}
+	return indices, nil
+}
+
+//bufferView 返回bufferView对应的字节与byteStride
+func (l *loader) bufferView(index int) ([]byte, int, error) {
+	if index < 0 || index >= len(l.doc.BufferViews) {
+		return nil, 0, fmt.Errorf("bufferView %d not found", index)
+	}
+	view := l.doc.BufferViews[index]
+	buf, err := l.buffer(view.Buffer)
+	if err != nil {
+		return nil, 0, err
+	}
+	if view.ByteOffset < 0 || view.ByteLength < 0 || view.ByteOffset > len(buf) || view.ByteLength > len(buf)-view.ByteOffset {
+		return nil, 0, fmt.Errorf("bufferView %d: out of buffer bounds", index)
+	}
+	return buf[view.ByteOffset : view.ByteOffset+view.ByteLength], view.ByteStride, nil
+}
@@ -0,0 +1,86 @@
+package gltf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//triangleDocument 一个三角形的glTF, 位置放在36字节的data URI缓冲中, buffer、view与accessor为对应的JSON片段
+func triangleDocument(buffer, view, accessor string) []byte {
+	var buf bytes.Buffer
+	for _, v := range []float32{0, 0, 0, 1, 0, 0, 0, 1, 0} {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	uri := "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+	return []byte(fmt.Sprintf(`{
+		"asset": {"version": "2.0"},
+		"buffers": [{"uri": %q, %s}],
+		"bufferViews": [{"buffer": 0, %s}],
+		"accessors": [{"componentType": 5126, "type": "VEC3", %s}],
+		"meshes": [{"primitives": [{"attributes": {"POSITION": 0}}]}]
+	}`, uri, buffer, view, accessor))
+}
+
+func TestReadAccessor(t *testing.T) {
+	model, err := Parse(triangleDocument(`"byteLength": 36`, `"byteLength": 36`, `"bufferView": 0, "count": 3`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []mgl32.Vec3{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}
+	got := model.Meshes[0].Primitives[0].Positions
+	if len(got) != len(want) {
+		t.Fatalf("positions %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("positions %v, want %v", got, want)
+		}
+	}
+}
+
+//TestMalformedAccessor 格式错误的accessor与bufferView返回错误, 不会越界panic
+func TestMalformedAccessor(t *testing.T) {
+	tests := []struct {
+		name, buffer, view, accessor, want string
+	}{
+		{"negative count", "", `"byteLength": 36`, `"bufferView": 0, "count": -1`, "negative count"},
+		{"negative byteOffset", "", `"byteLength": 36`, `"bufferView": 0, "count": 1, "byteOffset": -12`, "or byteOffset -12"},
+		{"count past end", "", `"byteLength": 36`, `"bufferView": 0, "count": 4`, "out of bufferView bounds"},
+		{"offset past end", "", `"byteLength": 36`, `"bufferView": 0, "count": 1, "byteOffset": 36`, "out of bufferView bounds"},
+		{"huge count", "", `"byteLength": 36`, `"bufferView": 0, "count": 1152921504606846976`, "out of bufferView bounds"},
+		{"stride too small", "", `"byteLength": 36, "byteStride": 8`, `"bufferView": 0, "count": 3`, "byteStride 8 is smaller than the element size 12"},
+		{"negative stride", "", `"byteLength": 36, "byteStride": -12`, `"bufferView": 0, "count": 3`, "byteStride -12"},
+		{"view past buffer", "", `"byteLength": 48`, `"bufferView": 0, "count": 3`, "out of buffer bounds"},
+		{"negative view offset", "", `"byteLength": 36, "byteOffset": -4`, `"bufferView": 0, "count": 3`, "out of buffer bounds"},
+		{"huge view length", "", `"byteLength": 9223372036854775807, "byteOffset": 4`, `"bufferView": 0, "count": 3`, "out of buffer bounds"},
+		{"negative sparse count", "", `"byteLength": 36`,
+			`"bufferView": 0, "count": 3, "sparse": {"count": -1, "indices": {"bufferView": 0, "componentType": 5125}, "values": {"bufferView": 0}}`,
+			"invalid sparse storage"},
+		{"sparse count past accessor", "", `"byteLength": 36`,
+			`"bufferView": 0, "count": 1, "sparse": {"count": 2, "indices": {"bufferView": 0, "componentType": 5125}, "values": {"bufferView": 0}}`,
+			"invalid sparse storage"},
+		{"negative sparse offset", "", `"byteLength": 36`,
+			`"bufferView": 0, "count": 3, "sparse": {"count": 1, "indices": {"bufferView": 0, "componentType": 5125, "byteOffset": -4}, "values": {"bufferView": 0}}`,
+			"invalid sparse storage"},
+		{"negative buffer length", `"byteLength": -1`, `"byteLength": 36`, `"bufferView": 0, "count": 3`, "negative byteLength -1"},
+		// 没有bufferView时数据全为0, count不受数据大小限制, 需要单独的上限
+		{"huge count without bufferView", "", `"byteLength": 36`, `"count": 1152921504606846976`, "exceeds 16777216 values"},
+		{"large count without bufferView", "", `"byteLength": 36`, `"count": 5592406`, "exceeds 16777216 values"},
+	}
+	for _, tt := range tests {
+		buffer := tt.buffer // 为空时使用与数据一致的长度
+		if buffer == "" {
+			buffer = `"byteLength": 36`
+		}
+		_, err := Parse(triangleDocument(buffer, tt.view, tt.accessor), nil)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
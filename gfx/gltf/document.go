@@ -0,0 +1,124 @@
+package gltf
+
+// document 与glTF JSON结构对应, 只包含加载器用到的字段
+type document struct {
+	Asset struct {
+		Version string `json:"version"`
+	} `json:"asset"`
+	Scene       *int             `json:"scene"`
+	Scenes      []sceneJSON      `json:"scenes"`
+	Nodes       []nodeJSON       `json:"nodes"`
+	Meshes      []meshJSON       `json:"meshes"`
+	Accessors   []accessorJSON   `json:"accessors"`
+	BufferViews []bufferViewJSON `json:"bufferViews"`
+	Buffers     []bufferJSON     `json:"buffers"`
+	Materials   []materialJSON   `json:"materials"`
+	Textures    []textureJSON    `json:"textures"`
+	Images      []imageJSON      `json:"images"`
+	Samplers    []samplerJSON    `json:"samplers"`
+}
+
+type sceneJSON struct {
+	Name  string `json:"name"`
+	Nodes []int  `json:"nodes"`
+}
+
+type nodeJSON struct {
+	Name        string    `json:"name"`
+	Mesh        *int      `json:"mesh"`
+	Children    []int     `json:"children"`
+	Matrix      []float32 `json:"matrix"`
+	Translation []float32 `json:"translation"`
+	Rotation    []float32 `json:"rotation"`
+	Scale       []float32 `json:"scale"`
+}
+
+type meshJSON struct {
+	Name       string          `json:"name"`
+	Primitives []primitiveJSON `json:"primitives"`
+}
+
+type primitiveJSON struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    *int           `json:"indices"`
+	Material   *int           `json:"material"`
+	Mode       *uint32        `json:"mode"`
+}
+
+type accessorJSON struct {
+	BufferView    *int   `json:"bufferView"`
+	ByteOffset    int    `json:"byteOffset"`
+	ComponentType uint32 `json:"componentType"`
+	Normalized    bool   `json:"normalized"`
+	Count         int    `json:"count"`
+	Type          string `json:"type"`
+	Sparse        *struct {
+		Count   int `json:"count"`
+		Indices struct {
+			BufferView    int    `json:"bufferView"`
+			ByteOffset    int    `json:"byteOffset"`
+			ComponentType uint32 `json:"componentType"`
+		} `json:"indices"`
+		Values struct {
+			BufferView int `json:"bufferView"`
+			ByteOffset int `json:"byteOffset"`
+		} `json:"values"`
+	} `json:"sparse"`
+}
+
+type bufferViewJSON struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	ByteStride int `json:"byteStride"`
+}
+
+type bufferJSON struct {
+	URI        string `json:"uri"`
+	ByteLength int    `json:"byteLength"`
+}
+
+type textureInfoJSON struct {
+	Index    int      `json:"index"`
+	TexCoord int      `json:"texCoord"`
+	Scale    *float32 `json:"scale"`    // normalTexture
+	Strength *float32 `json:"strength"` // occlusionTexture
+}
+
+type materialJSON struct {
+	Name                 string `json:"name"`
+	PBRMetallicRoughness struct {
+		BaseColorFactor          []float32        `json:"baseColorFactor"`
+		BaseColorTexture         *textureInfoJSON `json:"baseColorTexture"`
+		MetallicFactor           *float32         `json:"metallicFactor"`
+		RoughnessFactor          *float32         `json:"roughnessFactor"`
+		MetallicRoughnessTexture *textureInfoJSON `json:"metallicRoughnessTexture"`
+	} `json:"pbrMetallicRoughness"`
+	NormalTexture    *textureInfoJSON `json:"normalTexture"`
+	OcclusionTexture *textureInfoJSON `json:"occlusionTexture"`
+	EmissiveTexture  *textureInfoJSON `json:"emissiveTexture"`
+	EmissiveFactor   []float32        `json:"emissiveFactor"`
+	AlphaMode        string           `json:"alphaMode"`
+	AlphaCutoff      *float32         `json:"alphaCutoff"`
+	DoubleSided      bool             `json:"doubleSided"`
+}
+
+type textureJSON struct {
+	Name    string `json:"name"`
+	Sampler *int   `json:"sampler"`
+	Source  *int   `json:"source"`
+}
+
+type imageJSON struct {
+	Name       string `json:"name"`
+	URI        string `json:"uri"`
+	MimeType   string `json:"mimeType"`
+	BufferView *int   `json:"bufferView"`
+}
+
+type samplerJSON struct {
+	MagFilter int32 `json:"magFilter"`
+	MinFilter int32 `json:"minFilter"`
+	WrapS     int32 `json:"wrapS"`
+	WrapT     int32 `json:"wrapT"`
+}
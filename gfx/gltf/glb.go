@@ -0,0 +1,51 @@
+package gltf
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// GLB块类型
+const (
+	chunkJSON = 0x4E4F534A // "JSON"
+	chunkBIN  = 0x004E4942 // "BIN\x00"
+)
+
+//parseGLB 拆分GLB容器: 12字节文件头, JSON块, 可选的BIN块
+func parseGLB(data []byte) (jsonChunk, bin []byte, err error) {
+	if len(data) < 12 {
+		return nil, nil, fmt.Errorf("glb: truncated header")
+	}
+	if version := binary.LittleEndian.Uint32(data[4:]); version != 2 {
+		return nil, nil, fmt.Errorf("glb: unsupported version %d", version)
+	}
+	length := int(binary.LittleEndian.Uint32(data[8:]))
+	if length > len(data) {
+		return nil, nil, fmt.Errorf("glb: length %d exceeds file size %d", length, len(data))
+	}
+
+	for offset := 12; offset < length; {
+		if offset+8 > length {
+			return nil, nil, fmt.Errorf("glb: truncated chunk header")
+		}
+		chunkLength := int(binary.LittleEndian.Uint32(data[offset:]))
+		chunkType := binary.LittleEndian.Uint32(data[offset+4:])
+		start := offset + 8
+		if start+chunkLength > length {
+			return nil, nil, fmt.Errorf("glb: chunk exceeds file length")
+		}
+		chunk := data[start : start+chunkLength]
+		switch {
+		case chunkType == chunkJSON && jsonChunk == nil:
+			jsonChunk = chunk
+		case chunkType == chunkBIN && bin == nil:
+			bin = chunk
+		}
+		// 未知块忽略; 块按4字节对齐
+		offset = start + (chunkLength+3)&^3
+	}
+	if jsonChunk == nil {
+		return nil, nil, fmt.Errorf("glb: missing JSON chunk")
+	}
+	return jsonChunk, bin, nil
+}
@@ -0,0 +1,168 @@
+/*
+glTF 2.0 模型加载
+支持 .gltf(JSON + 外部二进制文件或data URI) 与 .glb 容器,
+解码所有分量类型的accessor(含归一化整数、byteStride与sparse),
+构建带TRS变换的节点层级, 并将金属度-粗糙度材质映射为可通过
+texture.NewTextureFromFile 加载的纹理引用
+*/
+
+package gltf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+//Model 加载得到的模型, 各切片的下标与文件中的下标一致
+type Model struct {
+	Scenes    []*Scene
+	Scene     *Scene // 默认场景, 文件未指定时为第一个场景, 没有场景时为nil
+	Nodes     []*Node
+	Meshes    []*Mesh
+	Materials []*Material
+	Textures  []*Texture
+	Images    []*Image
+}
+
+//Scene 场景, Nodes为根节点
+type Scene struct {
+	Name  string
+	Nodes []*Node
+}
+
+//Opener 按相对路径读取外部缓冲或图片
+type Opener func(uri string) ([]byte, error)
+
+//Load 读取.gltf或.glb文件, 外部缓冲与图片路径相对于所在文件的目录
+func Load(file string) (*Model, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("load gltf file %s: %v", file, err)
+	}
+	dir := filepath.Dir(file)
+	model, err := Parse(data, func(uri string) ([]byte, error) {
+		return ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(uri)))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load gltf file %s: %v", file, err)
+	}
+	for _, img := range model.Images {
+		if img.URI != "" && !filepath.IsAbs(img.URI) {
+			img.URI = filepath.Join(dir, filepath.FromSlash(img.URI))
+		}
+	}
+	return model, nil
+}
+
+//Parse 解析glTF JSON或GLB数据, 按魔数区分格式
+//open 用于读取外部缓冲, 为nil时只能使用data URI与GLB内嵌缓冲
+func Parse(data []byte, open Opener) (*Model, error) {
+	l := &loader{open: open, model: &Model{}}
+	jsonChunk := data
+	if isGLB(data) {
+		var err error
+		if jsonChunk, l.bin, err = parseGLB(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal(jsonChunk, &l.doc); err != nil {
+		return nil, fmt.Errorf("parse json: %v", err)
+	}
+	if !strings.HasPrefix(l.doc.Asset.Version, "2.") {
+		return nil, fmt.Errorf("unsupported glTF version %q", l.doc.Asset.Version)
+	}
+	l.buffers = make([][]byte, len(l.doc.Buffers))
+
+	steps := []func() error{l.loadImages, l.loadTextures, l.loadMaterials, l.loadMeshes, l.loadNodes, l.loadScenes}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+	return l.model, nil
+}
+
+//loader 加载过程中的状态
+type loader struct {
+	doc     document
+	open    Opener
+	bin     []byte   // GLB的BIN块
+	buffers [][]byte // 按需读取的缓冲
+	model   *Model
+}
+
+//buffer 读取第index个缓冲: GLB内嵌、data URI或外部文件
+func (l *loader) buffer(index int) ([]byte, error) {
+	if index < 0 || index >= len(l.doc.Buffers) {
+		return nil, fmt.Errorf("buffer %d not found", index)
+	}
+	if l.buffers[index] != nil {
+		return l.buffers[index], nil
+	}
+	b := l.doc.Buffers[index]
+	var data []byte
+	var err error
+	switch {
+	case b.URI == "" && index == 0 && l.bin != nil:
+		data = l.bin
+	case b.URI == "":
+		return nil, fmt.Errorf("buffer %d: missing uri", index)
+	default:
+		data, _, err = l.readURI(b.URI)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("buffer %d: %v", index, err)
+	}
+	if b.ByteLength < 0 {
+		return nil, fmt.Errorf("buffer %d: negative byteLength %d", index, b.ByteLength)
+	}
+	if len(data) < b.ByteLength {
+		return nil, fmt.Errorf("buffer %d: %d bytes, expected %d", index, len(data), b.ByteLength)
+	}
+	l.buffers[index] = data[:b.ByteLength]
+	return l.buffers[index], nil
+}
+
+//readURI 读取data URI或相对路径, 返回数据与data URI中的MIME类型
+func (l *loader) readURI(uri string) ([]byte, string, error) {
+	if strings.HasPrefix(uri, "data:") {
+		return decodeDataURI(uri)
+	}
+	if l.open == nil {
+		return nil, "", fmt.Errorf("cannot open external uri %q", uri)
+	}
+	path, err := url.PathUnescape(uri)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := l.open(path)
+	return data, "", err
+}
+
+//decodeDataURI 解码 data:[<mime>][;base64],<data>
+func decodeDataURI(uri string) ([]byte, string, error) {
+	comma := strings.IndexByte(uri, ',')
+	if comma < 0 {
+		return nil, "", fmt.Errorf("invalid data uri")
+	}
+	header, payload := uri[len("data:"):comma], uri[comma+1:]
+	mime := header
+	if strings.HasSuffix(header, ";base64") {
+		mime = strings.TrimSuffix(header, ";base64")
+		data, err := base64.StdEncoding.DecodeString(payload)
+		return data, mime, err
+	}
+	data, err := url.PathUnescape(payload)
+	return []byte(data), mime, err
+}
+
+//isGLB 是否以GLB魔数"glTF"开头
+func isGLB(data []byte) bool {
+	return len(data) >= 4 && bytes.Equal(data[:4], []byte("glTF"))
+}
@@ -0,0 +1,226 @@
+package gltf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx/texture"
+)
+
+//Image 图片: 外部文件时URI为文件路径, 内嵌(data URI或bufferView)时数据在Data中
+type Image struct {
+	Name     string
+	URI      string
+	MimeType string
+	Data     []byte
+}
+
+//Texture 图片与采样参数, 取值为OpenGL常量, 过滤方式未指定时为0
+type Texture struct {
+	Name      string
+	Image     *Image
+	WrapS     int32
+	WrapT     int32
+	MinFilter int32
+	MagFilter int32
+}
+
+//TextureRef 材质对纹理的引用
+type TextureRef struct {
+	*Texture
+	TexCoord int     // 使用 TEXCOORD_<n>
+	Scale    float32 // 法线贴图的scale或遮蔽贴图的strength, 其它贴图为1
+}
+
+//Material 金属度-粗糙度PBR材质, 未使用的贴图为nil
+type Material struct {
+	Name string
+
+	BaseColorFactor          mgl32.Vec4
+	BaseColorTexture         *TextureRef
+	MetallicFactor           float32
+	RoughnessFactor          float32
+	MetallicRoughnessTexture *TextureRef // B通道为金属度, G通道为粗糙度
+
+	NormalTexture    *TextureRef
+	OcclusionTexture *TextureRef
+	EmissiveTexture  *TextureRef
+	EmissiveFactor   mgl32.Vec3
+
+	AlphaMode   string // OPAQUE, MASK 或 BLEND
+	AlphaCutoff float32
+	DoubleSided bool
+}
+
+//Decode 解码图片
+func (img *Image) Decode() (image.Image, error) {
+	if img.Data != nil {
+		m, _, err := image.Decode(bytes.NewReader(img.Data))
+		return m, err
+	}
+	f, err := os.Open(img.URI)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, _, err := image.Decode(f)
+	return m, err
+}
+
+//Load 创建OpenGL纹理, 外部图片经由 texture.NewTextureFromFile 加载
+//linear 为true时按线性数据存储(法线、金属度粗糙度、遮蔽贴图), 颜色贴图(基础色、自发光)为false
+func (tex *Texture) Load(linear bool) (*texture.Texture, error) {
+	if tex.Image == nil {
+		return nil, fmt.Errorf("texture %q has no image", tex.Name)
+	}
+	opts := tex.Options()
+	opts.Linear = linear
+	if tex.Image.Data == nil {
+		return texture.NewTextureFromFile(tex.Image.URI, opts)
+	}
+	img, err := tex.Image.Decode()
+	if err != nil {
+		return nil, err
+	}
+	return texture.NewTexture(img, opts)
+}
+
+//Options 采样参数对应的纹理选项, 缩小过滤未指定或使用mipmap时生成多级渐远纹理
+func (tex *Texture) Options() texture.TextureOptions {
+	opts := texture.TextureOptions{
+		MinFilter: tex.MinFilter,
+		MagFilter: tex.MagFilter,
+		WrapS:     tex.WrapS,
+		WrapT:     tex.WrapT,
+	}
+	switch tex.MinFilter {
+	case 0, gl.NEAREST_MIPMAP_NEAREST, gl.LINEAR_MIPMAP_NEAREST, gl.NEAREST_MIPMAP_LINEAR, gl.LINEAR_MIPMAP_LINEAR:
+		opts.Mipmap = true
+	}
+	return opts
+}
+
+func (l *loader) loadImages() error {
+	for i, src := range l.doc.Images {
+		img := &Image{Name: src.Name, MimeType: src.MimeType}
+		switch {
+		case src.BufferView != nil:
+			view, _, err := l.bufferView(*src.BufferView)
+			if err != nil {
+				return fmt.Errorf("image %d: %v", i, err)
+			}
+			img.Data = view
+		case strings.HasPrefix(src.URI, "data:"):
+			data, mime, err := decodeDataURI(src.URI)
+			if err != nil {
+				return fmt.Errorf("image %d: %v", i, err)
+			}
+			img.Data = data
+			if img.MimeType == "" {
+				img.MimeType = mime
+			}
+		default:
+			// 外部图片只记录路径, 由 texture.NewTextureFromFile 读取
+			img.URI = src.URI
+		}
+		l.model.Images = append(l.model.Images, img)
+	}
+	return nil
+}
+
+func (l *loader) loadTextures() error {
+	for i, src := range l.doc.Textures {
+		tex := &Texture{Name: src.Name, WrapS: gl.REPEAT, WrapT: gl.REPEAT}
+		if src.Source != nil {
+			if *src.Source < 0 || *src.Source >= len(l.model.Images) {
+				return fmt.Errorf("texture %d: image %d not found", i, *src.Source)
+			}
+			tex.Image = l.model.Images[*src.Source]
+		}
+		if src.Sampler != nil {
+			if *src.Sampler < 0 || *src.Sampler >= len(l.doc.Samplers) {
+				return fmt.Errorf("texture %d: sampler %d not found", i, *src.Sampler)
+			}
+			s := l.doc.Samplers[*src.Sampler]
+			tex.MinFilter, tex.MagFilter = s.MinFilter, s.MagFilter
+			if s.WrapS != 0 {
+				tex.WrapS = s.WrapS
+			}
+			if s.WrapT != 0 {
+				tex.WrapT = s.WrapT
+			}
+		}
+		l.model.Textures = append(l.model.Textures, tex)
+	}
+	return nil
+}
+
+func (l *loader) loadMaterials() error {
+	for i, src := range l.doc.Materials {
+		pbr := src.PBRMetallicRoughness
+		m := &Material{
+			Name:            src.Name,
+			BaseColorFactor: mgl32.Vec4{1, 1, 1, 1},
+			MetallicFactor:  1,
+			RoughnessFactor: 1,
+			AlphaMode:       "OPAQUE",
+			AlphaCutoff:     0.5,
+			DoubleSided:     src.DoubleSided,
+		}
+		if len(pbr.BaseColorFactor) == 4 {
+			copy(m.BaseColorFactor[:], pbr.BaseColorFactor)
+		}
+		if pbr.MetallicFactor != nil {
+			m.MetallicFactor = *pbr.MetallicFactor
+		}
+		if pbr.RoughnessFactor != nil {
+			m.RoughnessFactor = *pbr.RoughnessFactor
+		}
+		if len(src.EmissiveFactor) == 3 {
+			copy(m.EmissiveFactor[:], src.EmissiveFactor)
+		}
+		if src.AlphaMode != "" {
+			m.AlphaMode = src.AlphaMode
+		}
+		if src.AlphaCutoff != nil {
+			m.AlphaCutoff = *src.AlphaCutoff
+		}
+
+		refs := []struct {
+			info *textureInfoJSON
+			dst  **TextureRef
+		}{
+			{pbr.BaseColorTexture, &m.BaseColorTexture},
+			{pbr.MetallicRoughnessTexture, &m.MetallicRoughnessTexture},
+			{src.NormalTexture, &m.NormalTexture},
+			{src.OcclusionTexture, &m.OcclusionTexture},
+			{src.EmissiveTexture, &m.EmissiveTexture},
+		}
+		for _, ref := range refs {
+			if ref.info == nil {
+				continue
+			}
+			if ref.info.Index < 0 || ref.info.Index >= len(l.model.Textures) {
+				return fmt.Errorf("material %d: texture %d not found", i, ref.info.Index)
+			}
+			r := &TextureRef{Texture: l.model.Textures[ref.info.Index], TexCoord: ref.info.TexCoord, Scale: 1}
+			if ref.info.Scale != nil {
+				r.Scale = *ref.info.Scale
+			}
+			if ref.info.Strength != nil {
+				r.Scale = *ref.info.Strength
+			}
+			*ref.dst = r
+		}
+		l.model.Materials = append(l.model.Materials, m)
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package gltf
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx/mesh"
+)
+
+//Mesh 网格, 由一个或多个图元组成
+type Mesh struct {
+	Name       string
+	Primitives []*Primitive
+}
+
+//Primitive 使用同一材质的一组图元
+//三角形带与三角形扇会被展开为 gl.TRIANGLES;
+//缺少法线时按规范生成平面法线, 缺少切线时由纹理坐标计算
+type Primitive struct {
+	Mode     uint32    // gl.TRIANGLES/gl.LINES/gl.POINTS 等
+	Material *Material // 未指定时为nil, 应使用默认材质
+	mesh.Geometry
+	UVs1   []mgl32.Vec2 // TEXCOORD_1, 没有时为nil
+	Colors []mgl32.Vec4 // COLOR_0, 没有时为nil
+}
+
+func (l *loader) loadMeshes() error {
+	for i, src := range l.doc.Meshes {
+		m := &Mesh{Name: src.Name}
+		for j, p := range src.Primitives {
+			prim, err := l.loadPrimitive(p)
+			if err != nil {
+				return fmt.Errorf("mesh %d primitive %d: %v", i, j, err)
+			}
+			m.Primitives = append(m.Primitives, prim)
+		}
+		l.model.Meshes = append(l.model.Meshes, m)
+	}
+	return nil
+}
+
+func (l *loader) loadPrimitive(src primitiveJSON) (*Primitive, error) {
+	p := &Primitive{Mode: gl.TRIANGLES}
+	if src.Mode != nil {
+		p.Mode = *src.Mode
+	}
+	if src.Material != nil {
+		if *src.Material < 0 || *src.Material >= len(l.model.Materials) {
+			return nil, fmt.Errorf("material %d not found", *src.Material)
+		}
+		p.Material = l.model.Materials[*src.Material]
+	}
+
+	posIndex, ok := src.Attributes["POSITION"]
+	if !ok {
+		return nil, fmt.Errorf("missing POSITION")
+	}
+	positions, err := l.readAttribute(posIndex, 3)
+	if err != nil {
+		return nil, err
+	}
+	count := positions.count()
+	p.Positions = positions.vec3s()
+
+	// 可选属性: 缺少时为nil
+	optional := func(name string, minComponents int) (*accessorData, error) {
+		index, ok := src.Attributes[name]
+		if !ok {
+			return nil, nil
+		}
+		data, err := l.readAttribute(index, minComponents)
+		if err == nil && data.count() != count {
+			err = fmt.Errorf("%s has %d elements, POSITION has %d", name, data.count(), count)
+		}
+		return data, err
+	}
+	normals, err := optional("NORMAL", 3)
+	if err != nil {
+		return nil, err
+	}
+	tangents, err := optional("TANGENT", 4)
+	if err != nil {
+		return nil, err
+	}
+	uvs, err := optional("TEXCOORD_0", 2)
+	if err != nil {
+		return nil, err
+	}
+	uvs1, err := optional("TEXCOORD_1", 2)
+	if err != nil {
+		return nil, err
+	}
+	colors, err := optional("COLOR_0", 3)
+	if err != nil {
+		return nil, err
+	}
+	if normals != nil {
+		p.Normals = normals.vec3s()
+	}
+	if tangents != nil {
+		p.Tangents = tangents.vec4s()
+	}
+	if uvs != nil {
+		p.UVs = uvs.vec2s()
+	} else {
+		p.UVs = make([]mgl32.Vec2, count)
+	}
+	if uvs1 != nil {
+		p.UVs1 = uvs1.vec2s()
+	}
+	if colors != nil {
+		p.Colors = colors.vec4s()
+	}
+
+	if src.Indices != nil {
+		if p.Indices, err = l.readIndices(*src.Indices); err != nil {
+			return nil, err
+		}
+		for _, index := range p.Indices {
+			if int(index) >= count {
+				return nil, fmt.Errorf("index %d out of range (%d vertices)", index, count)
+			}
+		}
+	} else {
+		p.Indices = make([]uint32, count)
+		for i := range p.Indices {
+			p.Indices[i] = uint32(i)
+		}
+	}
+
+	switch p.Mode {
+	case gl.TRIANGLE_STRIP, gl.TRIANGLE_FAN:
+		p.Indices = toTriangles(p.Mode, p.Indices)
+		p.Mode = gl.TRIANGLES
+	}
+	if p.Mode != gl.TRIANGLES {
+		return p, nil
+	}
+	if p.Normals == nil {
+		p.flatten()
+	}
+	if p.Tangents == nil {
+		p.ComputeTangents()
+	}
+	return p, nil
+}
+
+//readAttribute 读取顶点属性, 分量个数不得少于minComponents
+func (l *loader) readAttribute(index, minComponents int) (*accessorData, error) {
+	data, err := l.readAccessor(index)
+	if err != nil {
+		return nil, err
+	}
+	if data.components < minComponents || data.components > 4 {
+		return nil, fmt.Errorf("accessor %d: expected at least %d components, got %d", index, minComponents, data.components)
+	}
+	return data, nil
+}
+
+//toTriangles 将三角形带或三角形扇的索引展开为三角形列表, 保持绕序
+func toTriangles(mode uint32, strip []uint32) []uint32 {
+	var tris []uint32
+	for i := 0; i+2 < len(strip); i++ {
+		switch {
+		case mode == gl.TRIANGLE_FAN:
+			tris = append(tris, strip[i+1], strip[i+2], strip[0])
+		case i%2 == 0:
+			tris = append(tris, strip[i], strip[i+1], strip[i+2])
+		default:
+			tris = append(tris, strip[i], strip[i+2], strip[i+1])
+		}
+	}
+	return tris
+}
+
+//flatten 没有法线时展开共享顶点, 每个三角形使用自己的面法线
+func (p *Primitive) flatten() {
+	indices := p.Indices
+	positions, uvs, uvs1, colors := p.Positions, p.UVs, p.UVs1, p.Colors
+	p.Positions, p.UVs, p.UVs1, p.Colors, p.Indices = nil, nil, nil, nil, nil
+	for i := 0; i+2 < len(indices); i += 3 {
+		a, b, c := positions[indices[i]], positions[indices[i+1]], positions[indices[i+2]]
+		normal := b.Sub(a).Cross(c.Sub(a))
+		if normal.Len() > 0 {
+			normal = normal.Normalize()
+		}
+		for _, index := range indices[i : i+3] {
+			p.Indices = append(p.Indices, uint32(len(p.Positions)))
+			p.Positions = append(p.Positions, positions[index])
+			p.Normals = append(p.Normals, normal)
+			p.UVs = append(p.UVs, uvs[index])
+			if uvs1 != nil {
+				p.UVs1 = append(p.UVs1, uvs1[index])
+			}
+			if colors != nil {
+				p.Colors = append(p.Colors, colors[index])
+			}
+		}
+	}
+	// 已有的切线与展开后的顶点不再对应, 需要重新计算
+	p.Tangents = nil
+}
+
+func (a *accessorData) vec2s() []mgl32.Vec2 {
+	out := make([]mgl32.Vec2, a.count())
+	for i := range out {
+		for c := 0; c < 2; c++ {
+			out[i][c] = float32(a.values[i*a.components+c])
+		}
+	}
+	return out
+}
+
+func (a *accessorData) vec3s() []mgl32.Vec3 {
+	out := make([]mgl32.Vec3, a.count())
+	for i := range out {
+		for c := 0; c < 3; c++ {
+			out[i][c] = float32(a.values[i*a.components+c])
+		}
+	}
+	return out
+}
+
+//vec4s 不足4个分量时w补1, 用于COLOR_0的VEC3
+func (a *accessorData) vec4s() []mgl32.Vec4 {
+	out := make([]mgl32.Vec4, a.count())
+	for i := range out {
+		out[i][3] = 1
+		for c := 0; c < a.components && c < 4; c++ {
+			out[i][c] = float32(a.values[i*a.components+c])
+		}
+	}
+	return out
+}
@@ -0,0 +1,142 @@
+package gltf
+
+import (
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//Node 场景节点, 局部变换为 Matrix 或 T*R*S
+type Node struct {
+	Name     string
+	Mesh     *Mesh // 没有网格时为nil
+	Parent   *Node
+	Children []*Node
+
+	Translation mgl32.Vec3
+	Rotation    mgl32.Quat
+	Scale       mgl32.Vec3
+	Matrix      *mgl32.Mat4 // 文件中直接给出矩阵时非nil, 此时忽略TRS
+}
+
+//LocalTransform 相对于父节点的变换
+func (n *Node) LocalTransform() mgl32.Mat4 {
+	if n.Matrix != nil {
+		return *n.Matrix
+	}
+	t := mgl32.Translate3D(n.Translation.X(), n.Translation.Y(), n.Translation.Z())
+	r := n.Rotation.Normalize().Mat4()
+	s := mgl32.Scale3D(n.Scale.X(), n.Scale.Y(), n.Scale.Z())
+	return t.Mul4(r).Mul4(s)
+}
+
+//WorldTransform 相对于场景根的变换
+func (n *Node) WorldTransform() mgl32.Mat4 {
+	m := n.LocalTransform()
+	for p := n.Parent; p != nil; p = p.Parent {
+		m = p.LocalTransform().Mul4(m)
+	}
+	return m
+}
+
+//Walk 深度优先遍历场景, fn 收到每个节点及其世界变换
+func (s *Scene) Walk(fn func(node *Node, world mgl32.Mat4)) {
+	var walk func(node *Node, parent mgl32.Mat4)
+	walk = func(node *Node, parent mgl32.Mat4) {
+		world := parent.Mul4(node.LocalTransform())
+		fn(node, world)
+		for _, child := range node.Children {
+			walk(child, world)
+		}
+	}
+	for _, root := range s.Nodes {
+		walk(root, mgl32.Ident4())
+	}
+}
+
+func (l *loader) loadNodes() error {
+	for i, src := range l.doc.Nodes {
+		n := &Node{
+			Name:     src.Name,
+			Rotation: mgl32.QuatIdent(),
+			Scale:    mgl32.Vec3{1, 1, 1},
+		}
+		if src.Mesh != nil {
+			if *src.Mesh < 0 || *src.Mesh >= len(l.model.Meshes) {
+				return fmt.Errorf("node %d: mesh %d not found", i, *src.Mesh)
+			}
+			n.Mesh = l.model.Meshes[*src.Mesh]
+		}
+		if len(src.Matrix) == 16 {
+			var m mgl32.Mat4
+			copy(m[:], src.Matrix) // glTF与mgl32都按列主序存储
+			n.Matrix = &m
+		}
+		if len(src.Translation) == 3 {
+			copy(n.Translation[:], src.Translation)
+		}
+		if len(src.Rotation) == 4 {
+			// glTF四元数顺序为 x, y, z, w
+			n.Rotation = mgl32.Quat{
+				W: src.Rotation[3],
+				V: mgl32.Vec3{src.Rotation[0], src.Rotation[1], src.Rotation[2]},
+			}
+		}
+		if len(src.Scale) == 3 {
+			copy(n.Scale[:], src.Scale)
+		}
+		l.model.Nodes = append(l.model.Nodes, n)
+	}
+
+	for i, src := range l.doc.Nodes {
+		parent := l.model.Nodes[i]
+		for _, c := range src.Children {
+			if c < 0 || c >= len(l.model.Nodes) {
+				return fmt.Errorf("node %d: child %d not found", i, c)
+			}
+			child := l.model.Nodes[c]
+			if child.Parent != nil || child == parent {
+				return fmt.Errorf("node %d: child %d already has a parent", i, c)
+			}
+			child.Parent = parent
+			parent.Children = append(parent.Children, child)
+		}
+	}
+	// 每个节点至多一个父节点, 此时只剩下不含根的环
+	for i, n := range l.model.Nodes {
+		steps := 0
+		for p := n.Parent; p != nil; p = p.Parent {
+			if steps++; steps > len(l.model.Nodes) {
+				return fmt.Errorf("node %d: cycle in node hierarchy", i)
+			}
+		}
+	}
+	return nil
+}
+
+func (l *loader) loadScenes() error {
+	for i, src := range l.doc.Scenes {
+		s := &Scene{Name: src.Name}
+		for _, index := range src.Nodes {
+			if index < 0 || index >= len(l.model.Nodes) {
+				return fmt.Errorf("scene %d: node %d not found", i, index)
+			}
+			root := l.model.Nodes[index]
+			if root.Parent != nil {
+				return fmt.Errorf("scene %d: node %d is not a root node", i, index)
+			}
+			s.Nodes = append(s.Nodes, root)
+		}
+		l.model.Scenes = append(l.model.Scenes, s)
+	}
+	switch {
+	case l.doc.Scene != nil:
+		if *l.doc.Scene < 0 || *l.doc.Scene >= len(l.model.Scenes) {
+			return fmt.Errorf("default scene %d not found", *l.doc.Scene)
+		}
+		l.model.Scene = l.model.Scenes[*l.doc.Scene]
+	case len(l.model.Scenes) > 0:
+		l.model.Scene = l.model.Scenes[0]
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+module gfx
+
+go 1.16
+
+require (
+	github.com/go-gl/gl v0.0.0-20190320180904-bf2b1f2f34d7
+	github.com/go-gl/mathgl v0.0.0-20190713194549-592312d8590a
+)
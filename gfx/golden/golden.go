@@ -0,0 +1,110 @@
+/*
+黄金图像(golden image)比对
+将离屏渲染结果与提交在仓库中的PNG逐像素比较,
+不一致时输出差异图,便于定位改动带来的渲染变化
+*/
+
+package golden
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+)
+
+//Compare 逐像素比较got与want,任一通道差值超过tolerance的像素记为不一致
+//返回不一致的像素个数与差异图: 不一致像素标为红色,其余像素为变暗的期望图
+func Compare(got, want image.Image, tolerance uint8) (int, *image.RGBA, error) {
+	if got.Bounds().Size() != want.Bounds().Size() {
+		return 0, nil, fmt.Errorf("golden: size mismatch, got %v want %v",
+			got.Bounds().Size(), want.Bounds().Size())
+	}
+	g, w := toRGBA(got), toRGBA(want)
+	diff := image.NewRGBA(g.Bounds())
+	mismatched := 0
+	for i := 0; i < len(g.Pix); i += 4 {
+		bad := false
+		for c := 0; c < 4; c++ {
+			if absDiff(g.Pix[i+c], w.Pix[i+c]) > tolerance {
+				bad = true
+			}
+		}
+		if bad {
+			mismatched++
+			copy(diff.Pix[i:i+4], []uint8{255, 0, 0, 255})
+			continue
+		}
+		y := (uint32(w.Pix[i]) + uint32(w.Pix[i+1]) + uint32(w.Pix[i+2])) / 3
+		copy(diff.Pix[i:i+4], []uint8{uint8(y / 3), uint8(y / 3), uint8(y / 3), 255})
+	}
+	return mismatched, diff, nil
+}
+
+//Check 将got与dir/name.png比较
+//不一致时写出dir/name.got.png与dir/name.diff.png并返回错误
+func Check(dir, name string, got image.Image, tolerance uint8) error {
+	want, err := Load(filepath.Join(dir, name+".png"))
+	if err != nil {
+		return err
+	}
+	mismatched, diff, err := Compare(got, want, tolerance)
+	if err == nil && mismatched == 0 {
+		return nil
+	}
+	if werr := Save(filepath.Join(dir, name+".got.png"), got); werr != nil {
+		return werr
+	}
+	if err != nil {
+		return err
+	}
+	if werr := Save(filepath.Join(dir, name+".diff.png"), diff); werr != nil {
+		return werr
+	}
+	return fmt.Errorf("golden: %s: %d pixels differ by more than %d, see %s",
+		name, mismatched, tolerance, filepath.Join(dir, name+".diff.png"))
+}
+
+//Update 用got覆盖dir/name.png
+func Update(dir, name string, got image.Image) error {
+	return Save(filepath.Join(dir, name+".png"), got)
+}
+
+//Load 读取PNG图像
+func Load(file string) (image.Image, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return png.Decode(f)
+}
+
+//Save 将图像保存为PNG
+func Save(file string, img image.Image) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	return rgba
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
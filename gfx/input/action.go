@@ -0,0 +1,308 @@
+/*
+输入动作映射
+把按键、鼠标按键、手柄按键和手柄摇杆绑定到命名的动作(如 "MoveForward", "Quit"),
+程序只查询动作的状态, 不关心具体按了哪个键; 绑定可以从JSON配置读取并在运行时修改
+本包不依赖窗口库: 窗口层把事件转换成输入源名字后调用Set*, 每帧调用一次Update
+*/
+
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+//Device 输入设备类型
+type Device int
+
+// 输入设备
+const (
+	Key           Device = iota //键盘按键, 如 "W", "Escape", "Space", "Left"
+	MouseButton                 //鼠标按键, "Left", "Right", "Middle"
+	GamepadButton               //手柄按键, 如 "A", "Start", "DpadUp"
+	GamepadAxis                 //手柄轴, 如 "LeftX", "RightTrigger"
+)
+
+var deviceNames = []string{"key", "mouse", "button", "axis"}
+
+//ActiveThreshold 模拟量达到该值时动作视为按下
+const ActiveThreshold = 0.5
+
+//Source 一个输入源, 配置中写作 "设备:名字", 轴还要加上方向, 如 "key:W", "mouse:Left", "axis:LeftY-"
+type Source struct {
+	Device Device
+	Name   string
+	Sign   float32 //轴的方向, +1或-1, 按键为0
+}
+
+//ParseSource 解析 "设备:名字" 形式的输入源
+func ParseSource(s string) (Source, error) {
+	i := strings.IndexByte(s, ':')
+	if i < 0 {
+		return Source{}, fmt.Errorf("input source %q: want device:name", s)
+	}
+	device, name := s[:i], s[i+1:]
+	for d, dn := range deviceNames {
+		if dn != device {
+			continue
+		}
+		src := Source{Device: Device(d), Name: name}
+		if src.Device == GamepadAxis {
+			switch {
+			case strings.HasSuffix(name, "+"):
+				src.Sign = 1
+			case strings.HasSuffix(name, "-"):
+				src.Sign = -1
+			default:
+				return Source{}, fmt.Errorf("input source %q: axis needs a direction, + or -", s)
+			}
+			src.Name = name[:len(name)-1]
+		}
+		if src.Name == "" {
+			return Source{}, fmt.Errorf("input source %q: empty name", s)
+		}
+		return src, nil
+	}
+	return Source{}, fmt.Errorf("input source %q: unknown device %q (want key, mouse, button or axis)", s, device)
+}
+
+func (src Source) String() string {
+	s := deviceNames[src.Device] + ":" + src.Name
+	if src.Sign > 0 {
+		s += "+"
+	} else if src.Sign < 0 {
+		s += "-"
+	}
+	return s
+}
+
+//control 一个按键或轴的原始状态, 轴的两个方向共用
+type control struct {
+	device Device
+	name   string
+}
+
+//controlState 原始状态, latched 记录上次Update以来是否按下过, 保证按下又松开的短按不会丢失
+type controlState struct {
+	value   float32
+	latched bool
+}
+
+//actionState 动作在本帧与上一帧的值
+type actionState struct {
+	value, prev float32
+}
+
+//ActionMap 动作映射
+//Set* 记录原始输入, Update 计算各动作本帧的值; 查询只反映最近一次Update的结果
+type ActionMap struct {
+	bindings map[string][]Source
+	controls map[control]*controlState
+	actions  map[string]*actionState
+}
+
+//NewActionMap 创建空的动作映射
+func NewActionMap() *ActionMap {
+	return &ActionMap{
+		bindings: make(map[string][]Source),
+		controls: make(map[control]*controlState),
+		actions:  make(map[string]*actionState),
+	}
+}
+
+//ParseActionMap 从JSON解析动作映射, 格式为 动作名 -> 输入源列表:
+//{"MoveForward": ["key:W", "key:Up", "axis:LeftY-"], "Quit": ["key:Escape", "button:Back"]}
+func ParseActionMap(data []byte) (*ActionMap, error) {
+	var config map[string][]string
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	m := NewActionMap()
+	for action, sources := range config {
+		if err := m.Bind(action, sources...); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+//LoadActionMap 读取JSON格式的动作映射文件, 格式见ParseActionMap
+func LoadActionMap(file string) (*ActionMap, error) {
+	data, err := ioutil.ReadFile(file)
+	return loadActionMap(file, data, err)
+}
+
+//LoadActionMapFS 从fsys读取动作映射文件, 用于读取编译进程序的配置
+func LoadActionMapFS(fsys fs.FS, file string) (*ActionMap, error) {
+	data, err := fs.ReadFile(fsys, file)
+	return loadActionMap(file, data, err)
+}
+
+func loadActionMap(file string, data []byte, err error) (*ActionMap, error) {
+	if err != nil {
+		return nil, fmt.Errorf("load input file %s: %v", file, err)
+	}
+	m, err := ParseActionMap(data)
+	if err != nil {
+		return nil, fmt.Errorf("load input file %s: %v", file, err)
+	}
+	return m, nil
+}
+
+//MarshalJSON 按ParseActionMap的格式输出, 用于保存修改后的绑定
+func (m *ActionMap) MarshalJSON() ([]byte, error) {
+	config := make(map[string][]string, len(m.bindings))
+	for action, sources := range m.bindings {
+		names := make([]string, len(sources))
+		for i, src := range sources {
+			names[i] = src.String()
+		}
+		config[action] = names
+	}
+	return json.Marshal(config)
+}
+
+//Bind 给动作追加输入源, 同一个输入源可以绑定到多个动作
+func (m *ActionMap) Bind(action string, sources ...string) error {
+	for _, s := range sources {
+		src, err := ParseSource(s)
+		if err != nil {
+			return fmt.Errorf("bind %s: %v", action, err)
+		}
+		m.bindings[action] = append(m.bindings[action], src)
+	}
+	if _, ok := m.actions[action]; !ok {
+		m.actions[action] = &actionState{}
+	}
+	return nil
+}
+
+//Unbind 删除动作的所有绑定
+func (m *ActionMap) Unbind(action string) {
+	delete(m.bindings, action)
+}
+
+//Actions 按名字排序的所有动作
+func (m *ActionMap) Actions() []string {
+	actions := make([]string, 0, len(m.bindings))
+	for action := range m.bindings {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+//Bindings 动作绑定的输入源
+func (m *ActionMap) Bindings(action string) []Source {
+	return append([]Source(nil), m.bindings[action]...)
+}
+
+//SetKey 记录按键状态
+func (m *ActionMap) SetKey(name string, down bool) {
+	m.setButton(Key, name, down)
+}
+
+//SetMouseButton 记录鼠标按键状态
+func (m *ActionMap) SetMouseButton(name string, down bool) {
+	m.setButton(MouseButton, name, down)
+}
+
+//SetGamepadButton 记录手柄按键状态
+func (m *ActionMap) SetGamepadButton(name string, down bool) {
+	m.setButton(GamepadButton, name, down)
+}
+
+//SetAxis 记录手柄轴的值, 摇杆为[-1, 1], 扳机为[0, 1]
+func (m *ActionMap) SetAxis(name string, value float32) {
+	m.control(GamepadAxis, name).value = value
+}
+
+//Reset 清除所有原始输入, 例如窗口失去焦点时收不到松开事件
+func (m *ActionMap) Reset() {
+	for _, c := range m.controls {
+		*c = controlState{}
+	}
+}
+
+//Update 计算各动作本帧的值, 每帧在处理完窗口事件后调用一次
+func (m *ActionMap) Update() {
+	for action, state := range m.actions {
+		state.prev = state.value
+		state.value = 0
+		for _, src := range m.bindings[action] {
+			if v := m.sourceValue(src); v > state.value {
+				state.value = v
+			}
+		}
+	}
+	for _, c := range m.controls {
+		c.latched = false
+	}
+}
+
+//Value 动作的值[0, 1]: 按键为0或1, 轴为所绑定方向上的分量, 多个输入源取最大值
+func (m *ActionMap) Value(action string) float32 {
+	if state, ok := m.actions[action]; ok {
+		return state.value
+	}
+	return 0
+}
+
+//Held 动作当前是否处于按下状态
+func (m *ActionMap) Held(action string) bool {
+	return m.Value(action) >= ActiveThreshold
+}
+
+//Pressed 动作是否在本帧刚刚按下
+func (m *ActionMap) Pressed(action string) bool {
+	state, ok := m.actions[action]
+	return ok && state.value >= ActiveThreshold && state.prev < ActiveThreshold
+}
+
+//Released 动作是否在本帧刚刚松开
+func (m *ActionMap) Released(action string) bool {
+	state, ok := m.actions[action]
+	return ok && state.value < ActiveThreshold && state.prev >= ActiveThreshold
+}
+
+func (m *ActionMap) setButton(device Device, name string, down bool) {
+	c := m.control(device, name)
+	if down {
+		c.value = 1
+		c.latched = true
+	} else {
+		c.value = 0
+	}
+}
+
+func (m *ActionMap) control(device Device, name string) *controlState {
+	key := control{device, name}
+	c, ok := m.controls[key]
+	if !ok {
+		c = &controlState{}
+		m.controls[key] = c
+	}
+	return c
+}
+
+//sourceValue 输入源本帧的值, 上次Update以来按下过的按键即使已经松开也算一帧按下
+func (m *ActionMap) sourceValue(src Source) float32 {
+	c, ok := m.controls[control{src.Device, src.Name}]
+	if !ok {
+		return 0
+	}
+	if src.Device == GamepadAxis {
+		if v := c.value * src.Sign; v > 0 {
+			return v
+		}
+		return 0
+	}
+	if c.latched {
+		return 1
+	}
+	return c.value
+}
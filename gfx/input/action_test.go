@@ -0,0 +1,169 @@
+package input
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Source
+		err  string
+	}{
+		{"key:W", Source{Device: Key, Name: "W"}, ""},
+		{"mouse:Left", Source{Device: MouseButton, Name: "Left"}, ""},
+		{"button:DpadUp", Source{Device: GamepadButton, Name: "DpadUp"}, ""},
+		{"axis:LeftY-", Source{Device: GamepadAxis, Name: "LeftY", Sign: -1}, ""},
+		{"axis:RightTrigger+", Source{Device: GamepadAxis, Name: "RightTrigger", Sign: 1}, ""},
+		{"W", Source{}, "want device:name"},
+		{"pedal:Left", Source{}, "unknown device"},
+		{"axis:LeftY", Source{}, "axis needs a direction"},
+		{"axis:+", Source{}, "empty name"},
+		{"key:", Source{}, "empty name"},
+	}
+	for _, tt := range tests {
+		got, err := ParseSource(tt.in)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ParseSource(%q): error %v, want %q", tt.in, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSource(%q) = %+v, %v, want %+v", tt.in, got, err, tt.want)
+		}
+		if s := got.String(); s != tt.in {
+			t.Errorf("ParseSource(%q).String() = %q", tt.in, s)
+		}
+	}
+}
+
+//TestLatching 两次Update之间按下又松开的短按也算一帧按下, 下一帧报告松开
+func TestLatching(t *testing.T) {
+	m := NewActionMap()
+	if err := m.Bind("Jump", "key:Space", "button:A"); err != nil {
+		t.Fatal(err)
+	}
+	type frame struct {
+		events                  func()
+		held, pressed, released bool
+	}
+	frames := []frame{
+		{func() {}, false, false, false},
+		{func() { m.SetKey("Space", true) }, true, true, false},
+		{func() {}, true, false, false},
+		{func() { m.SetKey("Space", false) }, false, false, true},
+		{func() {}, false, false, false},
+		// 同一帧内按下又松开
+		{func() { m.SetKey("Space", true); m.SetKey("Space", false) }, true, true, false},
+		{func() {}, false, false, true},
+		// 按住时另一个输入源的短按不会产生新的Pressed
+		{func() { m.SetGamepadButton("A", true) }, true, true, false},
+		{func() { m.SetKey("Space", true); m.SetKey("Space", false) }, true, false, false},
+		{func() { m.SetGamepadButton("A", false) }, false, false, true},
+	}
+	for i, f := range frames {
+		f.events()
+		m.Update()
+		if m.Held("Jump") != f.held || m.Pressed("Jump") != f.pressed || m.Released("Jump") != f.released {
+			t.Errorf("frame %d: held %v pressed %v released %v, want %v %v %v", i,
+				m.Held("Jump"), m.Pressed("Jump"), m.Released("Jump"), f.held, f.pressed, f.released)
+		}
+	}
+}
+
+//TestAxisValue 轴只取绑定方向上的分量, 多个输入源取最大值, 达到ActiveThreshold时视为按下
+func TestAxisValue(t *testing.T) {
+	m, err := ParseActionMap([]byte(`{"Forward": ["axis:LeftY-", "key:W"], "Back": ["axis:LeftY+"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		axis          float32
+		key           bool
+		forward, back float32
+		held          bool
+	}{
+		{0, false, 0, 0, false},
+		{-0.3, false, 0.3, 0, false},
+		{-0.5, false, 0.5, 0, true},
+		{0.8, false, 0, 0.8, false},
+		{-0.3, true, 1, 0, true},
+	}
+	for _, tt := range tests {
+		m.SetAxis("LeftY", tt.axis)
+		m.SetKey("W", tt.key)
+		m.Update()
+		if m.Value("Forward") != tt.forward || m.Value("Back") != tt.back || m.Held("Forward") != tt.held {
+			t.Errorf("axis %v key %v: Forward %v Back %v held %v, want %v %v %v", tt.axis, tt.key,
+				m.Value("Forward"), m.Value("Back"), m.Held("Forward"), tt.forward, tt.back, tt.held)
+		}
+	}
+}
+
+func TestResetAndUnbind(t *testing.T) {
+	m := NewActionMap()
+	m.Bind("Fire", "mouse:Left")
+	m.SetMouseButton("Left", true)
+	m.Update()
+	if !m.Held("Fire") {
+		t.Fatal("Fire not held")
+	}
+	m.Reset()
+	m.Update()
+	if m.Held("Fire") || !m.Released("Fire") {
+		t.Error("Reset did not release Fire")
+	}
+
+	m.SetMouseButton("Left", true)
+	m.Unbind("Fire")
+	m.Update()
+	if m.Held("Fire") || len(m.Actions()) != 0 || m.Bindings("Fire") != nil {
+		t.Errorf("after Unbind: held %v actions %v bindings %v", m.Held("Fire"), m.Actions(), m.Bindings("Fire"))
+	}
+	if m.Value("Unknown") != 0 || m.Pressed("Unknown") || m.Released("Unknown") {
+		t.Error("unknown action is active")
+	}
+}
+
+//TestMarshal MarshalJSON的输出可以被ParseActionMap读回
+func TestMarshal(t *testing.T) {
+	m, err := ParseActionMap([]byte(`{"Quit": ["key:Escape", "button:Back"], "Up": ["axis:LeftY-"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := ParseActionMap(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again.Actions(), []string{"Quit", "Up"}) {
+		t.Errorf("actions %v", again.Actions())
+	}
+	for _, action := range m.Actions() {
+		if !reflect.DeepEqual(again.Bindings(action), m.Bindings(action)) {
+			t.Errorf("%s: bindings %v, want %v", action, again.Bindings(action), m.Bindings(action))
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	if _, err := ParseActionMap([]byte(`{"Quit": ["key:Escape", "axis:LeftX"]}`)); err == nil ||
+		!strings.Contains(err.Error(), "bind Quit") {
+		t.Errorf("bad source: error %v", err)
+	}
+	if _, err := ParseActionMap([]byte(`["key:Escape"]`)); err == nil {
+		t.Error("bad JSON: no error")
+	}
+	file := filepath.Join(t.TempDir(), "missing.json")
+	if _, err := LoadActionMap(file); err == nil || !strings.Contains(err.Error(), "load input file "+file) {
+		t.Errorf("missing file: error %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package input
+
+import (
+	"fmt"
+	"math"
+)
+
+// 手柄按键与轴的数量, 布局与GLFW(SDL)的标准手柄映射一致
+const (
+	GamepadAxes    = 6
+	GamepadButtons = 15
+)
+
+//AxisNames 手柄轴的名字, 下标与GamepadState.Axes一致, 配置中写作 "axis:名字+" 或 "axis:名字-"
+var AxisNames = [GamepadAxes]string{
+	"LeftX", "LeftY", "RightX", "RightY", "LeftTrigger", "RightTrigger",
+}
+
+//ButtonNames 手柄按键的名字, 下标与GamepadState.Buttons一致, 配置中写作 "button:名字"
+var ButtonNames = [GamepadButtons]string{
+	"A", "B", "X", "Y", "LeftBumper", "RightBumper", "Back", "Start", "Guide",
+	"LeftThumb", "RightThumb", "DpadUp", "DpadRight", "DpadDown", "DpadLeft",
+}
+
+// 轴的下标
+const (
+	AxisLeftX = iota
+	AxisLeftY
+	AxisRightX
+	AxisRightY
+	AxisLeftTrigger
+	AxisRightTrigger
+)
+
+//GamepadState 一帧的手柄原始状态, 可以从设备读取, 也可以是录制的数据
+type GamepadState struct {
+	Axes    [GamepadAxes]float32 //摇杆为[-1, 1], X向右、Y向下为正; 扳机为[-1, 1], 松开时为-1
+	Buttons [GamepadButtons]bool
+}
+
+//Response 模拟量的响应曲线
+type Response struct {
+	DeadZone float32 //死区[0, 1), 幅度小于它时视为0, 之外的部分重新映射到[0, 1], 输出从0开始连续变化
+	Exponent float32 //曲线指数, 1为线性, 大于1时小幅度推动更精细; 不大于0时按1处理
+}
+
+// 默认的响应曲线
+var (
+	DefaultStickResponse   = Response{DeadZone: 0.15, Exponent: 2}
+	DefaultTriggerResponse = Response{DeadZone: 0.05, Exponent: 1}
+)
+
+//Apply 把幅度v(超过1按1处理)经过死区与曲线映射到[0, 1]
+func (r Response) Apply(v float32) float32 {
+	if v <= r.DeadZone {
+		return 0
+	}
+	if v > 1 {
+		v = 1
+	}
+	v = (v - r.DeadZone) / (1 - r.DeadZone)
+	if r.Exponent > 0 && r.Exponent != 1 {
+		v = float32(math.Pow(float64(v), float64(r.Exponent)))
+	}
+	return v
+}
+
+//Stick 摇杆的二维映射: 死区与曲线作用在推动幅度上(径向死区), 方向保持不变,
+//斜向推动时不会像逐轴处理那样被吸附到坐标轴上
+func (r Response) Stick(x, y float32) (float32, float32) {
+	l := float32(math.Hypot(float64(x), float64(y)))
+	if l == 0 {
+		return 0, 0
+	}
+	s := r.Apply(l) / l
+	return x * s, y * s
+}
+
+//Trigger 扳机从[-1, 1]换算到[0, 1]后经过死区与曲线
+func (r Response) Trigger(v float32) float32 {
+	return r.Apply((v + 1) / 2)
+}
+
+//GamepadEvent 手柄连接或断开
+type GamepadEvent struct {
+	ID        int //设备编号, 对应GLFW的Joystick
+	Name      string
+	Connected bool
+}
+
+func (e GamepadEvent) String() string {
+	state := "disconnected"
+	if e.Connected {
+		state = "connected"
+	}
+	return fmt.Sprintf("gamepad %d (%s) %s", e.ID, e.Name, state)
+}
+
+//Gamepad 把手柄状态经过死区与响应曲线处理后写入ActionMap, 并跟踪手柄的热插拔
+//同一时间只使用一个手柄: 最先连接且仍然连接着的那个
+type Gamepad struct {
+	Stick   Response //两个摇杆的响应曲线
+	Trigger Response //两个扳机的响应曲线
+
+	actions *ActionMap
+	pads    []GamepadEvent //已连接的手柄, 按连接顺序
+	events  []GamepadEvent //Events取走之前的连接与断开事件
+}
+
+//NewGamepad 创建写入actions的手柄, 使用默认的响应曲线
+func NewGamepad(actions *ActionMap) *Gamepad {
+	return &Gamepad{
+		Stick:   DefaultStickResponse,
+		Trigger: DefaultTriggerResponse,
+		actions: actions,
+	}
+}
+
+//Connect 记录手柄连接, 已连接的手柄重复调用被忽略
+func (g *Gamepad) Connect(id int, name string) {
+	for _, p := range g.pads {
+		if p.ID == id {
+			return
+		}
+	}
+	e := GamepadEvent{ID: id, Name: name, Connected: true}
+	g.pads = append(g.pads, e)
+	g.events = append(g.events, e)
+}
+
+//Disconnect 记录手柄断开; 断开的是正在使用的手柄时松开它的所有按键和轴, 避免摄像机继续移动
+func (g *Gamepad) Disconnect(id int) {
+	for i, p := range g.pads {
+		if p.ID != id {
+			continue
+		}
+		if i == 0 {
+			g.release()
+		}
+		g.pads = append(g.pads[:i], g.pads[i+1:]...)
+		g.events = append(g.events, GamepadEvent{ID: id, Name: p.Name})
+		return
+	}
+}
+
+//Active 正在使用的手柄编号, 没有手柄时ok为false
+func (g *Gamepad) Active() (id int, ok bool) {
+	if len(g.pads) == 0 {
+		return 0, false
+	}
+	return g.pads[0].ID, true
+}
+
+//Events 取走上次调用以来的连接与断开事件
+func (g *Gamepad) Events() []GamepadEvent {
+	events := g.events
+	g.events = nil
+	return events
+}
+
+//Apply 处理正在使用的手柄本帧的状态并写入ActionMap, 在ActionMap.Update之前调用
+func (g *Gamepad) Apply(state GamepadState) {
+	lx, ly := g.Stick.Stick(state.Axes[AxisLeftX], state.Axes[AxisLeftY])
+	rx, ry := g.Stick.Stick(state.Axes[AxisRightX], state.Axes[AxisRightY])
+	axes := [GamepadAxes]float32{
+		lx, ly, rx, ry,
+		g.Trigger.Trigger(state.Axes[AxisLeftTrigger]),
+		g.Trigger.Trigger(state.Axes[AxisRightTrigger]),
+	}
+	for i, v := range axes {
+		g.actions.SetAxis(AxisNames[i], v)
+	}
+	for i, down := range state.Buttons {
+		g.actions.SetGamepadButton(ButtonNames[i], down)
+	}
+}
+
+//release 松开所有手柄按键, 轴回到中心
+func (g *Gamepad) release() {
+	for _, name := range AxisNames {
+		g.actions.SetAxis(name, 0)
+	}
+	for _, name := range ButtonNames {
+		g.actions.SetGamepadButton(name, false)
+	}
+}
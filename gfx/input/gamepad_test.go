@@ -0,0 +1,216 @@
+package input
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-5
+}
+
+func TestResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response Response
+		in, want float32
+	}{
+		{"inside the dead zone", Response{DeadZone: 0.2, Exponent: 1}, 0.15, 0},
+		{"on the dead zone", Response{DeadZone: 0.2, Exponent: 1}, 0.2, 0},
+		// 死区之外从0开始连续变化
+		{"just outside the dead zone", Response{DeadZone: 0.2, Exponent: 1}, 0.2001, 0.000125},
+		{"linear remap", Response{DeadZone: 0.2, Exponent: 1}, 0.6, 0.5},
+		{"full", Response{DeadZone: 0.2, Exponent: 1}, 1, 1},
+		{"clamped above 1", Response{DeadZone: 0.2, Exponent: 1}, 1.5, 1},
+		{"negative", Response{DeadZone: 0.2, Exponent: 1}, -0.8, 0},
+		{"squared", Response{DeadZone: 0, Exponent: 2}, 0.5, 0.25},
+		{"cubed after dead zone", Response{DeadZone: 0.5, Exponent: 3}, 0.75, 0.125},
+		{"square root", Response{DeadZone: 0, Exponent: 0.5}, 0.25, 0.5},
+		{"zero exponent is linear", Response{DeadZone: 0, Exponent: 0}, 0.3, 0.3},
+		{"negative exponent is linear", Response{DeadZone: 0, Exponent: -2}, 0.3, 0.3},
+		{"no dead zone", Response{}, 0.01, 0.01},
+	}
+	for _, tt := range tests {
+		if got := tt.response.Apply(tt.in); !near(got, tt.want) {
+			t.Errorf("%s: Apply(%v) = %v, want %v", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+//TestStick 径向死区作用在推动幅度上, 方向不变; 逐轴处理时同样的输入会被吸附到坐标轴
+func TestStick(t *testing.T) {
+	r := Response{DeadZone: 0.2, Exponent: 1}
+	tests := []struct {
+		name         string
+		x, y         float32
+		wantX, wantY float32
+	}{
+		{"center", 0, 0, 0, 0},
+		{"inside the radius", 0.1, 0.1, 0, 0},
+		{"along x", 0.6, 0, 0.5, 0},
+		{"along -y", 0, -1, 0, -1},
+		// 幅度0.5: (0.5-0.2)/0.8 = 0.375, 方向为(0.6, 0.8)
+		{"diagonal", 0.3, 0.4, 0.225, 0.3},
+		// 幅度0.5, 逐轴处理时y分量0.14在死区内会变成0
+		{"small component kept", 0.48, 0.14, 0.36, 0.105},
+		{"beyond the unit circle", 1, 1, float32(1 / math.Sqrt2), float32(1 / math.Sqrt2)},
+	}
+	for _, tt := range tests {
+		x, y := r.Stick(tt.x, tt.y)
+		if math.Abs(float64(x-tt.wantX)) > 1e-4 || math.Abs(float64(y-tt.wantY)) > 1e-4 {
+			t.Errorf("%s: Stick(%v, %v) = %v, %v, want %v, %v", tt.name, tt.x, tt.y, x, y, tt.wantX, tt.wantY)
+		}
+		// 输出方向与输入相同
+		if math.Abs(float64(x*tt.y-y*tt.x)) > 1e-5 || x*tt.x < 0 || y*tt.y < 0 {
+			t.Errorf("%s: Stick(%v, %v) = %v, %v changes the direction", tt.name, tt.x, tt.y, x, y)
+		}
+	}
+
+	// 指数作用在幅度上
+	x, y := Response{Exponent: 2}.Stick(0.3, 0.4)
+	if !near(x, 0.15) || !near(y, 0.2) {
+		t.Errorf("squared Stick(0.3, 0.4) = %v, %v, want 0.15, 0.2", x, y)
+	}
+}
+
+//TestTrigger 扳机从[-1, 1]换算到[0, 1]
+func TestTrigger(t *testing.T) {
+	tests := []struct {
+		response Response
+		in, want float32
+	}{
+		{Response{}, -1, 0},
+		{Response{}, 0, 0.5},
+		{Response{}, 1, 1},
+		{Response{DeadZone: 0.1}, -0.85, 0},
+		{Response{DeadZone: 0.5, Exponent: 1}, 0.5, 0.5},
+		{Response{Exponent: 2}, 0, 0.25},
+	}
+	for _, tt := range tests {
+		if got := tt.response.Trigger(tt.in); !near(got, tt.want) {
+			t.Errorf("%+v: Trigger(%v) = %v, want %v", tt.response, tt.in, got, tt.want)
+		}
+	}
+}
+
+//testActions 测试用的动作映射, 摇杆与扳机各方向分别绑定
+func testActions(t *testing.T) *ActionMap {
+	t.Helper()
+	m, err := ParseActionMap([]byte(`{
+		"Right": ["axis:LeftX+"], "Left": ["axis:LeftX-"],
+		"LookDown": ["axis:RightY+"], "LookUp": ["axis:RightY-"],
+		"Zoom": ["axis:RightTrigger+"],
+		"Jump": ["button:A"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestGamepadApply(t *testing.T) {
+	actions := testActions(t)
+	pad := NewGamepad(actions)
+	pad.Stick = Response{DeadZone: 0.2, Exponent: 1}
+	pad.Trigger = Response{Exponent: 1}
+
+	var state GamepadState
+	state.Axes[AxisLeftX] = -0.6
+	state.Axes[AxisRightY] = 1
+	state.Axes[AxisLeftTrigger] = -1
+	state.Axes[AxisRightTrigger] = 0
+	state.Buttons[0] = true
+	pad.Apply(state)
+	actions.Update()
+
+	want := map[string]float32{"Left": 0.5, "Right": 0, "LookDown": 1, "LookUp": 0, "Zoom": 0.5, "Jump": 1}
+	for action, v := range want {
+		if got := actions.Value(action); !near(got, v) {
+			t.Errorf("%s = %v, want %v", action, got, v)
+		}
+	}
+	if !actions.Pressed("Jump") {
+		t.Error("Jump not pressed")
+	}
+
+	// 摇杆松开后回到死区内, 扳机松开为-1
+	state = GamepadState{}
+	state.Axes[AxisLeftX] = 0.1
+	state.Axes[AxisRightTrigger] = -1
+	pad.Apply(state)
+	actions.Update()
+	for _, action := range []string{"Left", "Right", "LookDown", "Zoom", "Jump"} {
+		if got := actions.Value(action); got != 0 {
+			t.Errorf("released: %s = %v, want 0", action, got)
+		}
+	}
+	if !actions.Released("Jump") {
+		t.Error("Jump not released")
+	}
+}
+
+func TestGamepadConnect(t *testing.T) {
+	pad := NewGamepad(NewActionMap())
+	if _, ok := pad.Active(); ok {
+		t.Error("active pad before any connection")
+	}
+	pad.Connect(2, "pad two")
+	pad.Connect(0, "pad zero")
+	pad.Connect(2, "pad two") // 重复连接被忽略
+	if id, ok := pad.Active(); !ok || id != 2 {
+		t.Errorf("active %d %v, want the first connected pad 2", id, ok)
+	}
+	events := pad.Events()
+	if len(events) != 2 || events[0] != (GamepadEvent{2, "pad two", true}) || events[1] != (GamepadEvent{0, "pad zero", true}) {
+		t.Errorf("events %v", events)
+	}
+	if events := pad.Events(); len(events) != 0 {
+		t.Errorf("events %v after they were taken", events)
+	}
+
+	pad.Disconnect(5) // 未连接的手柄
+	pad.Disconnect(2)
+	if id, ok := pad.Active(); !ok || id != 0 {
+		t.Errorf("active %d %v after disconnecting 2, want 0", id, ok)
+	}
+	pad.Disconnect(0)
+	if _, ok := pad.Active(); ok {
+		t.Error("active pad after all were disconnected")
+	}
+	events = pad.Events()
+	if len(events) != 2 || events[0] != (GamepadEvent{2, "pad two", false}) || events[1] != (GamepadEvent{0, "pad zero", false}) {
+		t.Errorf("events %v", events)
+	}
+	if s := events[0].String(); s != "gamepad 2 (pad two) disconnected" {
+		t.Errorf("event string %q", s)
+	}
+}
+
+//TestGamepadDisconnect 断开正在使用的手柄时松开所有输入, 断开其他手柄时保持不变
+func TestGamepadDisconnect(t *testing.T) {
+	actions := testActions(t)
+	pad := NewGamepad(actions)
+	pad.Connect(0, "first")
+	pad.Connect(1, "second")
+
+	var state GamepadState
+	state.Axes[AxisLeftX] = 1
+	state.Buttons[0] = true
+	pad.Apply(state)
+	actions.Update()
+
+	pad.Disconnect(1)
+	actions.Update()
+	if !actions.Held("Right") || !actions.Held("Jump") {
+		t.Error("disconnecting an unused pad released the inputs")
+	}
+
+	pad.Disconnect(0)
+	actions.Update()
+	if actions.Value("Right") != 0 || actions.Held("Jump") {
+		t.Errorf("after disconnect: Right %v Jump %v, want released", actions.Value("Right"), actions.Held("Jump"))
+	}
+	if !actions.Released("Jump") {
+		t.Error("Jump not reported as released")
+	}
+}
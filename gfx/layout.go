@@ -0,0 +1,140 @@
+/*
+顶点布局
+按顺序列出交错顶点缓冲中的各个属性, 第i个属性对应着色器中的 layout (location = i),
+由布局计算步长与偏移并设置顶点属性指针,
+同一个布局也可以设置软件渲染上下文, 使黄金图像测试与例程共用顶点属性的设置
+*/
+
+package gfx
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+
+	"gfx/soft"
+)
+
+//Attribute 顶点属性: 着色器中的变量名、分量个数与分量类型
+type Attribute struct {
+	Name       string
+	Size       int32  // 分量个数, 1-4
+	Type       uint32 // 分量类型, 如:gl.FLOAT, gl.UNSIGNED_BYTE
+	Normalized bool   // 整数类型是否归一化到[0,1]或[-1,1]
+	Integer    bool   // 是否作为整数传给 int/ivec/uvec 输入(gl.VertexAttribIPointer)
+}
+
+//FloatAttrib 由size个float组成的顶点属性
+func FloatAttrib(name string, size int32) Attribute {
+	return Attribute{Name: name, Size: size, Type: gl.FLOAT}
+}
+
+//VertexLayout 交错顶点缓冲的布局
+type VertexLayout struct {
+	Attributes []Attribute
+	offsets    []int
+	stride     int32
+}
+
+//NewVertexLayout 按顺序排列的属性生成布局, 属性之间没有填充
+func NewVertexLayout(attribs ...Attribute) *VertexLayout {
+	layout := &VertexLayout{Attributes: attribs}
+	offset := 0
+	for _, attrib := range attribs {
+		layout.offsets = append(layout.offsets, offset)
+		offset += int(attrib.Size) * typeSize(attrib.Type)
+	}
+	layout.stride = int32(offset)
+	return layout
+}
+
+//Stride 一个顶点占用的字节数
+func (layout *VertexLayout) Stride() int32 {
+	return layout.stride
+}
+
+//Offset 第i个属性在顶点内的字节偏移
+func (layout *VertexLayout) Offset(i int) int {
+	return layout.offsets[i]
+}
+
+//Floats 一个顶点占用的float个数, 只在全部属性为gl.FLOAT时有意义
+func (layout *VertexLayout) Floats() int {
+	return int(layout.stride) / 4
+}
+
+//Apply 为当前绑定的VAO与VBO设置并启用全部顶点属性指针
+func (layout *VertexLayout) Apply() {
+	for i, attrib := range layout.Attributes {
+		if attrib.Integer {
+			gl.VertexAttribIPointer(uint32(i), attrib.Size, attrib.Type,
+				layout.stride, gl.PtrOffset(layout.offsets[i]))
+		} else {
+			gl.VertexAttribPointer(uint32(i), attrib.Size, attrib.Type, attrib.Normalized,
+				layout.stride, gl.PtrOffset(layout.offsets[i]))
+		}
+		gl.EnableVertexAttribArray(uint32(i))
+	}
+}
+
+//ApplySoft 与Apply相同, 在软件渲染上下文中为当前绑定的VAO与VBO设置并启用全部顶点属性指针
+//软件渲染只支持未归一化的float分量, 其他属性返回错误
+func (layout *VertexLayout) ApplySoft(ctx *soft.Context) error {
+	for i, attrib := range layout.Attributes {
+		if attrib.Type != gl.FLOAT || attrib.Integer || attrib.Normalized {
+			return fmt.Errorf("vertex layout: attribute %s: software renderer supports float attributes only", attrib.Name)
+		}
+		ctx.VertexAttribPointer(uint32(i), attrib.Size, int(layout.stride), layout.offsets[i])
+		ctx.EnableVertexAttribArray(uint32(i))
+	}
+	return nil
+}
+
+//Check 检查布局与着色器程序的活动属性是否一致:
+//每个活动属性都必须出现在布局中, location 与其在布局中的下标相同, 且整数输入与Integer标志一致
+//布局中有而着色器中没有的属性(可能被编译器优化掉)不算错误
+func (layout *VertexLayout) Check(prog *Program) error {
+	for _, active := range prog.attribs {
+		index := -1
+		for i, attrib := range layout.Attributes {
+			if attrib.Name == active.Name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return fmt.Errorf("vertex layout: attribute %s (location %d) not in layout", active.Name, active.Location)
+		}
+		if active.Location != int32(index) {
+			return fmt.Errorf("vertex layout: attribute %s is at location %d in shader, %d in layout",
+				active.Name, active.Location, index)
+		}
+		if isIntegerType(active.Type) != layout.Attributes[index].Integer {
+			return fmt.Errorf("vertex layout: attribute %s: integer input mismatch between shader and layout", active.Name)
+		}
+	}
+	return nil
+}
+
+//typeSize 分量类型的字节数
+func typeSize(glType uint32) int {
+	switch glType {
+	case gl.BYTE, gl.UNSIGNED_BYTE:
+		return 1
+	case gl.SHORT, gl.UNSIGNED_SHORT, gl.HALF_FLOAT:
+		return 2
+	case gl.DOUBLE:
+		return 8
+	}
+	return 4 // gl.FLOAT, gl.INT, gl.UNSIGNED_INT, gl.FIXED
+}
+
+//isIntegerType 着色器变量类型是否为整数
+func isIntegerType(glType uint32) bool {
+	switch glType {
+	case gl.INT, gl.INT_VEC2, gl.INT_VEC3, gl.INT_VEC4,
+		gl.UNSIGNED_INT, gl.UNSIGNED_INT_VEC2, gl.UNSIGNED_INT_VEC3, gl.UNSIGNED_INT_VEC4:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package mesh
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//Icosphere 由正二十面体细分subdivisions次得到的球
+//三角形数为 20*4^subdivisions, 经线接缝处的顶点会被复制以保证纹理坐标连续
+//subdivisions 小于0时按0处理, 即正二十面体本身
+func Icosphere(radius float32, subdivisions int) *Geometry {
+	subdivisions = atLeast(subdivisions, 0)
+	t := float32((1 + math.Sqrt(5)) / 2)
+	dirs := []mgl32.Vec3{
+		{-1, t, 0}, {1, t, 0}, {-1, -t, 0}, {1, -t, 0},
+		{0, -1, t}, {0, 1, t}, {0, -1, -t}, {0, 1, -t},
+		{t, 0, -1}, {t, 0, 1}, {-t, 0, -1}, {-t, 0, 1},
+	}
+	for i := range dirs {
+		dirs[i] = dirs[i].Normalize()
+	}
+	faces := []uint32{
+		0, 11, 5, 0, 5, 1, 0, 1, 7, 0, 7, 10, 0, 10, 11,
+		1, 5, 9, 5, 11, 4, 11, 10, 2, 10, 7, 6, 7, 1, 8,
+		3, 9, 4, 3, 4, 2, 3, 2, 6, 3, 6, 8, 3, 8, 9,
+		4, 9, 5, 2, 4, 11, 6, 2, 10, 8, 6, 7, 9, 8, 1,
+	}
+
+	for s := 0; s < subdivisions; s++ {
+		midpoints := make(map[[2]uint32]uint32)
+		midpoint := func(a, b uint32) uint32 {
+			key := [2]uint32{a, b}
+			if a > b {
+				key = [2]uint32{b, a}
+			}
+			if m, ok := midpoints[key]; ok {
+				return m
+			}
+			dirs = append(dirs, dirs[a].Add(dirs[b]).Normalize())
+			m := uint32(len(dirs) - 1)
+			midpoints[key] = m
+			return m
+		}
+		next := make([]uint32, 0, len(faces)*4)
+		for i := 0; i < len(faces); i += 3 {
+			a, b, c := faces[i], faces[i+1], faces[i+2]
+			ab, bc, ca := midpoint(a, b), midpoint(b, c), midpoint(c, a)
+			next = append(next, a, ab, ca, b, bc, ab, c, ca, bc, ab, bc, ca)
+		}
+		faces = next
+	}
+
+	g := &Geometry{}
+	for _, d := range dirs {
+		g.addVertex(d.Mul(radius), d, sphericalUV(d))
+	}
+	// 跨越u=0/1接缝的三角形: 复制u较小的顶点并加1
+	seam := make(map[uint32]uint32)
+	for i := 0; i < len(faces); i += 3 {
+		tri := faces[i : i+3]
+		maxU := float32(0)
+		for _, v := range tri {
+			if u := g.UVs[v].X(); u > maxU {
+				maxU = u
+			}
+		}
+		for k, v := range tri {
+			if maxU-g.UVs[v].X() <= 0.5 {
+				continue
+			}
+			dup, ok := seam[v]
+			if !ok {
+				uv := g.UVs[v]
+				dup = g.addVertex(g.Positions[v], g.Normals[v], mgl32.Vec2{uv.X() + 1, uv.Y()})
+				seam[v] = dup
+			}
+			tri[k] = dup
+		}
+	}
+	g.Indices = faces
+	g.ComputeTangents()
+	return g
+}
+
+//sphericalUV 单位方向的经纬纹理坐标,与UVSphere的参数化一致
+func sphericalUV(d mgl32.Vec3) mgl32.Vec2 {
+	u := math.Atan2(float64(-d.Z()), float64(d.X())) / (2 * math.Pi)
+	if u < 0 {
+		u++
+	}
+	v := 1 - math.Acos(float64(mgl32.Clamp(d.Y(), -1, 1)))/math.Pi
+	return mgl32.Vec2{float32(u), float32(v)}
+}
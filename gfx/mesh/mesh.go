@@ -0,0 +1,150 @@
+/*
+程序化网格生成
+所有生成函数输出位置、法线、切线、纹理坐标与索引,
+三角形以逆时针(CCW)为正面,法线朝外,纹理坐标原点在左下角
+*/
+
+package mesh
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//Geometry 网格几何数据,各顶点属性切片长度一致
+type Geometry struct {
+	Positions []mgl32.Vec3
+	Normals   []mgl32.Vec3
+	Tangents  []mgl32.Vec4 // xyz为切线, w为副切线方向(±1): bitangent = w * cross(normal, tangent)
+	UVs       []mgl32.Vec2
+	Indices   []uint32
+}
+
+//Attrib 顶点属性选择位,用于Interleave
+type Attrib uint8
+
+// 可交错输出的顶点属性,输出顺序与声明顺序一致
+const (
+	Position Attrib = 1 << iota // 3个float
+	Normal                      // 3个float
+	UV                          // 2个float
+	Tangent                     // 4个float
+)
+
+//Stride 所选属性的一个顶点占用的float个数
+func (attribs Attrib) Stride() int {
+	n := 0
+	if attribs&Position != 0 {
+		n += 3
+	}
+	if attribs&Normal != 0 {
+		n += 3
+	}
+	if attribs&UV != 0 {
+		n += 2
+	}
+	if attribs&Tangent != 0 {
+		n += 4
+	}
+	return n
+}
+
+//VertexCount 顶点个数
+func (g *Geometry) VertexCount() int {
+	return len(g.Positions)
+}
+
+//Interleave 按 位置/法线/纹理坐标/切线 的顺序交错输出所选属性,
+//可直接配合索引传给 gfx.NewMesh
+func (g *Geometry) Interleave(attribs Attrib) []float32 {
+	out := make([]float32, 0, g.VertexCount()*attribs.Stride())
+	for i := range g.Positions {
+		if attribs&Position != 0 {
+			out = append(out, g.Positions[i][:]...)
+		}
+		if attribs&Normal != 0 {
+			out = append(out, g.Normals[i][:]...)
+		}
+		if attribs&UV != 0 {
+			out = append(out, g.UVs[i][:]...)
+		}
+		if attribs&Tangent != 0 {
+			out = append(out, g.Tangents[i][:]...)
+		}
+	}
+	return out
+}
+
+func (g *Geometry) addVertex(pos, normal mgl32.Vec3, uv mgl32.Vec2) uint32 {
+	g.Positions = append(g.Positions, pos)
+	g.Normals = append(g.Normals, normal)
+	g.UVs = append(g.UVs, uv)
+	return uint32(len(g.Positions) - 1)
+}
+
+func (g *Geometry) addTriangle(a, b, c uint32) {
+	g.Indices = append(g.Indices, a, b, c)
+}
+
+//ComputeTangents 根据纹理坐标的变化方向重新计算每个顶点的切线
+//要求Positions、Normals、UVs与Indices已填好
+func (g *Geometry) ComputeTangents() {
+	tan := make([]mgl32.Vec3, len(g.Positions))
+	bitan := make([]mgl32.Vec3, len(g.Positions))
+	for i := 0; i+2 < len(g.Indices); i += 3 {
+		a, b, c := g.Indices[i], g.Indices[i+1], g.Indices[i+2]
+		e1 := g.Positions[b].Sub(g.Positions[a])
+		e2 := g.Positions[c].Sub(g.Positions[a])
+		d1 := g.UVs[b].Sub(g.UVs[a])
+		d2 := g.UVs[c].Sub(g.UVs[a])
+		det := d1.X()*d2.Y() - d2.X()*d1.Y()
+		if det == 0 {
+			continue
+		}
+		r := 1 / det
+		t := e1.Mul(d2.Y()).Sub(e2.Mul(d1.Y())).Mul(r)
+		bt := e2.Mul(d1.X()).Sub(e1.Mul(d2.X())).Mul(r)
+		for _, v := range []uint32{a, b, c} {
+			tan[v] = tan[v].Add(t)
+			bitan[v] = bitan[v].Add(bt)
+		}
+	}
+
+	g.Tangents = make([]mgl32.Vec4, len(g.Positions))
+	for i, n := range g.Normals {
+		// Gram-Schmidt 正交化
+		t := tan[i].Sub(n.Mul(n.Dot(tan[i])))
+		if t.Len() < 1e-6 {
+			t = perpendicular(n)
+		}
+		t = t.Normalize()
+		w := float32(1)
+		if n.Cross(t).Dot(bitan[i]) < 0 {
+			w = -1
+		}
+		g.Tangents[i] = t.Vec4(w)
+	}
+}
+
+//perpendicular 返回与n垂直的任一单位向量
+func perpendicular(n mgl32.Vec3) mgl32.Vec3 {
+	axis := mgl32.Vec3{1, 0, 0}
+	if abs(n.X()) > 0.9 {
+		axis = mgl32.Vec3{0, 1, 0}
+	}
+	return n.Cross(axis).Normalize()
+}
+
+//atLeast 分段数n小于min时返回min, 避免0分段时除以0得到NaN或空网格
+func atLeast(n, min int) int {
+	if n < min {
+		return min
+	}
+	return n
+}
+
+func abs(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
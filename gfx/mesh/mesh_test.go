@@ -0,0 +1,167 @@
+package mesh
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+var shapes = []struct {
+	name     string
+	geometry *Geometry
+	vertices int  //期望的顶点数, 为0时不检查
+	indices  int  //期望的索引数
+	convex   bool //凸体: 每个面的法线还应背向原点
+}{
+	{"Cube", Cube(2), 24, 36, true},
+	{"Plane", Plane(2, 3, 4, 5), 5 * 6, 4 * 5 * 6, false},
+	{"UVSphere", UVSphere(1, 16, 8), 17 * 9, 3 * 16 * (2*8 - 2), true},
+	{"Cylinder", Cylinder(1, 2, 12), 6 * 13, 12 * 12, true},
+	{"Cone", Cone(1, 2, 12), 4 * 13, 6 * 12, true},
+	{"Capsule", Capsule(0.5, 1, 12, 4), 2 * 5 * 13, 12 * 12 * 4, true},
+	{"Torus", Torus(1, 0.25, 16, 8), 17 * 9, 16 * 8 * 6, false},
+	{"Icosphere", Icosphere(1, 2), 0, 60 * 16, true},
+}
+
+//TestCounts 顶点与索引个数, 各属性切片长度一致, 索引不越界
+func TestCounts(t *testing.T) {
+	for _, s := range shapes {
+		g := s.geometry
+		if s.vertices != 0 && g.VertexCount() != s.vertices {
+			t.Errorf("%s: %d vertices, want %d", s.name, g.VertexCount(), s.vertices)
+		}
+		if len(g.Indices) != s.indices {
+			t.Errorf("%s: %d indices, want %d", s.name, len(g.Indices), s.indices)
+		}
+		n := g.VertexCount()
+		if len(g.Normals) != n || len(g.UVs) != n || len(g.Tangents) != n {
+			t.Errorf("%s: attribute lengths %d/%d/%d, want %d",
+				s.name, len(g.Normals), len(g.UVs), len(g.Tangents), n)
+		}
+		for _, i := range g.Indices {
+			if int(i) >= n {
+				t.Errorf("%s: index %d out of range [0, %d)", s.name, i, n)
+				break
+			}
+		}
+	}
+}
+
+//TestIcosphereVertices 去掉接缝处复制的顶点后, 细分s次的二十面体有10*4^s+2个顶点
+func TestIcosphereVertices(t *testing.T) {
+	for s := 0; s <= 3; s++ {
+		g := Icosphere(1, s)
+		unique := map[mgl32.Vec3]bool{}
+		for _, p := range g.Positions {
+			unique[p] = true
+		}
+		want := 10*int(math.Pow(4, float64(s))) + 2
+		if len(unique) != want {
+			t.Errorf("subdivisions %d: %d unique vertices, want %d", s, len(unique), want)
+		}
+	}
+}
+
+//TestNormals 法线与切线为单位向量, 切线与法线垂直
+func TestNormals(t *testing.T) {
+	for _, s := range shapes {
+		g := s.geometry
+		for i, n := range g.Normals {
+			if l := n.Len(); math.Abs(float64(l)-1) > 1e-4 {
+				t.Errorf("%s: |normal %d| = %v", s.name, i, l)
+				break
+			}
+			tan := g.Tangents[i].Vec3()
+			if l := tan.Len(); math.Abs(float64(l)-1) > 1e-4 {
+				t.Errorf("%s: |tangent %d| = %v", s.name, i, l)
+				break
+			}
+			if d := tan.Dot(n); math.Abs(float64(d)) > 1e-3 {
+				t.Errorf("%s: tangent %d not perpendicular to normal, dot %v", s.name, i, d)
+				break
+			}
+		}
+	}
+}
+
+//TestWinding 三角形逆时针为正面: 由顶点顺序得到的面法线与顶点法线同向, 凸体的面法线朝外
+func TestWinding(t *testing.T) {
+	for _, s := range shapes {
+		g := s.geometry
+		for i := 0; i+2 < len(g.Indices); i += 3 {
+			a, b, c := g.Indices[i], g.Indices[i+1], g.Indices[i+2]
+			pa, pb, pc := g.Positions[a], g.Positions[b], g.Positions[c]
+			face := pb.Sub(pa).Cross(pc.Sub(pa))
+			if face.Len() < 1e-9 {
+				t.Errorf("%s: triangle %d is degenerate", s.name, i/3)
+				break
+			}
+			normal := g.Normals[a].Add(g.Normals[b]).Add(g.Normals[c])
+			if face.Dot(normal) <= 0 {
+				t.Errorf("%s: triangle %d winds against its normals", s.name, i/3)
+				break
+			}
+			center := pa.Add(pb).Add(pc).Mul(1.0 / 3)
+			if s.convex && face.Dot(center) <= 0 {
+				t.Errorf("%s: triangle %d faces inwards", s.name, i/3)
+				break
+			}
+		}
+	}
+}
+
+//TestMinimumSegments 分段数为0或负数时按最小值生成, 不产生NaN或空网格
+func TestMinimumSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		geometry *Geometry
+		want     *Geometry
+	}{
+		{"Plane", Plane(1, 1, 0, -1), Plane(1, 1, 1, 1)},
+		{"UVSphere", UVSphere(1, 0, 0), UVSphere(1, 3, 2)},
+		{"Cylinder", Cylinder(1, 1, 0), Cylinder(1, 1, 3)},
+		{"Cone", Cone(1, 1, -2), Cone(1, 1, 3)},
+		{"Capsule", Capsule(1, 1, 0, 0), Capsule(1, 1, 3, 1)},
+		{"Torus", Torus(1, 0.25, 0, 0), Torus(1, 0.25, 3, 3)},
+		{"Icosphere", Icosphere(1, -1), Icosphere(1, 0)},
+	}
+	for _, tt := range tests {
+		g := tt.geometry
+		if len(g.Indices) == 0 || len(g.Indices) != len(tt.want.Indices) || g.VertexCount() != tt.want.VertexCount() {
+			t.Errorf("%s: %d vertices %d indices, want %d and %d", tt.name,
+				g.VertexCount(), len(g.Indices), tt.want.VertexCount(), len(tt.want.Indices))
+		}
+		for i, p := range g.Positions {
+			if isNaN(p) || isNaN(g.Normals[i]) {
+				t.Errorf("%s: vertex %d is NaN", tt.name, i)
+				break
+			}
+		}
+	}
+}
+
+//TestInterleave 交错输出的长度与各属性的位置
+func TestInterleave(t *testing.T) {
+	g := Cube(2)
+	attribs := Position | UV | Tangent
+	if attribs.Stride() != 9 {
+		t.Fatalf("stride %d, want 9", attribs.Stride())
+	}
+	out := g.Interleave(attribs)
+	if len(out) != g.VertexCount()*9 {
+		t.Fatalf("%d floats, want %d", len(out), g.VertexCount()*9)
+	}
+	for i := range g.Positions {
+		v := out[i*9 : i*9+9]
+		if (mgl32.Vec3{v[0], v[1], v[2]}) != g.Positions[i] ||
+			(mgl32.Vec2{v[3], v[4]}) != g.UVs[i] ||
+			(mgl32.Vec4{v[5], v[6], v[7], v[8]}) != g.Tangents[i] {
+			t.Fatalf("vertex %d interleaved as %v", i, v)
+		}
+	}
+}
+
+func isNaN(v mgl32.Vec3) bool {
+	return v.X() != v.X() || v.Y() != v.Y() || v.Z() != v.Z()
+}
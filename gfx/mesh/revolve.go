@@ -0,0 +1,143 @@
+package mesh
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//profilePoint 旋转体轮廓上的一点(自上而下排列)
+//r,y 为到Y轴的距离与高度, nr,ny 为该点法线在径向与Y方向的分量, v 为纹理坐标v
+//位置与法线用float64保存, 旋转后才转换为float32, 避免两次舍入
+type profilePoint struct {
+	r, y   float64
+	nr, ny float64
+	v      float32
+}
+
+//revolve 将轮廓绕Y轴旋转一周生成网格,segments为经向分段数
+//经度角phi从+X转向-Z,使纹理坐标u在物体外侧从左到右递增
+//角度与最初的sphere例程一样先按float32计算, 使UVSphere生成的顶点与该例程相同
+func revolve(profile []profilePoint, segments int) *Geometry {
+	g := &Geometry{}
+	for _, p := range profile {
+		for j := 0; j <= segments; j++ {
+			u := float32(j) / float32(segments)
+			phi := float64(u * 2 * math.Pi)
+			cos, sin := math.Cos(phi), math.Sin(phi)
+			pos := mgl32.Vec3{float32(p.r * cos), float32(p.y), float32(-p.r * sin)}
+			normal := mgl32.Vec3{float32(p.nr * cos), float32(p.ny), float32(-p.nr * sin)}.Normalize()
+			g.addVertex(pos, normal, mgl32.Vec2{u, p.v})
+		}
+	}
+
+	row := segments + 1
+	for i := 0; i+1 < len(profile); i++ {
+		top, bottom := profile[i], profile[i+1]
+		if top.r == bottom.r && top.y == bottom.y {
+			continue // 法线突变处的重复环,面积为0
+		}
+		for j := 0; j < segments; j++ {
+			v00 := uint32(i*row + j)
+			v01 := v00 + 1
+			v10 := uint32((i+1)*row + j)
+			v11 := v10 + 1
+			if !onAxis(bottom) {
+				g.addTriangle(v00, v10, v11)
+			}
+			if !onAxis(top) {
+				g.addTriangle(v00, v11, v01)
+			}
+		}
+	}
+	g.ComputeTangents()
+	return g
+}
+
+//onAxis 轮廓点是否落在旋转轴上(sin(π)等不严格为0,需容差)
+func onAxis(p profilePoint) bool {
+	return math.Abs(p.r) <= 1e-6*(math.Abs(p.y)+1)
+}
+
+//UVSphere 经纬球, xSegments 经向分段数(至少为3), ySegments 纬向分段数(至少为2)
+//顶点按纬线自上而下排列,共(xSegments+1)*(ySegments+1)个
+func UVSphere(radius float32, xSegments, ySegments int) *Geometry {
+	xSegments, ySegments = atLeast(xSegments, 3), atLeast(ySegments, 2)
+	profile := make([]profilePoint, 0, ySegments+1)
+	for y := 0; y <= ySegments; y++ {
+		ySegment := float32(y) / float32(ySegments)
+		theta := float64(ySegment * math.Pi)
+		sin, cos := math.Sin(theta), math.Cos(theta)
+		profile = append(profile, profilePoint{
+			r: float64(radius) * sin, y: float64(radius) * cos,
+			nr: sin, ny: cos,
+			v: 1 - ySegment,
+		})
+	}
+	return revolve(profile, xSegments)
+}
+
+//Cylinder 圆柱,中心在原点,轴沿Y, 带上下底面, segments 至少为3
+func Cylinder(radius, height float32, segments int) *Geometry {
+	return frustum(radius, radius, height, segments)
+}
+
+//Cone 圆锥,中心在原点,顶点朝+Y, 带底面, segments 至少为3
+func Cone(radius, height float32, segments int) *Geometry {
+	return frustum(radius, 0, height, segments)
+}
+
+//frustum 圆台,topRadius为0时为圆锥
+func frustum(bottomRadius, topRadius, height float32, segments int) *Geometry {
+	segments = atLeast(segments, 3)
+	r0, r1, h := float64(bottomRadius), float64(topRadius), float64(height)/2
+	// 侧面法线: 轮廓方向为(bottomRadius-topRadius, -height)的法向
+	l := math.Hypot(float64(height), r0-r1)
+	slope := [2]float64{float64(height) / l, (r0 - r1) / l}
+	var profile []profilePoint
+	if topRadius > 0 {
+		profile = append(profile,
+			profilePoint{r: 0, y: h, nr: 0, ny: 1, v: 1},
+			profilePoint{r: r1, y: h, nr: 0, ny: 1, v: 0},
+		)
+	}
+	profile = append(profile,
+		profilePoint{r: r1, y: h, nr: slope[0], ny: slope[1], v: 1},
+		profilePoint{r: r0, y: -h, nr: slope[0], ny: slope[1], v: 0},
+		profilePoint{r: r0, y: -h, nr: 0, ny: -1, v: 0},
+		profilePoint{r: 0, y: -h, nr: 0, ny: -1, v: 1},
+	)
+	return revolve(profile, segments)
+}
+
+//Capsule 胶囊体: 半径radius的两个半球由高height的圆柱相连,
+//segments 为经向分段数(至少为3), rings 为每个半球的纬向分段数(至少为1)
+func Capsule(radius, height float32, segments, rings int) *Geometry {
+	segments, rings = atLeast(segments, 3), atLeast(rings, 1)
+	r, h := float64(radius), float64(height)/2
+	// v 按轮廓弧长分配
+	total := float32(math.Pi)*radius + height
+	quarter := float32(math.Pi) * radius / 2
+	profile := make([]profilePoint, 0, 2*(rings+1))
+	for k := 0; k <= rings; k++ {
+		t := float32(k) / float32(rings)
+		theta := float64(t) * math.Pi / 2
+		sin, cos := math.Sin(theta), math.Cos(theta)
+		profile = append(profile, profilePoint{
+			r: r * sin, y: h + r*cos,
+			nr: sin, ny: cos,
+			v: 1 - t*quarter/total,
+		})
+	}
+	for k := 0; k <= rings; k++ {
+		t := float32(k) / float32(rings)
+		theta := math.Pi/2 + float64(t)*math.Pi/2
+		sin, cos := math.Sin(theta), math.Cos(theta)
+		profile = append(profile, profilePoint{
+			r: r * sin, y: -h + r*cos,
+			nr: sin, ny: cos,
+			v: (1 - t) * quarter / total,
+		})
+	}
+	return revolve(profile, segments)
+}
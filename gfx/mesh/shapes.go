@@ -0,0 +1,96 @@
+package mesh
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//Cube 边长为size的立方体,中心在原点
+//每个面4个独立顶点(共24个),36个索引
+func Cube(size float32) *Geometry {
+	g := &Geometry{}
+	h := size / 2
+	faces := []struct{ normal, u, v mgl32.Vec3 }{
+		{mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, 0, -1}, mgl32.Vec3{0, 1, 0}},
+		{mgl32.Vec3{-1, 0, 0}, mgl32.Vec3{0, 0, 1}, mgl32.Vec3{0, 1, 0}},
+		{mgl32.Vec3{0, 1, 0}, mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, 0, -1}},
+		{mgl32.Vec3{0, -1, 0}, mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, 0, 1}},
+		{mgl32.Vec3{0, 0, 1}, mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, 1, 0}},
+		{mgl32.Vec3{0, 0, -1}, mgl32.Vec3{-1, 0, 0}, mgl32.Vec3{0, 1, 0}},
+	}
+	for _, f := range faces {
+		g.addGrid(f.normal.Mul(h), f.u.Mul(size), f.v.Mul(size), f.normal, 1, 1)
+	}
+	g.ComputeTangents()
+	return g
+}
+
+//Plane XZ平面上宽width深depth的平面,法线朝+Y,中心在原点, 分段数至少为1
+func Plane(width, depth float32, xSegments, zSegments int) *Geometry {
+	xSegments, zSegments = atLeast(xSegments, 1), atLeast(zSegments, 1)
+	g := &Geometry{}
+	g.addGrid(mgl32.Vec3{}, mgl32.Vec3{width, 0, 0}, mgl32.Vec3{0, 0, -depth},
+		mgl32.Vec3{0, 1, 0}, xSegments, zSegments)
+	g.ComputeTangents()
+	return g
+}
+
+//addGrid 添加以center为中心、沿u与v方向展开的矩形网格
+//要求 cross(u, v) 与 normal 同向,这样三角形为逆时针
+func (g *Geometry) addGrid(center, u, v, normal mgl32.Vec3, uSegments, vSegments int) {
+	base := uint32(len(g.Positions))
+	corner := center.Sub(u.Mul(0.5)).Sub(v.Mul(0.5))
+	for j := 0; j <= vSegments; j++ {
+		tv := float32(j) / float32(vSegments)
+		for i := 0; i <= uSegments; i++ {
+			tu := float32(i) / float32(uSegments)
+			pos := corner.Add(u.Mul(tu)).Add(v.Mul(tv))
+			g.addVertex(pos, normal, mgl32.Vec2{tu, tv})
+		}
+	}
+	row := uint32(uSegments + 1)
+	for j := 0; j < vSegments; j++ {
+		for i := 0; i < uSegments; i++ {
+			a := base + uint32(j)*row + uint32(i)
+			b := a + 1
+			c := a + row + 1
+			d := a + row
+			g.addTriangle(a, b, c)
+			g.addTriangle(a, c, d)
+		}
+	}
+}
+
+//Torus 圆环,位于XZ平面,majorRadius为环中心半径,minorRadius为管半径, 分段数至少为3
+func Torus(majorRadius, minorRadius float32, majorSegments, minorSegments int) *Geometry {
+	majorSegments, minorSegments = atLeast(majorSegments, 3), atLeast(minorSegments, 3)
+	g := &Geometry{}
+	for i := 0; i <= majorSegments; i++ {
+		u := float32(i) / float32(majorSegments)
+		phi := float64(u) * 2 * math.Pi
+		cosPhi, sinPhi := float32(math.Cos(phi)), float32(math.Sin(phi))
+		for j := 0; j <= minorSegments; j++ {
+			v := float32(j) / float32(minorSegments)
+			theta := float64(v) * 2 * math.Pi
+			cosTheta, sinTheta := float32(math.Cos(theta)), float32(math.Sin(theta))
+			r := majorRadius + minorRadius*cosTheta
+			pos := mgl32.Vec3{r * cosPhi, minorRadius * sinTheta, -r * sinPhi}
+			normal := mgl32.Vec3{cosTheta * cosPhi, sinTheta, -cosTheta * sinPhi}
+			g.addVertex(pos, normal, mgl32.Vec2{u, v})
+		}
+	}
+	row := uint32(minorSegments + 1)
+	for i := 0; i < majorSegments; i++ {
+		for j := 0; j < minorSegments; j++ {
+			a := uint32(i)*row + uint32(j)
+			b := a + row
+			c := b + 1
+			d := a + 1
+			g.addTriangle(a, b, c)
+			g.addTriangle(a, c, d)
+		}
+	}
+	g.ComputeTangents()
+	return g
+}
@@ -0,0 +1,200 @@
+package obj
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//Material MTL中的一个材质, 贴图为文件名, 未设置时为空字符串
+type Material struct {
+	Name string
+
+	Ambient   mgl32.Vec3 // Ka
+	Diffuse   mgl32.Vec3 // Kd, 默认(1,1,1)
+	Specular  mgl32.Vec3 // Ks
+	Emissive  mgl32.Vec3 // Ke
+	Shininess float32    // Ns
+	IOR       float32    // Ni, 默认1
+	Dissolve  float32    // d, 1为不透明; Tr = 1 - d
+	Illum     int        // illum 光照模型
+
+	AmbientMap      string // map_Ka
+	DiffuseMap      string // map_Kd
+	SpecularMap     string // map_Ks
+	EmissiveMap     string // map_Ke
+	ShininessMap    string // map_Ns
+	DissolveMap     string // map_d
+	BumpMap         string // map_bump 或 bump
+	NormalMap       string // norm
+	DisplacementMap string // disp
+}
+
+//LoadMTL 读取MTL文件, 贴图路径转换为相对于当前目录的路径
+func LoadMTL(file string) (map[string]*Material, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("load mtl file %s: %v", file, err)
+	}
+	defer f.Close()
+
+	materials, err := ParseMTL(f)
+	if err != nil {
+		return nil, fmt.Errorf("load mtl file %s: %v", file, err)
+	}
+	dir := filepath.Dir(file)
+	for _, m := range materials {
+		for _, path := range m.maps() {
+			if *path != "" && !filepath.IsAbs(*path) {
+				*path = filepath.Join(dir, filepath.FromSlash(*path))
+			}
+		}
+	}
+	return materials, nil
+}
+
+//ParseMTL 从r解析材质库, 贴图路径保持文件中的写法
+func ParseMTL(r io.Reader) (map[string]*Material, error) {
+	materials := make(map[string]*Material)
+	var m *Material
+	err := scanLines(r, func(lineNo int, fields []string) error {
+		if fields[0] == "newmtl" {
+			m = &Material{
+				Name:     strings.Join(fields[1:], " "),
+				Diffuse:  mgl32.Vec3{1, 1, 1},
+				IOR:      1,
+				Dissolve: 1,
+			}
+			materials[m.Name] = m
+			return nil
+		}
+		if m == nil {
+			return fmt.Errorf("line %d: %s before newmtl", lineNo, fields[0])
+		}
+		if err := m.parseLine(fields); err != nil {
+			return fmt.Errorf("line %d: %v", lineNo, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return materials, nil
+}
+
+func (m *Material) parseLine(fields []string) error {
+	args := fields[1:]
+	var err error
+	switch fields[0] {
+	case "Ka":
+		m.Ambient, err = parseColor(args)
+	case "Kd":
+		m.Diffuse, err = parseColor(args)
+	case "Ks":
+		m.Specular, err = parseColor(args)
+	case "Ke":
+		m.Emissive, err = parseColor(args)
+	case "Ns":
+		m.Shininess, err = parseFloat(args)
+	case "Ni":
+		m.IOR, err = parseFloat(args)
+	case "d":
+		m.Dissolve, err = parseFloat(args)
+	case "Tr":
+		var tr float32
+		tr, err = parseFloat(args)
+		m.Dissolve = 1 - tr
+	case "illum":
+		var illum float32
+		illum, err = parseFloat(args)
+		m.Illum = int(illum)
+	case "map_Ka":
+		m.AmbientMap, err = parseMap(args)
+	case "map_Kd":
+		m.DiffuseMap, err = parseMap(args)
+	case "map_Ks":
+		m.SpecularMap, err = parseMap(args)
+	case "map_Ke":
+		m.EmissiveMap, err = parseMap(args)
+	case "map_Ns":
+		m.ShininessMap, err = parseMap(args)
+	case "map_d":
+		m.DissolveMap, err = parseMap(args)
+	case "map_bump", "map_Bump", "bump":
+		m.BumpMap, err = parseMap(args)
+	case "norm", "map_Kn":
+		m.NormalMap, err = parseMap(args)
+	case "disp", "map_disp":
+		m.DisplacementMap, err = parseMap(args)
+	}
+	// 其余语句(Tf, sharpness, refl等)忽略
+	return err
+}
+
+//maps 所有贴图字段, 便于统一处理路径
+func (m *Material) maps() []*string {
+	return []*string{
+		&m.AmbientMap, &m.DiffuseMap, &m.SpecularMap, &m.EmissiveMap,
+		&m.ShininessMap, &m.DissolveMap, &m.BumpMap, &m.NormalMap, &m.DisplacementMap,
+	}
+}
+
+//parseColor 解析 r g b, 只给一个值时三个分量相同
+func parseColor(args []string) (mgl32.Vec3, error) {
+	if len(args) > 0 && (args[0] == "spectral" || args[0] == "xyz") {
+		return mgl32.Vec3{}, fmt.Errorf("%s colors are not supported", args[0])
+	}
+	v, err := parseFloats(args, 1)
+	if err != nil {
+		return mgl32.Vec3{}, err
+	}
+	if len(v) < 3 {
+		return mgl32.Vec3{v[0], v[0], v[0]}, nil
+	}
+	return mgl32.Vec3{v[0], v[1], v[2]}, nil
+}
+
+func parseFloat(args []string) (float32, error) {
+	v, err := parseFloats(args, 1)
+	if err != nil {
+		return 0, err
+	}
+	return v[0], nil
+}
+
+//mapOptionArgs 贴图选项及其后最多跟随的参数个数
+var mapOptionArgs = map[string]int{
+	"-blendu": 1, "-blendv": 1, "-boost": 1, "-cc": 1, "-clamp": 1,
+	"-imfchan": 1, "-texres": 1, "-bm": 1, "-type": 1,
+	"-mm": 2, "-o": 3, "-s": 3, "-t": 3,
+}
+
+//parseMap 跳过 -bm 0.5 之类的选项, 返回贴图文件名(可含空格)
+func parseMap(args []string) (string, error) {
+	i := 0
+	for i < len(args) {
+		n, ok := mapOptionArgs[args[i]]
+		if !ok {
+			break
+		}
+		i++
+		// -o/-s/-t 的后两个参数可省略, 只跳过数字
+		for k := 0; k < n && i < len(args); k++ {
+			if k > 0 {
+				if _, err := strconv.ParseFloat(args[i], 32); err != nil {
+					break
+				}
+			}
+			i++
+		}
+	}
+	if i >= len(args) {
+		return "", fmt.Errorf("missing texture file name")
+	}
+	return strings.Join(args[i:], " "), nil
+}
@@ -0,0 +1,397 @@
+/*
+Wavefront OBJ/MTL 模型加载
+支持多边形三角化、负索引、多个对象(o)与组(g)、平滑组(s)以及逐面材质(usemtl),
+输出按 对象/组/材质 划分的网格, 顶点去重后交错输出, 可直接传给 gfx.NewMesh
+*/
+
+package obj
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx/mesh"
+)
+
+//Model 加载得到的模型
+type Model struct {
+	Parts     []*Part              // 按首次出现的顺序排列
+	Materials map[string]*Material // mtllib中定义的全部材质
+}
+
+//Part 同一对象、同一组且使用同一材质的三角形
+type Part struct {
+	Object   string // o 名称
+	Group    string // g 名称, 多个组名以空格连接
+	Material string // usemtl 名称, 可能不在Model.Materials中
+	mesh.Geometry
+}
+
+//MTLLoader 按mtllib中写的文件名读取材质库
+type MTLLoader func(name string) (map[string]*Material, error)
+
+//Load 读取OBJ文件, mtllib 与贴图路径都相对于所在文件的目录解析
+func Load(file string) (*Model, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("load obj file %s: %v", file, err)
+	}
+	defer f.Close()
+
+	dir := filepath.Dir(file)
+	model, err := Parse(f, func(name string) (map[string]*Material, error) {
+		return LoadMTL(filepath.Join(dir, name))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load obj file %s: %v", file, err)
+	}
+	return model, nil
+}
+
+//Parse 从r解析OBJ, mtl为nil时忽略mtllib
+func Parse(r io.Reader, mtl MTLLoader) (*Model, error) {
+	p := &parser{
+		model: &Model{Materials: make(map[string]*Material)},
+		parts: make(map[partKey]*partBuilder),
+	}
+	err := scanLines(r, func(lineNo int, fields []string) error {
+		if err := p.parseLine(fields, mtl); err != nil {
+			return fmt.Errorf("line %d: %v", lineNo, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.build()
+	return p.model, nil
+}
+
+//Interleave 将所有Part合并为一组交错顶点与索引
+func (m *Model) Interleave(attribs mesh.Attrib) ([]float32, []uint32) {
+	var vertices []float32
+	var indices []uint32
+	for _, part := range m.Parts {
+		base := uint32(len(vertices) / attribs.Stride())
+		vertices = append(vertices, part.Interleave(attribs)...)
+		for _, index := range part.Indices {
+			indices = append(indices, base+index)
+		}
+	}
+	return vertices, indices
+}
+
+//corner 面上一个顶点引用的 位置/纹理坐标/法线 下标(从0开始, 缺省为-1)
+type corner struct {
+	v, vt, vn int
+}
+
+//triangle 三角化后的一个三角形
+type triangle struct {
+	corners [3]corner
+	smooth  uint32     // 平滑组, 0为关闭
+	normal  mgl32.Vec3 // 未归一化的三角形法线, 长度与面积成正比
+	face    *face
+}
+
+//face 三角化前的多边形, 平滑关闭时其三角形共享顶点与法线
+type face struct {
+	normal mgl32.Vec3
+}
+
+type partKey struct {
+	object, group, material string
+}
+
+type partBuilder struct {
+	part      *Part
+	triangles []*triangle
+}
+
+type parser struct {
+	model *Model
+
+	positions []mgl32.Vec3
+	texcoords []mgl32.Vec2
+	normals   []mgl32.Vec3
+
+	object, group, material string
+	smooth                  uint32
+
+	parts     map[partKey]*partBuilder
+	order     []*partBuilder
+	triangles []*triangle
+}
+
+func (p *parser) parseLine(fields []string, mtl MTLLoader) error {
+	args := fields[1:]
+	switch fields[0] {
+	case "v":
+		v, err := parseFloats(args, 3)
+		if err != nil {
+			return err
+		}
+		p.positions = append(p.positions, mgl32.Vec3{v[0], v[1], v[2]})
+	case "vt":
+		v, err := parseFloats(args, 1)
+		if err != nil {
+			return err
+		}
+		uv := mgl32.Vec2{v[0]}
+		if len(v) > 1 {
+			uv[1] = v[1]
+		}
+		p.texcoords = append(p.texcoords, uv)
+	case "vn":
+		v, err := parseFloats(args, 3)
+		if err != nil {
+			return err
+		}
+		p.normals = append(p.normals, mgl32.Vec3{v[0], v[1], v[2]})
+	case "f":
+		return p.parseFace(args)
+	case "o":
+		p.object = strings.Join(args, " ")
+	case "g":
+		p.group = strings.Join(args, " ")
+	case "usemtl":
+		p.material = strings.Join(args, " ")
+	case "s":
+		if len(args) == 0 || args[0] == "off" {
+			p.smooth = 0
+			break
+		}
+		s, err := strconv.ParseUint(args[0], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid smoothing group %q", args[0])
+		}
+		p.smooth = uint32(s)
+	case "mtllib":
+		if mtl == nil {
+			break
+		}
+		for _, name := range args {
+			materials, err := mtl(name)
+			if err != nil {
+				return err
+			}
+			for name, m := range materials {
+				p.model.Materials[name] = m
+			}
+		}
+	}
+	// 其余语句(l, p, 曲面等)忽略
+	return nil
+}
+
+func (p *parser) parseFace(args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("face needs at least 3 vertices, got %d", len(args))
+	}
+	corners := make([]corner, len(args))
+	for i, arg := range args {
+		c, err := p.parseCorner(arg)
+		if err != nil {
+			return err
+		}
+		corners[i] = c
+	}
+
+	key := partKey{p.object, p.group, p.material}
+	part, ok := p.parts[key]
+	if !ok {
+		part = &partBuilder{part: &Part{Object: p.object, Group: p.group, Material: p.material}}
+		p.parts[key] = part
+		p.order = append(p.order, part)
+	}
+
+	points := make([]mgl32.Vec3, len(corners))
+	for i, c := range corners {
+		points[i] = p.positions[c.v]
+	}
+	f := &face{}
+	for _, tri := range triangulate(points) {
+		a, b, c := points[tri[0]], points[tri[1]], points[tri[2]]
+		t := &triangle{
+			corners: [3]corner{corners[tri[0]], corners[tri[1]], corners[tri[2]]},
+			smooth:  p.smooth,
+			normal:  b.Sub(a).Cross(c.Sub(a)),
+			face:    f,
+		}
+		f.normal = f.normal.Add(t.normal)
+		part.triangles = append(part.triangles, t)
+		p.triangles = append(p.triangles, t)
+	}
+	return nil
+}
+
+//parseCorner 解析 v、v/vt、v//vn 或 v/vt/vn
+func (p *parser) parseCorner(s string) (corner, error) {
+	c := corner{-1, -1, -1}
+	refs := strings.Split(s, "/")
+	if len(refs) > 3 {
+		return c, fmt.Errorf("invalid face vertex %q", s)
+	}
+	var err error
+	if c.v, err = resolveIndex(refs[0], len(p.positions)); err != nil {
+		return c, err
+	}
+	if len(refs) > 1 && refs[1] != "" {
+		if c.vt, err = resolveIndex(refs[1], len(p.texcoords)); err != nil {
+			return c, err
+		}
+	}
+	if len(refs) > 2 && refs[2] != "" {
+		if c.vn, err = resolveIndex(refs[2], len(p.normals)); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
+
+//resolveIndex 将从1开始的下标或相对末尾的负下标转换为从0开始的下标
+func resolveIndex(s string, count int) (int, error) {
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid index %q", s)
+	}
+	switch {
+	case i > 0 && i <= count:
+		return i - 1, nil
+	case i < 0 && -i <= count:
+		return count + i, nil
+	}
+	return 0, fmt.Errorf("index %d out of range (%d defined)", i, count)
+}
+
+//smoothKey 平滑组内共享法线的顶点
+type smoothKey struct {
+	v      int
+	smooth uint32
+}
+
+//vertexKey 去重时区分顶点的依据
+//文件未给出法线时, 平滑组内按位置共享, 平滑关闭时每个面单独一份
+type vertexKey struct {
+	corner
+	smooth uint32
+	face   *face
+}
+
+func (p *parser) build() {
+	// 平滑组跨对象与组生效, 先在整个文件范围内按面积加权累加面法线
+	smoothNormals := make(map[smoothKey]mgl32.Vec3)
+	for _, t := range p.triangles {
+		if t.smooth == 0 {
+			continue
+		}
+		for _, c := range t.corners {
+			if c.vn < 0 {
+				k := smoothKey{c.v, t.smooth}
+				smoothNormals[k] = smoothNormals[k].Add(t.normal)
+			}
+		}
+	}
+
+	for _, b := range p.order {
+		g := &b.part.Geometry
+		hasUV := false
+		vertices := make(map[vertexKey]uint32)
+		for _, t := range b.triangles {
+			for _, c := range t.corners {
+				key := vertexKey{corner: c}
+				var normal mgl32.Vec3
+				switch {
+				case c.vn >= 0:
+					normal = p.normals[c.vn]
+				case t.smooth != 0:
+					key.smooth = t.smooth
+					normal = smoothNormals[smoothKey{c.v, t.smooth}]
+				default:
+					key.face = t.face
+					normal = t.face.normal
+				}
+				index, ok := vertices[key]
+				if !ok {
+					var uv mgl32.Vec2
+					if c.vt >= 0 {
+						uv = p.texcoords[c.vt]
+						hasUV = true
+					}
+					if normal.Len() > 0 {
+						normal = normal.Normalize()
+					}
+					g.Positions = append(g.Positions, p.positions[c.v])
+					g.Normals = append(g.Normals, normal)
+					g.UVs = append(g.UVs, uv)
+					index = uint32(len(g.Positions) - 1)
+					vertices[key] = index
+				}
+				g.Indices = append(g.Indices, index)
+			}
+		}
+		if hasUV {
+			g.ComputeTangents()
+		} else {
+			g.Tangents = make([]mgl32.Vec4, len(g.Positions))
+		}
+		p.model.Parts = append(p.model.Parts, b.part)
+	}
+}
+
+//scanLines 按行读取, 处理注释与行尾的续行符 '\', 跳过空行
+func scanLines(r io.Reader, fn func(lineNo int, fields []string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNo, start := 0, 0
+	var line strings.Builder
+	for scanner.Scan() {
+		lineNo++
+		text := scanner.Text()
+		if line.Len() == 0 {
+			start = lineNo
+		}
+		if strings.HasSuffix(text, "\\") {
+			line.WriteString(text[:len(text)-1])
+			line.WriteByte(' ')
+			continue
+		}
+		line.WriteString(text)
+		s := line.String()
+		line.Reset()
+		if i := strings.IndexByte(s, '#'); i >= 0 {
+			s = s[:i]
+		}
+		fields := strings.Fields(s)
+		if len(fields) == 0 {
+			continue
+		}
+		if err := fn(start, fields); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+//parseFloats 解析至少min个浮点数
+func parseFloats(args []string, min int) ([]float32, error) {
+	if len(args) < min {
+		return nil, fmt.Errorf("expected %d numbers, got %d", min, len(args))
+	}
+	v := make([]float32, len(args))
+	for i, arg := range args {
+		f, err := strconv.ParseFloat(arg, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", arg)
+		}
+		v[i] = float32(f)
+	}
+	return v, nil
+}
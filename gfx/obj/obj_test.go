@@ -0,0 +1,257 @@
+package obj
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx/mesh"
+)
+
+func load(t *testing.T, name string) *Model {
+	t.Helper()
+	model, err := Load(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return model
+}
+
+func near(a, b mgl32.Vec3) bool {
+	return a.ApproxEqualThreshold(b, 1e-5)
+}
+
+//TestNegativeIndices 负索引相对当前已定义的顶点, 与对应的正索引得到相同的几何
+func TestNegativeIndices(t *testing.T) {
+	model := load(t, "negative.obj")
+	if len(model.Parts) != 2 {
+		t.Fatalf("%d parts, want 2", len(model.Parts))
+	}
+	positive, negative := model.Parts[0], model.Parts[1]
+	if positive.Group != "positive" || negative.Group != "negative" {
+		t.Fatalf("groups %q, %q", positive.Group, negative.Group)
+	}
+	if len(negative.Indices) != 6 || negative.VertexCount() != 4 {
+		t.Fatalf("%d indices %d vertices, want 6 and 4", len(negative.Indices), negative.VertexCount())
+	}
+	for i, index := range negative.Indices {
+		if index != positive.Indices[i] {
+			t.Fatalf("indices %v, want %v", negative.Indices, positive.Indices)
+		}
+	}
+	for i := range negative.Positions {
+		if negative.Positions[i] != positive.Positions[i] || negative.UVs[i] != positive.UVs[i] ||
+			negative.Normals[i] != positive.Normals[i] {
+			t.Fatalf("vertex %d differs: %v %v", i, negative.Positions[i], positive.Positions[i])
+		}
+	}
+}
+
+//TestConcavePolygon 耳切法三角化凹多边形: 三角形都保持原绕序且面积之和等于多边形面积
+func TestConcavePolygon(t *testing.T) {
+	model := load(t, "concave.obj")
+	g := &model.Parts[0].Geometry
+	if len(g.Indices) != 3*4 {
+		t.Fatalf("%d triangles, want 4", len(g.Indices)/3)
+	}
+	area := float32(0)
+	for i := 0; i < len(g.Indices); i += 3 {
+		a, b, c := g.Positions[g.Indices[i]], g.Positions[g.Indices[i+1]], g.Positions[g.Indices[i+2]]
+		n := b.Sub(a).Cross(c.Sub(a))
+		if n.Z() <= 0 {
+			t.Errorf("triangle %v %v %v is clockwise or degenerate", a, b, c)
+		}
+		area += n.Len() / 2
+	}
+	if math.Abs(float64(area)-3) > 1e-5 {
+		t.Errorf("triangles cover area %v, want 3", area)
+	}
+	for i, n := range g.Normals {
+		if !near(n, mgl32.Vec3{0, 0, 1}) {
+			t.Errorf("normal %d = %v, want +Z", i, n)
+		}
+	}
+}
+
+func TestTriangulate(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []mgl32.Vec3
+		want   int
+	}{
+		{"triangle", []mgl32.Vec3{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}, 1},
+		{"convex quad", []mgl32.Vec3{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}}, 2},
+		// 投影到YZ平面, 法线朝-X
+		{"concave yz", []mgl32.Vec3{{0, 1, 2}, {0, 1, 1}, {0, 2, 1}, {0, 2, 0}, {0, 0, 0}, {0, 0, 2}}, 4},
+	}
+	for _, tt := range tests {
+		tris := triangulate(tt.points)
+		if len(tris) != tt.want {
+			t.Errorf("%s: %d triangles, want %d", tt.name, len(tris), tt.want)
+			continue
+		}
+		var polygon mgl32.Vec3
+		for i := 1; i+1 < len(tt.points); i++ {
+			polygon = polygon.Add(tt.points[i].Sub(tt.points[0]).Cross(tt.points[i+1].Sub(tt.points[0])))
+		}
+		var sum mgl32.Vec3
+		for _, tri := range tris {
+			a, b, c := tt.points[tri[0]], tt.points[tri[1]], tt.points[tri[2]]
+			n := b.Sub(a).Cross(c.Sub(a))
+			if n.Dot(polygon) <= 0 {
+				t.Errorf("%s: triangle %v winds against the polygon", tt.name, tri)
+			}
+			sum = sum.Add(n)
+		}
+		if !near(sum, polygon) {
+			t.Errorf("%s: triangles sum to %v, polygon %v", tt.name, sum, polygon)
+		}
+	}
+}
+
+//TestSmoothingGroups 平滑组内共享顶点并按面积加权平均法线, 平滑关闭时每个面使用自己的法线
+func TestSmoothingGroups(t *testing.T) {
+	model := load(t, "smooth.obj")
+	if len(model.Parts) != 2 {
+		t.Fatalf("%d parts, want 2", len(model.Parts))
+	}
+	smooth, flat := model.Parts[0], model.Parts[1]
+
+	if smooth.VertexCount() != 4 {
+		t.Errorf("smooth: %d vertices, want 4", smooth.VertexCount())
+	}
+	// 折边上的顶点: 两个面的未归一化法线(0,0,1)与(1,0,1)之和
+	shared := mgl32.Vec3{1, 0, 2}.Normalize()
+	for i, p := range smooth.Positions {
+		want := mgl32.Vec3{0, 0, 1}
+		switch p {
+		case mgl32.Vec3{0, 0, 0}, mgl32.Vec3{0, 1, 0}:
+			want = shared
+		case mgl32.Vec3{-1, 0, 1}:
+			want = mgl32.Vec3{1, 0, 1}.Normalize()
+		}
+		if !near(smooth.Normals[i], want) {
+			t.Errorf("smooth: vertex %v normal %v, want %v", p, smooth.Normals[i], want)
+		}
+	}
+
+	if flat.VertexCount() != 6 {
+		t.Errorf("flat: %d vertices, want 6", flat.VertexCount())
+	}
+	for i := 0; i < len(flat.Indices); i += 3 {
+		want := mgl32.Vec3{0, 0, 1}
+		if i > 0 {
+			want = mgl32.Vec3{1, 0, 1}.Normalize()
+		}
+		for _, index := range flat.Indices[i : i+3] {
+			if !near(flat.Normals[index], want) {
+				t.Errorf("flat: triangle %d normal %v, want %v", i/3, flat.Normals[index], want)
+			}
+		}
+	}
+}
+
+//TestDeduplication 位置/纹理坐标/法线完全相同的面顶点只输出一次
+func TestDeduplication(t *testing.T) {
+	model := load(t, "cube.obj")
+	if len(model.Parts) != 1 {
+		t.Fatalf("%d parts, want 1", len(model.Parts))
+	}
+	part := model.Parts[0]
+	if part.Material != "crate" {
+		t.Errorf("material %q, want crate", part.Material)
+	}
+	if part.VertexCount() != 24 || len(part.Indices) != 36 {
+		t.Errorf("%d vertices %d indices, want 24 and 36", part.VertexCount(), len(part.Indices))
+	}
+	// 每个三角形的面法线与文件给出的法线同向
+	for i := 0; i < len(part.Indices); i += 3 {
+		a, b, c := part.Indices[i], part.Indices[i+1], part.Indices[i+2]
+		face := part.Positions[b].Sub(part.Positions[a]).Cross(part.Positions[c].Sub(part.Positions[a]))
+		if face.Dot(part.Normals[a]) <= 0 {
+			t.Errorf("triangle %d winds against its normal %v", i/3, part.Normals[a])
+		}
+	}
+
+	vertices, indices := model.Interleave(mesh.Position | mesh.UV)
+	if len(vertices) != 24*5 || len(indices) != 36 {
+		t.Errorf("interleaved %d floats %d indices, want %d and 36", len(vertices), len(indices), 24*5)
+	}
+}
+
+//TestMaterials 材质属性、贴图选项与相对于MTL文件目录的贴图路径
+func TestMaterials(t *testing.T) {
+	model := load(t, "cube.obj")
+	m, ok := model.Materials["crate"]
+	if !ok {
+		t.Fatalf("materials %v, want crate", model.Materials)
+	}
+	if m.Ambient != (mgl32.Vec3{0.1, 0.1, 0.1}) || m.Diffuse != (mgl32.Vec3{0.8, 0.6, 0.4}) {
+		t.Errorf("Ka %v Kd %v", m.Ambient, m.Diffuse)
+	}
+	if m.Shininess != 64 || m.Dissolve != 0.75 || m.Illum != 2 || m.IOR != 1 {
+		t.Errorf("Ns %v d %v illum %v Ni %v", m.Shininess, m.Dissolve, m.Illum, m.IOR)
+	}
+	if want := filepath.Join("testdata", "textures", "crate.png"); m.DiffuseMap != want {
+		t.Errorf("map_Kd %q, want %q", m.DiffuseMap, want)
+	}
+	if want := filepath.Join("testdata", "textures", "crate normal.png"); m.NormalMap != want {
+		t.Errorf("norm %q, want %q", m.NormalMap, want)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		file, want string
+	}{
+		{"badindex.obj", "line 4: index 4 out of range (3 defined)"},
+		{"missingmtl.obj", "load mtl file " + filepath.Join("testdata", "missing.mtl")},
+		{"nonexistent.obj", "load obj file"},
+	}
+	for _, tt := range tests {
+		_, err := Load(filepath.Join("testdata", tt.file))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.file, err, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"zero index", "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 0 1 2", "index 0 out of range"},
+		{"negative beyond start", "v 0 0 0\nv 1 0 0\nf -1 -2 -3", "index -3 out of range"},
+		{"texcoord out of range", "v 0 0 0\nv 1 0 0\nv 0 1 0\nvt 0 0\nf 1/2 2/1 3/1", "index 2 out of range (1 defined)"},
+		{"two vertices", "v 0 0 0\nv 1 0 0\nf 1 2", "face needs at least 3 vertices"},
+		{"bad number", "v 0 x 0", "invalid number"},
+		{"short vertex", "v 0 0", "expected 3 numbers"},
+		{"bad smoothing group", "s on", "invalid smoothing group"},
+		{"too many slashes", "v 0 0 0\nf 1/1/1/1 1 1", "invalid face vertex"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(strings.NewReader(tt.src), nil)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestParseMTLErrors(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"before newmtl", "Kd 1 1 1", "line 1: Kd before newmtl"},
+		{"spectral", "newmtl a\nKd spectral file.rfl", "spectral colors are not supported"},
+		{"missing map", "newmtl a\nmap_Kd -bm 0.5", "missing texture file name"},
+	}
+	for _, tt := range tests {
+		_, err := ParseMTL(strings.NewReader(tt.src))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
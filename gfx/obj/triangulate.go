@@ -0,0 +1,108 @@
+package obj
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//triangulate 用耳切法将多边形三角化, 返回保持原绕序的顶点下标
+//多边形投影到法线最大分量所对应的坐标平面上处理, 可处理凹多边形
+func triangulate(points []mgl32.Vec3) [][3]int {
+	n := len(points)
+	if n == 3 {
+		return [][3]int{{0, 1, 2}}
+	}
+
+	// Newell法求多边形法线, 对非平面多边形也稳定
+	var normal mgl32.Vec3
+	for i := range points {
+		a, b := points[i], points[(i+1)%n]
+		normal = normal.Add(mgl32.Vec3{
+			(a.Y() - b.Y()) * (a.Z() + b.Z()),
+			(a.Z() - b.Z()) * (a.X() + b.X()),
+			(a.X() - b.X()) * (a.Y() + b.Y()),
+		})
+	}
+	// 丢弃法线最大分量的坐标, 并保证投影后多边形为逆时针
+	u, v := 0, 1
+	ax, ay, az := abs(normal.X()), abs(normal.Y()), abs(normal.Z())
+	switch {
+	case ax >= ay && ax >= az:
+		u, v = 1, 2
+		if normal.X() < 0 {
+			u, v = v, u
+		}
+	case ay >= az:
+		u, v = 2, 0
+		if normal.Y() < 0 {
+			u, v = v, u
+		}
+	default:
+		if normal.Z() < 0 {
+			u, v = v, u
+		}
+	}
+	flat := make([]mgl32.Vec2, n)
+	for i, p := range points {
+		flat[i] = mgl32.Vec2{p[u], p[v]}
+	}
+
+	remaining := make([]int, n)
+	for i := range remaining {
+		remaining[i] = i
+	}
+	triangles := make([][3]int, 0, n-2)
+	for len(remaining) > 3 {
+		ear := -1
+		for i := range remaining {
+			if isEar(flat, remaining, i) {
+				ear = i
+				break
+			}
+		}
+		if ear < 0 {
+			break // 自相交或退化, 剩余部分按扇形处理
+		}
+		m := len(remaining)
+		triangles = append(triangles, [3]int{
+			remaining[(ear+m-1)%m], remaining[ear], remaining[(ear+1)%m],
+		})
+		remaining = append(remaining[:ear], remaining[ear+1:]...)
+	}
+	for i := 1; i+1 < len(remaining); i++ {
+		triangles = append(triangles, [3]int{remaining[0], remaining[i], remaining[i+1]})
+	}
+	return triangles
+}
+
+//isEar remaining[i]是否为凸顶点且与相邻两点构成的三角形内不含其它顶点
+func isEar(flat []mgl32.Vec2, remaining []int, i int) bool {
+	m := len(remaining)
+	a := flat[remaining[(i+m-1)%m]]
+	b := flat[remaining[i]]
+	c := flat[remaining[(i+1)%m]]
+	if cross2(a, b, c) <= 0 {
+		return false
+	}
+	for k, idx := range remaining {
+		if k == i || k == (i+m-1)%m || k == (i+1)%m {
+			continue
+		}
+		p := flat[idx]
+		if cross2(a, b, p) >= 0 && cross2(b, c, p) >= 0 && cross2(c, a, p) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+//cross2 向量ab与ac的叉积, 大于0时a,b,c为逆时针
+func cross2(a, b, c mgl32.Vec2) float32 {
+	return (b.X()-a.X())*(c.Y()-a.Y()) - (b.Y()-a.Y())*(c.X()-a.X())
+}
+
+func abs(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
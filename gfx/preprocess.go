@@ -0,0 +1,194 @@
+/*
+着色器源码预处理
+在gl.ShaderSource之前展开 #include "file" (路径相对于所在文件), 检测循环包含,
+在 #version 行之后插入调用方提供的 #define,
+并记录输出的每一行对应的原文件和行号, 用于把驱动报错的行号映射回源文件
+块注释中的 #include 不展开; 预处理器不计算 #if/#ifdef,
+条件编译块中的 #include 同样会被展开, 被包含的文件必须存在
+*/
+
+package gfx
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+//Define 预处理时插入的宏定义, Value为空时只定义名字
+type Define struct {
+	Name, Value string
+}
+
+//Source 预处理后的着色器源码
+type Source struct {
+	Code  string
+	Files []string // 参与预处理的所有文件, 第一个为入口文件
+	lines []srcLine
+	text  []string // 输出的各行
+}
+
+//srcLine 输出的一行在原文件中的位置, line为0表示由预处理器生成
+type srcLine struct {
+	file string
+	line int
+}
+
+//Preprocess 读取着色器文件, 展开#include, 并在#version行之后插入defines
+func Preprocess(file string, defines ...Define) (*Source, error) {
+	return PreprocessFS(nil, file, defines...)
+}
+
+//PreprocessFS 与Preprocess相同, 但从文件系统fsys中读取, 路径以"/"分隔
+//fsys为nil时从操作系统的文件中读取
+func PreprocessFS(fsys fs.FS, file string, defines ...Define) (*Source, error) {
+	p := &preprocessor{fsys: fsys, seen: make(map[string]bool)}
+	if err := p.include(file, nil); err != nil {
+		return nil, err
+	}
+	p.insertDefines(defines)
+
+	src := &Source{Code: strings.Join(p.out, "\n") + "\n", Files: p.files, lines: p.lines, text: p.out}
+	return src, nil
+}
+
+//Origin 返回输出源码第line行(从1开始)对应的原文件和行号
+//预处理器生成的行或超出范围时ok为false
+func (src *Source) Origin(line int) (file string, origLine int, ok bool) {
+	if line < 1 || line > len(src.lines) || src.lines[line-1].line == 0 {
+		return "", 0, false
+	}
+	l := src.lines[line-1]
+	return l.file, l.line, true
+}
+
+//stringSource 从字符串创建的源码, 行号与输出一致
+func stringSource(code string) *Source {
+	text := strings.Split(strings.TrimSuffix(code, "\n"), "\n")
+	lines := make([]srcLine, len(text))
+	for i := range lines {
+		lines[i] = srcLine{line: i + 1}
+	}
+	return &Source{Code: code, lines: lines, text: text}
+}
+
+type preprocessor struct {
+	fsys  fs.FS
+	out   []string
+	lines []srcLine
+	files []string
+	seen  map[string]bool
+}
+
+//include 将文件逐行追加到输出, stack为当前的包含链, 用于检测循环
+func (p *preprocessor) include(file string, stack []string) error {
+	for _, f := range stack {
+		if f == file {
+			return fmt.Errorf("shader include cycle: %s", strings.Join(append(stack, file), " -> "))
+		}
+	}
+	text, err := getShaderFromFile(p.fsys, file)
+	if err != nil {
+		return err
+	}
+	if !p.seen[file] {
+		p.seen[file] = true
+		p.files = append(p.files, file)
+	}
+	stack = append(stack, file)
+
+	text = strings.TrimSuffix(strings.Replace(text, "\r\n", "\n", -1), "\n")
+	comment := false // 当前行是否从 /* */ 注释中开始
+	for i, line := range strings.Split(text, "\n") {
+		var name string
+		var ok bool
+		if !comment {
+			name, ok, err = parseInclude(line)
+			if err != nil {
+				return fmt.Errorf("%s:%d: %v", file, i+1, err)
+			}
+		}
+		comment = inBlockComment(line, comment)
+		if !ok {
+			p.out = append(p.out, line)
+			p.lines = append(p.lines, srcLine{file, i + 1})
+			continue
+		}
+		if err := p.include(p.join(file, name), stack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//join 解析file中#include的name
+func (p *preprocessor) join(file, name string) string {
+	if p.fsys == nil {
+		return filepath.Join(filepath.Dir(file), name)
+	}
+	return path.Join(path.Dir(file), name)
+}
+
+//insertDefines 在第一个#version行之后(没有时在开头)插入宏定义
+func (p *preprocessor) insertDefines(defines []Define) {
+	if len(defines) == 0 {
+		return
+	}
+	at := 0
+	for i, line := range p.out {
+		if strings.HasPrefix(strings.TrimSpace(line), "#version") {
+			at = i + 1
+			break
+		}
+	}
+	out := make([]string, 0, len(p.out)+len(defines))
+	lines := make([]srcLine, 0, len(p.lines)+len(defines))
+	out = append(out, p.out[:at]...)
+	lines = append(lines, p.lines[:at]...)
+	for _, d := range defines {
+		out = append(out, strings.TrimSpace("#define "+d.Name+" "+d.Value))
+		lines = append(lines, srcLine{})
+	}
+	p.out = append(out, p.out[at:]...)
+	p.lines = append(lines, p.lines[at:]...)
+}
+
+//inBlockComment 扫描一行后是否位于 /* */ 注释中, comment为这一行开始时的状态
+func inBlockComment(line string, comment bool) bool {
+	for i := 0; i+1 < len(line); i++ {
+		switch {
+		case comment && line[i] == '*' && line[i+1] == '/':
+			comment = false
+			i++
+		case !comment && line[i] == '/' && line[i+1] == '/':
+			return false
+		case !comment && line[i] == '/' && line[i+1] == '*':
+			comment = true
+			i++
+		}
+	}
+	return comment
+}
+
+//parseInclude 解析 #include "file" 行, 不是#include时ok为false
+func parseInclude(line string) (name string, ok bool, err error) {
+	s := strings.TrimSpace(line)
+	if !strings.HasPrefix(s, "#") {
+		return "", false, nil
+	}
+	s = strings.TrimSpace(s[1:])
+	if !strings.HasPrefix(s, "include") {
+		return "", false, nil
+	}
+	s = strings.TrimSpace(s[len("include"):])
+	if len(s) < 2 || s[0] != '"' || strings.IndexByte(s[1:], '"') < 0 {
+		return "", false, fmt.Errorf("malformed #include, want #include \"file\"")
+	}
+	end := strings.IndexByte(s[1:], '"') + 1
+	if rest := strings.TrimSpace(s[end+1:]); rest != "" && !strings.HasPrefix(rest, "//") {
+		return "", false, fmt.Errorf("unexpected %q after #include", rest)
+	}
+	return s[1:end], true, nil
+}
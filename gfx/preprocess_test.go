@@ -0,0 +1,216 @@
+package gfx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+//testShaders main.frag包含lib/light.glsl, lib/light.glsl又包含../common.glsl
+func testShaders() fstest.MapFS {
+	return fstest.MapFS{
+		"shaders/main.frag": {Data: []byte("#version 330 core\n" +
+			"#include \"lib/light.glsl\"\n" +
+			"out vec4 FragColor;\n" +
+			"void main() { FragColor = light(); }\n")},
+		"shaders/lib/light.glsl": {Data: []byte("#include \"../common.glsl\" // 公共定义\n" +
+			"vec4 light() { return vec4(AMBIENT); }\n")},
+		"shaders/common.glsl": {Data: []byte("const float AMBIENT = 0.1;\r\n")},
+	}
+}
+
+//origin 输出源码中一行的来源, line为0表示由预处理器生成
+type origin struct {
+	file string
+	line int
+}
+
+//origins 输出源码每一行的来源
+func origins(src *Source) []origin {
+	var got []origin
+	for i := range src.text {
+		file, line, _ := src.Origin(i + 1)
+		got = append(got, origin{file, line})
+	}
+	return got
+}
+
+func TestPreprocessInclude(t *testing.T) {
+	src, err := PreprocessFS(testShaders(), "shaders/main.frag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCode := "#version 330 core\n" +
+		"const float AMBIENT = 0.1;\n" +
+		"vec4 light() { return vec4(AMBIENT); }\n" +
+		"out vec4 FragColor;\n" +
+		"void main() { FragColor = light(); }\n"
+	if src.Code != wantCode {
+		t.Errorf("code:\n%s\nwant:\n%s", src.Code, wantCode)
+	}
+	wantFiles := []string{"shaders/main.frag", "shaders/lib/light.glsl", "shaders/common.glsl"}
+	if !reflect.DeepEqual(src.Files, wantFiles) {
+		t.Errorf("files %v, want %v", src.Files, wantFiles)
+	}
+	wantOrigins := []origin{
+		{"shaders/main.frag", 1},
+		{"shaders/common.glsl", 1},
+		{"shaders/lib/light.glsl", 2},
+		{"shaders/main.frag", 3},
+		{"shaders/main.frag", 4},
+	}
+	if got := origins(src); !reflect.DeepEqual(got, wantOrigins) {
+		t.Errorf("origins %v, want %v", got, wantOrigins)
+	}
+	for _, line := range []int{0, 6} {
+		if _, _, ok := src.Origin(line); ok {
+			t.Errorf("Origin(%d) ok for a line out of range", line)
+		}
+	}
+}
+
+//TestPreprocessDefines 宏定义插入在#version之后, 不影响其余行映射回原文件的行号
+func TestPreprocessDefines(t *testing.T) {
+	defines := []Define{{"SHADOWS", ""}, {"LIGHTS", "4"}}
+	tests := []struct {
+		name, code string
+		want       []string
+		origins    []origin
+	}{
+		{"after version", "// 注释\n#version 330 core\nvoid main() {}\n",
+			[]string{"// 注释", "#version 330 core", "#define SHADOWS", "#define LIGHTS 4", "void main() {}"},
+			[]origin{{"a.vert", 1}, {"a.vert", 2}, {}, {}, {"a.vert", 3}}},
+		{"indented version", "  #version 410\nvoid main() {}\n",
+			[]string{"  #version 410", "#define SHADOWS", "#define LIGHTS 4", "void main() {}"},
+			[]origin{{"a.vert", 1}, {}, {}, {"a.vert", 2}}},
+		{"no version", "void main() {}\n",
+			[]string{"#define SHADOWS", "#define LIGHTS 4", "void main() {}"},
+			[]origin{{}, {}, {"a.vert", 1}}},
+	}
+	for _, tt := range tests {
+		fsys := fstest.MapFS{"a.vert": {Data: []byte(tt.code)}}
+		src, err := PreprocessFS(fsys, "a.vert", defines...)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if want := strings.Join(tt.want, "\n") + "\n"; src.Code != want {
+			t.Errorf("%s: code %q, want %q", tt.name, src.Code, want)
+		}
+		if got := origins(src); !reflect.DeepEqual(got, tt.origins) {
+			t.Errorf("%s: origins %v, want %v", tt.name, got, tt.origins)
+		}
+	}
+}
+
+func TestPreprocessErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"cycle", map[string]string{
+			"a.glsl":     "#include \"b.glsl\"\n",
+			"b.glsl":     "#include \"sub/c.glsl\"\n",
+			"sub/c.glsl": "#include \"../a.glsl\"\n",
+		}, "shader include cycle: a.glsl -> b.glsl -> sub/c.glsl -> a.glsl"},
+		{"self", map[string]string{
+			"a.glsl": "void f();\n#include \"a.glsl\"\n",
+		}, "shader include cycle: a.glsl -> a.glsl"},
+		{"missing file", map[string]string{
+			"a.glsl": "#include \"missing.glsl\"\n",
+		}, "load shader file missing.glsl"},
+		{"malformed", map[string]string{
+			"a.glsl": "void f();\n#include <b.glsl>\n",
+		}, "a.glsl:2: malformed #include"},
+		{"trailing text", map[string]string{
+			"a.glsl": "#include \"b.glsl\" x\n",
+			"b.glsl": "",
+		}, "a.glsl:1: unexpected \"x\" after #include"},
+	}
+	for _, tt := range tests {
+		fsys := fstest.MapFS{}
+		for name, code := range tt.files {
+			fsys[name] = &fstest.MapFile{Data: []byte(code)}
+		}
+		_, err := PreprocessFS(fsys, "a.glsl")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+//TestPreprocessRepeated 同一文件被包含多次不是循环, 在Files中只出现一次
+func TestPreprocessRepeated(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.glsl":      {Data: []byte("#include \"b.glsl\"\n#include \"c.glsl\"\n")},
+		"b.glsl":      {Data: []byte("#include \"common.glsl\"\n")},
+		"c.glsl":      {Data: []byte("#include \"common.glsl\"\n")},
+		"common.glsl": {Data: []byte("float x;\n")},
+	}
+	src, err := PreprocessFS(fsys, "a.glsl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src.Code != "float x;\nfloat x;\n" {
+		t.Errorf("code %q", src.Code)
+	}
+	if want := []string{"a.glsl", "b.glsl", "common.glsl", "c.glsl"}; !reflect.DeepEqual(src.Files, want) {
+		t.Errorf("files %v, want %v", src.Files, want)
+	}
+}
+
+//TestPreprocessComments 注释中的#include保持原样, 注释结束后的#include照常展开
+func TestPreprocessComments(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.glsl": {Data: []byte("/* 旧的写法:\n" +
+			"#include \"missing.glsl\"\n" +
+			"*/\n" +
+			"// #include \"missing.glsl\"\n" +
+			"float y; /* 行尾的注释 */\n" +
+			"#include \"b.glsl\"\n" +
+			"float z; // /* 行注释中的注释开头\n" +
+			"#include \"b.glsl\"\n")},
+		"b.glsl": {Data: []byte("float x;\n")},
+	}
+	src, err := PreprocessFS(fsys, "a.glsl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "/* 旧的写法:\n" +
+		"#include \"missing.glsl\"\n" +
+		"*/\n" +
+		"// #include \"missing.glsl\"\n" +
+		"float y; /* 行尾的注释 */\n" +
+		"float x;\n" +
+		"float z; // /* 行注释中的注释开头\n" +
+		"float x;\n"
+	if src.Code != want {
+		t.Errorf("code:\n%s\nwant:\n%s", src.Code, want)
+	}
+}
+
+func TestParseInclude(t *testing.T) {
+	tests := []struct {
+		line, name string
+		ok         bool
+		err        bool
+	}{
+		{`#include "a.glsl"`, "a.glsl", true, false},
+		{`  #  include   "dir/a b.glsl"  `, "dir/a b.glsl", true, false},
+		{`#include "a.glsl" // 注释`, "a.glsl", true, false},
+		{`#version 330 core`, "", false, false},
+		{`#define INCLUDE 1`, "", false, false},
+		{`float x; // #include "a.glsl"`, "", false, false},
+		{`#include`, "", false, true},
+		{`#include "a.glsl`, "", false, true},
+		{`#include a.glsl`, "", false, true},
+		{`#include "a.glsl" ;`, "", false, true},
+	}
+	for _, tt := range tests {
+		name, ok, err := parseInclude(tt.line)
+		if name != tt.name || ok != tt.ok || (err != nil) != tt.err {
+			t.Errorf("parseInclude(%q) = %q, %v, %v, want %q, %v, error %v", tt.line, name, ok, err, tt.name, tt.ok, tt.err)
+		}
+	}
+}
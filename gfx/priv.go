@@ -0,0 +1,50 @@
+//私有的对外包不可见的
+
+package gfx
+
+import (
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+//getShaderFromFile 从文件中获取shader源码, fsys为nil时从操作系统的文件中读取
+func getShaderFromFile(fsys fs.FS, file string) (string, error) {
+	var src []byte
+	var err error
+	if fsys == nil {
+		src, err = ioutil.ReadFile(file)
+	} else {
+		src, err = fs.ReadFile(fsys, file)
+	}
+	if err != nil {
+		return "", fmt.Errorf("load shader file %s: %v", file, err)
+	}
+	return string(src), nil
+}
+
+type getObjIv func(uint32, uint32, *int32)
+type getObjInfoLog func(uint32, int32, *int32, *uint8)
+
+//getInfoLog 检查checkTrueParam是否成功, 失败时返回日志
+func getInfoLog(glHandle uint32, checkTrueParam uint32, getObjIvFn getObjIv,
+	getObjInfoLogFn getObjInfoLog) (string, bool) {
+
+	var success int32
+	getObjIvFn(glHandle, checkTrueParam, &success)
+
+	if success == gl.FALSE {
+		var logLength int32
+		getObjIvFn(glHandle, gl.INFO_LOG_LENGTH, &logLength)
+
+		log := gl.Str(strings.Repeat("\x00", int(logLength+1)))
+		getObjInfoLogFn(glHandle, logLength, nil, log)
+
+		return gl.GoStr(log), false
+	}
+
+	return "", true
+}
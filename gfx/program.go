@@ -0,0 +1,215 @@
+/*
+创建着色器程序对象
+将编译好的着色器附加到程序对象上
+链接生成程序
+*/
+
+package gfx
+
+import (
+	"io/fs"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//Program 着色器程序对象
+type Program struct {
+	handle  uint32
+	shaders []*Shader
+
+	uniforms  []ActiveUniform
+	attribs   []ActiveAttrib
+	locations map[string]int32 // uniform名字到位置的缓存
+	warned    map[string]bool  // 已经警告过的不存在的uniform
+	strict    bool
+	blocks    map[string]*UniformBuffer // 已连接的uniform块, 重新加载后恢复
+}
+
+//NewProgram 附加着色器并链接生成着色器程序
+func NewProgram(shaders ...*Shader) (*Program, error) {
+	prog := &Program{handle: gl.CreateProgram()}
+	prog.Attach(shaders...)
+
+	if err := prog.Link(); err != nil {
+		prog.Delete()
+		return nil, err
+	}
+
+	return prog, nil
+}
+
+//NewProgramFromFiles 从顶点着色器和片段着色器文件生成着色器程序
+func NewProgramFromFiles(vertShaderPath, fragShaderPath string) (*Program, error) {
+	return NewProgramFromFS(nil, vertShaderPath, fragShaderPath)
+}
+
+//NewProgramFromFS 从文件系统fsys(如 embed.FS)中的顶点着色器和片段着色器文件生成着色器程序
+//fsys为nil时等同于NewProgramFromFiles
+func NewProgramFromFS(fsys fs.FS, vertShaderPath, fragShaderPath string) (*Program, error) {
+	vertShader, err := NewShaderFromFS(fsys, vertShaderPath, gl.VERTEX_SHADER)
+	if err != nil {
+		return nil, err
+	}
+	fragShader, err := NewShaderFromFS(fsys, fragShaderPath, gl.FRAGMENT_SHADER)
+	if err != nil {
+		vertShader.Delete()
+		return nil, err
+	}
+	return NewProgram(vertShader, fragShader)
+}
+
+//Attach 将着色器附加到程序上
+func (prog *Program) Attach(shaders ...*Shader) {
+	for _, shader := range shaders {
+		gl.AttachShader(prog.handle, shader.handle)
+		prog.shaders = append(prog.shaders, shader)
+	}
+}
+
+//Link 链接着色器程序, 链接失败时返回*ShaderError
+//链接成功后查询活动的uniform与顶点属性
+func (prog *Program) Link() error {
+	gl.LinkProgram(prog.handle)
+	if log, ok := getInfoLog(prog.handle, gl.LINK_STATUS, gl.GetProgramiv, gl.GetProgramInfoLog); !ok {
+		return &ShaderError{Log: log, Messages: parseShaderLog(log, nil)}
+	}
+	prog.reflect()
+	return nil
+}
+
+//Use 激活着色器程序
+func (prog *Program) Use() {
+	gl.UseProgram(prog.handle)
+}
+
+//Delete 删除着色器程序及其附加的着色器
+func (prog *Program) Delete() {
+	for _, shader := range prog.shaders {
+		shader.Delete()
+	}
+	gl.DeleteProgram(prog.handle)
+}
+
+//sourceFiles 程序中各着色器的源码文件(含#include的文件), 有着色器不是从文件创建时返回nil
+//操作系统的文件按路径去重, fs.FS中的文件按着色器分别列出(fs.FS不一定可比较)
+func (prog *Program) sourceFiles() []sourceFile {
+	var files []sourceFile
+	seen := make(map[string]bool)
+	for _, shader := range prog.shaders {
+		if shader.file == "" {
+			return nil
+		}
+		for _, file := range shader.files {
+			if shader.fsys == nil {
+				if seen[file] {
+					continue
+				}
+				seen[file] = true
+			}
+			files = append(files, sourceFile{shader.fsys, file})
+		}
+	}
+	return files
+}
+
+//reload 从源码文件重新编译并链接, 成功后替换句柄并删除旧程序, 失败时保持原样
+func (prog *Program) reload() error {
+	shaders := make([]*Shader, 0, len(prog.shaders))
+	for _, old := range prog.shaders {
+		shader, err := NewShaderFromFS(old.fsys, old.file, old.sType, old.defines...)
+		if err != nil {
+			for _, s := range shaders {
+				s.Delete()
+			}
+			return err
+		}
+		shaders = append(shaders, shader)
+	}
+	next, err := NewProgram(shaders...)
+	if err != nil {
+		return err
+	}
+	for name, ub := range prog.blocks {
+		if err := next.bindUniformBlock(name, ub.binding, ub.Size()); err != nil {
+			next.Delete()
+			return err
+		}
+	}
+
+	old := &Program{handle: prog.handle, shaders: prog.shaders}
+	prog.handle, prog.shaders = next.handle, next.shaders
+	prog.uniforms, prog.attribs, prog.locations = next.uniforms, next.attribs, next.locations
+	old.Delete()
+	return nil
+}
+
+//GetUniformLocation 返回着色器程序中uniform的位置, 不存在时返回-1
+func (prog *Program) GetUniformLocation(name string) int32 {
+	location, _ := prog.uniformLocation(name)
+	return location
+}
+
+//SetBool 赋 bool 类型值给着色器程序中的uniform
+func (prog *Program) SetBool(name string, value bool) {
+	if value {
+		prog.SetInt(name, 1)
+	} else {
+		prog.SetInt(name, 0)
+	}
+}
+
+//SetInt 赋 int 类型值给着色器程序中的uniform
+func (prog *Program) SetInt(name string, value int32) {
+	gl.Uniform1i(prog.getUniform(name), value)
+}
+
+//SetFloat 赋 float 类型值给着色器程序中的uniform
+func (prog *Program) SetFloat(name string, value float32) {
+	gl.Uniform1f(prog.getUniform(name), value)
+}
+
+//SetVec2XY 赋 Vec2(X,Y) 类型值给着色器程序中的uniform
+func (prog *Program) SetVec2XY(name string, x, y float32) {
+	gl.Uniform2f(prog.getUniform(name), x, y)
+}
+
+//SetVec2 赋 Vec2 类型值给着色器程序中的uniform
+func (prog *Program) SetVec2(name string, value mgl32.Vec2) {
+	prog.SetVec2XY(name, value.X(), value.Y())
+}
+
+//SetVec3XYZ 赋 Vec3(X,Y,Z) 类型值给着色器程序中的uniform
+func (prog *Program) SetVec3XYZ(name string, x, y, z float32) {
+	gl.Uniform3f(prog.getUniform(name), x, y, z)
+}
+
+//SetVec3 赋 Vec3 类型值给着色器程序中的uniform
+func (prog *Program) SetVec3(name string, value mgl32.Vec3) {
+	prog.SetVec3XYZ(name, value.X(), value.Y(), value.Z())
+}
+
+//SetVec4XYZW 赋 Vec4(X,Y,Z,W) 类型值给着色器程序中的uniform
+func (prog *Program) SetVec4XYZW(name string, x, y, z, w float32) {
+	gl.Uniform4f(prog.getUniform(name), x, y, z, w)
+}
+
+//SetVec4 赋 Vec4 类型值给着色器程序中的uniform
+func (prog *Program) SetVec4(name string, value mgl32.Vec4) {
+	prog.SetVec4XYZW(name, value.X(), value.Y(), value.Z(), value.W())
+}
+
+//SetMat2 赋 Mat2 类型值给着色器程序中的uniform
+func (prog *Program) SetMat2(name string, value mgl32.Mat2) {
+	gl.UniformMatrix2fv(prog.getUniform(name), 1, false, &value[0])
+}
+
+//SetMat3 赋 Mat3 类型值给着色器程序中的uniform
+func (prog *Program) SetMat3(name string, value mgl32.Mat3) {
+	gl.UniformMatrix3fv(prog.getUniform(name), 1, false, &value[0])
+}
+
+//SetMat4 赋 Mat4 类型值给着色器程序中的uniform
+func (prog *Program) SetMat4(name string, value mgl32.Mat4) {
+	gl.UniformMatrix4fv(prog.getUniform(name), 1, false, &value[0])
+}
@@ -0,0 +1,157 @@
+/*
+射线求交
+射线与球、轴对齐包围盒、三角形(Möller–Trumbore)和三角形网格求交, 用于鼠标拾取;
+所有函数只做CPU计算, 返回沿射线的参数t, 交点为 Origin + t*Direction
+*/
+
+package ray
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx/mesh"
+)
+
+const epsilon = 1e-7
+
+//Ray 射线, Direction 不要求是单位向量, t 以Direction的长度为单位
+type Ray struct {
+	Origin    mgl32.Vec3
+	Direction mgl32.Vec3
+}
+
+//Hit 射线与网格的交点
+type Hit struct {
+	T        float32 //沿射线的参数
+	Triangle int     //命中的三角形序号(第几个三角形, 不是索引下标)
+	U, V     float32 //重心坐标, 交点 = (1-U-V)*a + U*b + V*c
+}
+
+//At 射线上参数为t的点
+func (r Ray) At(t float32) mgl32.Vec3 {
+	return r.Origin.Add(r.Direction.Mul(t))
+}
+
+//Transform 用矩阵m变换射线, 例如用模型矩阵的逆把世界空间的射线变换到模型空间
+//Direction 不重新归一化, 因此变换前后同一交点的t相同
+func (r Ray) Transform(m mgl32.Mat4) Ray {
+	return Ray{
+		Origin:    m.Mul4x1(r.Origin.Vec4(1)).Vec3(),
+		Direction: m.Mul4x1(r.Direction.Vec4(0)).Vec3(),
+	}
+}
+
+//IntersectSphere 与球求交, 返回最近的t>=0; 起点在球内时返回出射点
+func (r Ray) IntersectSphere(center mgl32.Vec3, radius float32) (float32, bool) {
+	oc := r.Origin.Sub(center)
+	a := r.Direction.Dot(r.Direction)
+	b := oc.Dot(r.Direction)
+	c := oc.Dot(oc) - radius*radius
+	disc := b*b - a*c
+	if a == 0 || disc < 0 {
+		return 0, false
+	}
+	sq := float32(math.Sqrt(float64(disc)))
+	if t := (-b - sq) / a; t >= 0 {
+		return t, true
+	}
+	if t := (-b + sq) / a; t >= 0 {
+		return t, true
+	}
+	return 0, false
+}
+
+//IntersectAABB 与轴对齐包围盒[min, max]求交(slab法), 返回进入点的t; 起点在盒内时t为0
+func (r Ray) IntersectAABB(min, max mgl32.Vec3) (float32, bool) {
+	tmin, tmax := float32(0), float32(math.Inf(1))
+	for i := 0; i < 3; i++ {
+		o, d := r.Origin[i], r.Direction[i]
+		if d == 0 {
+			if o < min[i] || o > max[i] {
+				return 0, false
+			}
+			continue
+		}
+		t1, t2 := (min[i]-o)/d, (max[i]-o)/d
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		if t1 > tmin {
+			tmin = t1
+		}
+		if t2 < tmax {
+			tmax = t2
+		}
+		if tmin > tmax {
+			return 0, false
+		}
+	}
+	return tmin, true
+}
+
+//IntersectTriangle 与三角形abc求交(双面), 返回t>0与重心坐标u, v
+func (r Ray) IntersectTriangle(a, b, c mgl32.Vec3) (t, u, v float32, ok bool) {
+	e1, e2 := b.Sub(a), c.Sub(a)
+	p := r.Direction.Cross(e2)
+	det := e1.Dot(p)
+	if det > -epsilon && det < epsilon {
+		return 0, 0, 0, false // 射线与三角形平行
+	}
+	inv := 1 / det
+	s := r.Origin.Sub(a)
+	u = s.Dot(p) * inv
+	if u < 0 || u > 1 {
+		return 0, 0, 0, false
+	}
+	q := s.Cross(e1)
+	v = r.Direction.Dot(q) * inv
+	if v < 0 || u+v > 1 {
+		return 0, 0, 0, false
+	}
+	t = e2.Dot(q) * inv
+	if t <= epsilon {
+		return 0, 0, 0, false
+	}
+	return t, u, v, true
+}
+
+//IntersectTriangles 与三角形列表求交, 返回最近的交点
+//indices 每3个一组组成三角形, 为nil时positions按顺序每3个顶点一个三角形
+func (r Ray) IntersectTriangles(positions []mgl32.Vec3, indices []uint32) (Hit, bool) {
+	var best Hit
+	found := false
+	count := len(positions) / 3
+	if indices != nil {
+		count = len(indices) / 3
+	}
+	for i := 0; i < count; i++ {
+		var a, b, c mgl32.Vec3
+		if indices != nil {
+			a, b, c = positions[indices[3*i]], positions[indices[3*i+1]], positions[indices[3*i+2]]
+		} else {
+			a, b, c = positions[3*i], positions[3*i+1], positions[3*i+2]
+		}
+		t, u, v, ok := r.IntersectTriangle(a, b, c)
+		if ok && (!found || t < best.T) {
+			best = Hit{T: t, Triangle: i, U: u, V: v}
+			found = true
+		}
+	}
+	return best, found
+}
+
+//IntersectMesh 与网格几何求交, 返回最近的交点
+func (r Ray) IntersectMesh(g *mesh.Geometry) (Hit, bool) {
+	return r.IntersectTriangles(g.Positions, g.Indices)
+}
+
+//Positions 从交错顶点数组中取出位置, 每个顶点stride个float, 位置为前3个
+func Positions(vertices []float32, stride int) []mgl32.Vec3 {
+	positions := make([]mgl32.Vec3, 0, len(vertices)/stride)
+	for i := 0; i+3 <= len(vertices); i += stride {
+		positions = append(positions, mgl32.Vec3{vertices[i], vertices[i+1], vertices[i+2]})
+	}
+	return positions
+}
@@ -0,0 +1,170 @@
+package ray
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx/mesh"
+)
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-5
+}
+
+func TestIntersectSphere(t *testing.T) {
+	center := mgl32.Vec3{0, 0, -5}
+	tests := []struct {
+		name  string
+		ray   Ray
+		want  float32
+		hitOK bool
+	}{
+		{"hit", Ray{mgl32.Vec3{}, mgl32.Vec3{0, 0, -1}}, 4, true},
+		{"hit with long direction", Ray{mgl32.Vec3{}, mgl32.Vec3{0, 0, -2}}, 2, true},
+		{"tangent", Ray{mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, 0, -1}}, 5, true},
+		{"miss", Ray{mgl32.Vec3{1.5, 0, 0}, mgl32.Vec3{0, 0, -1}}, 0, false},
+		{"behind", Ray{mgl32.Vec3{}, mgl32.Vec3{0, 0, 1}}, 0, false},
+		{"inside", Ray{mgl32.Vec3{0, 0, -5}, mgl32.Vec3{0, 1, 0}}, 1, true},
+		{"zero direction", Ray{mgl32.Vec3{}, mgl32.Vec3{}}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := tt.ray.IntersectSphere(center, 1)
+		if ok != tt.hitOK || !near(got, tt.want) {
+			t.Errorf("%s: t %v %v, want %v %v", tt.name, got, ok, tt.want, tt.hitOK)
+		}
+	}
+}
+
+func TestIntersectAABB(t *testing.T) {
+	min, max := mgl32.Vec3{-1, -1, -1}, mgl32.Vec3{1, 1, 1}
+	tests := []struct {
+		name  string
+		ray   Ray
+		want  float32
+		hitOK bool
+	}{
+		{"hit", Ray{mgl32.Vec3{-3, 0.5, 0.5}, mgl32.Vec3{1, 0, 0}}, 2, true},
+		{"diagonal hit", Ray{mgl32.Vec3{-3, -3, -3}, mgl32.Vec3{1, 1, 1}}, 2, true},
+		{"miss", Ray{mgl32.Vec3{-3, 2, 0}, mgl32.Vec3{1, 0, 0}}, 0, false},
+		{"diagonal miss", Ray{mgl32.Vec3{-3, 0, 0}, mgl32.Vec3{1, 2, 0}}, 0, false},
+		{"behind", Ray{mgl32.Vec3{3, 0, 0}, mgl32.Vec3{1, 0, 0}}, 0, false},
+		{"inside", Ray{mgl32.Vec3{0.5, 0, 0}, mgl32.Vec3{0, 0, 1}}, 0, true},
+		// 方向分量为0时按起点是否在这一轴的范围内判断
+		{"axis parallel inside the slab", Ray{mgl32.Vec3{0.5, -4, 0.5}, mgl32.Vec3{0, 2, 0}}, 1.5, true},
+		{"axis parallel outside the slab", Ray{mgl32.Vec3{1.5, -4, 0}, mgl32.Vec3{0, 1, 0}}, 0, false},
+		{"axis parallel on the face", Ray{mgl32.Vec3{1, -4, 0}, mgl32.Vec3{0, 1, 0}}, 3, true},
+	}
+	for _, tt := range tests {
+		got, ok := tt.ray.IntersectAABB(min, max)
+		if ok != tt.hitOK || !near(got, tt.want) {
+			t.Errorf("%s: t %v %v, want %v %v", tt.name, got, ok, tt.want, tt.hitOK)
+		}
+	}
+}
+
+func TestIntersectTriangle(t *testing.T) {
+	// 逆时针三角形, 正面朝+Z
+	a, b, c := mgl32.Vec3{0, 0, 0}, mgl32.Vec3{1, 0, 0}, mgl32.Vec3{0, 1, 0}
+	tests := []struct {
+		name    string
+		ray     Ray
+		t, u, v float32
+		hitOK   bool
+	}{
+		{"front", Ray{mgl32.Vec3{0.25, 0.5, 2}, mgl32.Vec3{0, 0, -1}}, 2, 0.25, 0.5, true},
+		{"back face", Ray{mgl32.Vec3{0.25, 0.5, -2}, mgl32.Vec3{0, 0, 1}}, 2, 0.25, 0.5, true},
+		{"vertex b", Ray{mgl32.Vec3{1, 0, 1}, mgl32.Vec3{0, 0, -1}}, 1, 1, 0, true},
+		{"outside the edge", Ray{mgl32.Vec3{0.6, 0.6, 1}, mgl32.Vec3{0, 0, -1}}, 0, 0, 0, false},
+		{"negative u", Ray{mgl32.Vec3{-0.1, 0.5, 1}, mgl32.Vec3{0, 0, -1}}, 0, 0, 0, false},
+		{"behind", Ray{mgl32.Vec3{0.25, 0.25, 1}, mgl32.Vec3{0, 0, 1}}, 0, 0, 0, false},
+		{"parallel", Ray{mgl32.Vec3{-1, 0.25, 0}, mgl32.Vec3{1, 0, 0}}, 0, 0, 0, false},
+		{"origin on the plane", Ray{mgl32.Vec3{0.25, 0.25, 0}, mgl32.Vec3{0, 0, -1}}, 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		gt, gu, gv, ok := tt.ray.IntersectTriangle(a, b, c)
+		if ok != tt.hitOK || !near(gt, tt.t) || !near(gu, tt.u) || !near(gv, tt.v) {
+			t.Errorf("%s: t %v u %v v %v %v, want %v %v %v %v", tt.name, gt, gu, gv, ok, tt.t, tt.u, tt.v, tt.hitOK)
+		}
+		if ok {
+			// 重心坐标还原的点就是射线上的交点
+			p := a.Mul(1 - gu - gv).Add(b.Mul(gu)).Add(c.Mul(gv))
+			if !p.ApproxEqualThreshold(tt.ray.At(gt), 1e-5) {
+				t.Errorf("%s: barycentric point %v, ray point %v", tt.name, p, tt.ray.At(gt))
+			}
+		}
+	}
+}
+
+//TestIntersectMesh 起点在网格外时命中最近的面, 在网格内时命中出射的面
+func TestIntersectMesh(t *testing.T) {
+	cube := mesh.Cube(2)
+	tests := []struct {
+		name  string
+		ray   Ray
+		want  float32
+		hitOK bool
+	}{
+		{"front", Ray{mgl32.Vec3{0.2, 0.3, 5}, mgl32.Vec3{0, 0, -1}}, 4, true},
+		{"from the side", Ray{mgl32.Vec3{-4, 0.2, 0.3}, mgl32.Vec3{2, 0, 0}}, 1.5, true},
+		{"inside", Ray{mgl32.Vec3{0.2, 0.3, 0}, mgl32.Vec3{0, 0, -1}}, 1, true},
+		{"miss", Ray{mgl32.Vec3{2, 0, 5}, mgl32.Vec3{0, 0, -1}}, 0, false},
+	}
+	for _, tt := range tests {
+		hit, ok := tt.ray.IntersectMesh(cube)
+		if ok != tt.hitOK || !near(hit.T, tt.want) {
+			t.Errorf("%s: hit %+v %v, want t %v %v", tt.name, hit, ok, tt.want, tt.hitOK)
+		}
+	}
+
+	// 不带索引时每3个顶点一个三角形, Triangle为三角形序号
+	positions := []mgl32.Vec3{
+		{0, 0, -3}, {1, 0, -3}, {0, 1, -3},
+		{0, 0, -1}, {1, 0, -1}, {0, 1, -1},
+	}
+	hit, ok := Ray{mgl32.Vec3{0.2, 0.2, 0}, mgl32.Vec3{0, 0, -1}}.IntersectTriangles(positions, nil)
+	if !ok || hit.Triangle != 1 || !near(hit.T, 1) {
+		t.Errorf("unindexed: hit %+v %v, want triangle 1 at t 1", hit, ok)
+	}
+}
+
+//TestTransform 用模型矩阵的逆变换射线后, 在模型空间求得的t与世界空间的交点一致
+func TestTransform(t *testing.T) {
+	model := mgl32.Translate3D(3, 0, -10).
+		Mul4(mgl32.HomogRotate3DY(mgl32.DegToRad(30))).
+		Mul4(mgl32.Scale3D(2, 2, 2))
+	world := Ray{mgl32.Vec3{3, 0.5, 0}, mgl32.Vec3{0, 0, -1}}
+	local := world.Transform(model.Inv())
+
+	hit, ok := local.IntersectMesh(mesh.Cube(2))
+	if !ok {
+		t.Fatal("transformed ray misses the cube")
+	}
+	// 缩放2倍的立方体绕Y轴旋转30°, 中心在z=-10, 沿-Z方向的前表面到中心的距离为2/cos30°
+	want := 10 - 2/float32(math.Cos(math.Pi/6))
+	if math.Abs(float64(hit.T-want)) > 1e-4 {
+		t.Errorf("t %v, want %v", hit.T, want)
+	}
+	p := model.Mul4x1(local.At(hit.T).Vec4(1)).Vec3()
+	if !p.ApproxEqualThreshold(world.At(hit.T), 1e-4) {
+		t.Errorf("local hit maps to %v, world ray point %v", p, world.At(hit.T))
+	}
+
+	sphere, ok := local.IntersectSphere(mgl32.Vec3{}, 1)
+	worldSphere, worldOK := world.IntersectSphere(mgl32.Vec3{3, 0, -10}, 2)
+	if !ok || !worldOK || math.Abs(float64(sphere-worldSphere)) > 1e-4 {
+		t.Errorf("sphere t %v %v in model space, %v %v in world space", sphere, ok, worldSphere, worldOK)
+	}
+}
+
+func TestPositions(t *testing.T) {
+	vertices := []float32{
+		1, 2, 3, 0, 0,
+		4, 5, 6, 1, 1,
+	}
+	got := Positions(vertices, 5)
+	if len(got) != 2 || got[0] != (mgl32.Vec3{1, 2, 3}) || got[1] != (mgl32.Vec3{4, 5, 6}) {
+		t.Errorf("positions %v", got)
+	}
+}
@@ -0,0 +1,163 @@
+/*
+着色器程序反射
+链接成功后查询一次活动的uniform与顶点属性, 缓存uniform位置,
+之后设置uniform时不再调用gl.GetUniformLocation
+*/
+
+package gfx
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+//ActiveUniform 着色器程序中的一个活动uniform
+type ActiveUniform struct {
+	Name     string // 数组去掉了"[0]"后缀
+	Location int32  // uniform块中的成员为-1
+	Type     uint32 // 如:gl.FLOAT_MAT4
+	Size     int32  // 数组长度, 非数组为1
+}
+
+//ActiveAttrib 着色器程序中的一个活动顶点属性
+type ActiveAttrib struct {
+	Name     string
+	Location int32
+	Type     uint32 // 如:gl.FLOAT_VEC3
+}
+
+//Uniforms 返回链接时查询到的活动uniform
+func (prog *Program) Uniforms() []ActiveUniform {
+	return prog.uniforms
+}
+
+//Attributes 返回链接时查询到的活动顶点属性, 不含gl_开头的内置变量
+func (prog *Program) Attributes() []ActiveAttrib {
+	return prog.attribs
+}
+
+//SetStrict 严格模式下设置不存在(或被编译器优化掉)的uniform会panic, 视为程序错误, 只用于调试;
+//否则每个名字只打印一次警告. 需要把缺失的uniform作为错误处理时使用Uniform或CheckUniforms
+func (prog *Program) SetStrict(strict bool) {
+	prog.strict = strict
+}
+
+//reflect 查询活动uniform和顶点属性并缓存uniform位置
+//数组uniform按"name"、"name[0]"..."name[n-1]"都记录位置
+func (prog *Program) reflect() {
+	prog.uniforms = prog.activeUniforms()
+	prog.attribs = prog.activeAttributes()
+	prog.locations = make(map[string]int32)
+	for _, u := range prog.uniforms {
+		if u.Location < 0 {
+			continue
+		}
+		prog.locations[u.Name] = u.Location
+		if u.Size > 1 {
+			for i := int32(0); i < u.Size; i++ {
+				elem := u.Name + "[" + strconv.Itoa(int(i)) + "]"
+				prog.locations[elem] = gl.GetUniformLocation(prog.handle, gl.Str(elem+"\x00"))
+			}
+		}
+	}
+}
+
+//uniformLocation 从缓存中查找uniform位置, 不存在时返回-1(设置-1位置会被OpenGL忽略)
+func (prog *Program) uniformLocation(name string) (int32, bool) {
+	location, ok := prog.locations[name]
+	if !ok {
+		return -1, false
+	}
+	return location, true
+}
+
+//Uniform 返回uniform的位置, 不存在(或被编译器优化掉)时返回-1和错误
+func (prog *Program) Uniform(name string) (int32, error) {
+	location, ok := prog.uniformLocation(name)
+	if !ok {
+		return location, fmt.Errorf("uniform %s is not active in program", name)
+	}
+	return location, nil
+}
+
+//CheckUniforms 检查names都是着色器程序中的活动uniform, 返回第一个缺失的uniform的错误
+//在创建程序后调用一次, 之后设置这些uniform时就不会再有警告
+func (prog *Program) CheckUniforms(names ...string) error {
+	for _, name := range names {
+		if _, err := prog.Uniform(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//getUniform 设置uniform时使用的位置, 不存在时按严格模式panic或打印一次警告
+func (prog *Program) getUniform(name string) int32 {
+	location, err := prog.Uniform(name)
+	if err == nil {
+		return location
+	}
+	if prog.strict {
+		panic(err)
+	}
+	if !prog.warned[name] {
+		if prog.warned == nil {
+			prog.warned = make(map[string]bool)
+		}
+		prog.warned[name] = true
+		log.Printf("%v, ignoring", err)
+	}
+	return location
+}
+
+//activeUniforms 查询着色器程序的活动uniform
+func (prog *Program) activeUniforms() []ActiveUniform {
+	var count, maxLength int32
+	gl.GetProgramiv(prog.handle, gl.ACTIVE_UNIFORMS, &count)
+	gl.GetProgramiv(prog.handle, gl.ACTIVE_UNIFORM_MAX_LENGTH, &maxLength)
+
+	var uniforms []ActiveUniform
+	buf := make([]uint8, maxLength+1)
+	for i := int32(0); i < count; i++ {
+		var length, size int32
+		var glType uint32
+		gl.GetActiveUniform(prog.handle, uint32(i), maxLength+1, &length, &size, &glType, &buf[0])
+		name := string(buf[:length])
+		uniforms = append(uniforms, ActiveUniform{
+			Name:     strings.TrimSuffix(name, "[0]"),
+			Location: gl.GetUniformLocation(prog.handle, gl.Str(name+"\x00")),
+			Type:     glType,
+			Size:     size,
+		})
+	}
+	return uniforms
+}
+
+//activeAttributes 查询着色器程序的活动顶点属性, 不含gl_开头的内置变量
+func (prog *Program) activeAttributes() []ActiveAttrib {
+	var count, maxLength int32
+	gl.GetProgramiv(prog.handle, gl.ACTIVE_ATTRIBUTES, &count)
+	gl.GetProgramiv(prog.handle, gl.ACTIVE_ATTRIBUTE_MAX_LENGTH, &maxLength)
+
+	var attribs []ActiveAttrib
+	buf := make([]uint8, maxLength+1)
+	for i := int32(0); i < count; i++ {
+		var length, size int32
+		var glType uint32
+		gl.GetActiveAttrib(prog.handle, uint32(i), maxLength+1, &length, &size, &glType, &buf[0])
+		name := string(buf[:length])
+		if strings.HasPrefix(name, "gl_") {
+			continue
+		}
+		attribs = append(attribs, ActiveAttrib{
+			Name:     name,
+			Location: gl.GetAttribLocation(prog.handle, gl.Str(name+"\x00")),
+			Type:     glType,
+		})
+	}
+	return attribs
+}
@@ -0,0 +1,345 @@
+/*
+资源管理
+按相对路径(以"/"分隔, 如 "src/1.png")在若干搜索根目录或文件系统(如 embed.FS)中依次查找资源文件,
+按规范路径缓存已创建的着色器程序、纹理和网格, 重复请求返回同一个OpenGL对象并增加引用计数,
+句柄的Delete只释放一个引用, 最后一个引用释放时才删除OpenGL对象
+Manager不是并发安全的, 只能在渲染线程(持有OpenGL上下文的线程)使用
+*/
+
+package resource
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+
+	"gfx"
+	"gfx/mesh"
+	"gfx/obj"
+	"gfx/texture"
+)
+
+//Manager 资源管理器
+type Manager struct {
+	roots   []root
+	entries map[string]*entry
+}
+
+//root 一个搜索位置: 操作系统的目录或文件系统
+type root struct {
+	dir  string
+	fsys fs.FS
+}
+
+//entry 缓存的一个资源
+type entry struct {
+	refs  int
+	value interface{}
+	free  func()
+}
+
+//location 资源文件解析后的位置
+type location struct {
+	fsys fs.FS  // nil时file为操作系统的路径
+	file string // 在fsys中的路径或操作系统的路径
+	key  string // 规范路径, 用作缓存键
+}
+
+//NewManager 创建资源管理器, 按顺序在roots目录中查找资源
+func NewManager(roots ...string) *Manager {
+	m := &Manager{entries: make(map[string]*entry)}
+	for _, dir := range roots {
+		m.AddDir(dir)
+	}
+	return m
+}
+
+//AddDir 追加一个搜索根目录
+//从目录中加载的着色器可以被 gfx.ShaderWatcher 监视
+func (m *Manager) AddDir(dir string) {
+	m.roots = append(m.roots, root{dir: dir})
+}
+
+//AddFS 追加一个搜索文件系统, 如 embed.FS 或 os.DirFS
+func (m *Manager) AddFS(fsys fs.FS) {
+	m.roots = append(m.roots, root{fsys: fsys})
+}
+
+//DirOrEmbedded 磁盘目录dir中有embedded的所有顶层文件与目录时返回os.DirFS(dir), 否则返回embedded
+//在例程目录中运行时读取磁盘上的文件, 修改着色器后可以热重载; 在其他目录下运行时使用编译进程序的副本
+func DirOrEmbedded(dir string, embedded fs.FS) fs.FS {
+	entries, err := fs.ReadDir(embedded, ".")
+	if err != nil {
+		return embedded
+	}
+	for _, e := range entries {
+		if _, err := os.Stat(filepath.Join(dir, e.Name())); err != nil {
+			return embedded
+		}
+	}
+	return os.DirFS(dir)
+}
+
+//Program 由顶点着色器和片段着色器文件创建着色器程序, defines 插入到两个着色器中
+func (m *Manager) Program(vertFile, fragFile string, defines ...gfx.Define) (*Program, error) {
+	vert, err := m.resolve(vertFile)
+	if err != nil {
+		return nil, err
+	}
+	frag, err := m.resolve(fragFile)
+	if err != nil {
+		return nil, err
+	}
+	key := "program:" + vert.key + "|" + frag.key
+	for _, d := range defines {
+		key += "|" + d.Name + "=" + d.Value
+	}
+
+	value, err := m.acquire(key, func() (interface{}, func(), error) {
+		vertShader, err := gfx.NewShaderFromFS(vert.fsys, vert.file, gl.VERTEX_SHADER, defines...)
+		if err != nil {
+			return nil, nil, err
+		}
+		fragShader, err := gfx.NewShaderFromFS(frag.fsys, frag.file, gl.FRAGMENT_SHADER, defines...)
+		if err != nil {
+			vertShader.Delete()
+			return nil, nil, err
+		}
+		prog, err := gfx.NewProgram(vertShader, fragShader)
+		if err != nil {
+			return nil, nil, err
+		}
+		return prog, prog.Delete, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Program{Program: value.(*gfx.Program), handle: handle{m: m, key: key}}, nil
+}
+
+//Texture 由图片文件创建纹理, 同一文件以不同选项创建的纹理分别缓存
+func (m *Manager) Texture(file string, opts texture.TextureOptions) (*Texture, error) {
+	loc, err := m.resolve(file)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("texture:%s|%+v", loc.key, opts)
+
+	value, err := m.acquire(key, func() (interface{}, func(), error) {
+		var tex *texture.Texture
+		var err error
+		if loc.fsys == nil {
+			tex, err = texture.NewTextureFromFile(loc.file, opts)
+		} else {
+			tex, err = texture.NewTextureFromFS(loc.fsys, loc.file, opts)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		return tex, tex.Delete, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Texture{Texture: value.(*texture.Texture), handle: handle{m: m, key: key}}, nil
+}
+
+//Cubemap 由单张图片文件创建立方体贴图, 布局见 texture.NewCubemapFromImage
+func (m *Manager) Cubemap(file string, opts texture.TextureOptions) (*Texture, error) {
+	loc, err := m.resolve(file)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("cubemap:%s|%+v", loc.key, opts)
+
+	value, err := m.acquire(key, func() (interface{}, func(), error) {
+		var tex *texture.Texture
+		var err error
+		if loc.fsys == nil {
+			tex, err = texture.NewCubemapFromFile(loc.file, opts)
+		} else {
+			tex, err = texture.NewCubemapFromFS(loc.fsys, loc.file, opts)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		return tex, tex.Delete, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Texture{Texture: value.(*texture.Texture), handle: handle{m: m, key: key}}, nil
+}
+
+//Mesh 由OBJ文件创建网格, 所有部分合并为一个网格, 忽略材质
+//attribs 选择交错输出的顶点属性, layout 必须与之一致
+func (m *Manager) Mesh(file string, attribs mesh.Attrib, layout *gfx.VertexLayout) (*Mesh, error) {
+	if layout.Floats() != attribs.Stride() {
+		return nil, fmt.Errorf("load mesh file %s: layout has %d floats per vertex, attributes have %d",
+			file, layout.Floats(), attribs.Stride())
+	}
+	loc, err := m.resolve(file)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("mesh:%s|%d", loc.key, attribs)
+
+	value, err := m.acquire(key, func() (interface{}, func(), error) {
+		f, err := loc.open()
+		if err != nil {
+			return nil, nil, fmt.Errorf("load obj file %s: %v", file, err)
+		}
+		defer f.Close()
+		model, err := obj.Parse(f, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("load obj file %s: %v", file, err)
+		}
+		vertices, indices := model.Interleave(attribs)
+		vao, err := gfx.NewMesh(gl.TRIANGLES, layout, vertices, indices)
+		if err != nil {
+			return nil, nil, fmt.Errorf("load obj file %s: %v", file, err)
+		}
+		return vao, vao.Delete, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Mesh{Mesh: value.(*gfx.Mesh), handle: handle{m: m, key: key}}, nil
+}
+
+//Loaded 当前缓存的资源及其引用计数, 按缓存键排序, 用于调试
+func (m *Manager) Loaded() []string {
+	keys := make([]string, 0, len(m.entries))
+	for key, e := range m.entries {
+		keys = append(keys, fmt.Sprintf("%s (%d)", key, e.refs))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+//Close 删除所有缓存的资源, 不论引用计数, 之后所有句柄都不再可用
+func (m *Manager) Close() {
+	for key, e := range m.entries {
+		e.free()
+		delete(m.entries, key)
+	}
+}
+
+//acquire 返回缓存的资源并增加引用计数, 没有时调用load创建
+func (m *Manager) acquire(key string, load func() (interface{}, func(), error)) (interface{}, error) {
+	if e, ok := m.entries[key]; ok {
+		e.refs++
+		return e.value, nil
+	}
+	value, free, err := load()
+	if err != nil {
+		return nil, err
+	}
+	m.entries[key] = &entry{refs: 1, value: value, free: free}
+	return value, nil
+}
+
+//release 减少引用计数, 降为0时删除资源
+func (m *Manager) release(key string) {
+	e, ok := m.entries[key]
+	if !ok {
+		return
+	}
+	e.refs--
+	if e.refs == 0 {
+		e.free()
+		delete(m.entries, key)
+	}
+}
+
+//resolve 依次在各搜索位置中查找name, 返回第一个存在的文件
+func (m *Manager) resolve(name string) (location, error) {
+	clean := path.Clean(strings.TrimPrefix(name, "./"))
+	if !fs.ValidPath(clean) {
+		return location{}, fmt.Errorf("resource %s: invalid path", name)
+	}
+	for i, r := range m.roots {
+		if r.fsys != nil {
+			if _, err := fs.Stat(r.fsys, clean); err == nil {
+				return location{fsys: r.fsys, file: clean, key: fmt.Sprintf("fs%d:%s", i, clean)}, nil
+			}
+			continue
+		}
+		file := filepath.Join(r.dir, filepath.FromSlash(clean))
+		if _, err := os.Stat(file); err != nil {
+			continue
+		}
+		key, err := filepath.Abs(file)
+		if err != nil {
+			return location{}, fmt.Errorf("resource %s: %v", name, err)
+		}
+		if real, err := filepath.EvalSymlinks(key); err == nil {
+			key = real
+		}
+		return location{file: file, key: key}, nil
+	}
+	return location{}, fmt.Errorf("resource %s: not found in %d search roots", name, len(m.roots))
+}
+
+//open 打开解析后的文件
+func (loc location) open() (fs.File, error) {
+	if loc.fsys == nil {
+		return os.Open(loc.file)
+	}
+	return loc.fsys.Open(loc.file)
+}
+
+//handle 资源句柄, 每次请求得到一个新句柄, 各自释放一次引用
+type handle struct {
+	m        *Manager
+	key      string
+	released bool
+}
+
+//release 释放句柄持有的引用, 重复调用无效
+func (h *handle) release() {
+	if h.released {
+		return
+	}
+	h.released = true
+	h.m.release(h.key)
+}
+
+//Program 着色器程序句柄
+type Program struct {
+	*gfx.Program
+	handle
+}
+
+//Delete 释放引用, 最后一个引用释放时删除着色器程序
+func (p *Program) Delete() {
+	p.release()
+}
+
+//Texture 纹理句柄
+type Texture struct {
+	*texture.Texture
+	handle
+}
+
+//Delete 释放引用, 最后一个引用释放时删除纹理
+func (t *Texture) Delete() {
+	t.release()
+}
+
+//Mesh 网格句柄
+type Mesh struct {
+	*gfx.Mesh
+	handle
+}
+
+//Delete 释放引用, 最后一个引用释放时删除网格
+func (m *Mesh) Delete() {
+	m.release()
+}
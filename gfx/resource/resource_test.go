@@ -0,0 +1,236 @@
+package resource
+
+import (
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+//TestDirOrEmbedded 目录中有embedded的全部顶层条目时读取磁盘, 缺少任何一个时使用embedded
+func TestDirOrEmbedded(t *testing.T) {
+	embedded := fstest.MapFS{
+		"shaders/a.vs": {Data: []byte("embedded")},
+		"images/a.png": {Data: []byte("embedded")},
+	}
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "shaders"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "shaders", "a.vs"), []byte("disk"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	read := func(fsys fs.FS) string {
+		data, err := fs.ReadFile(fsys, "shaders/a.vs")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	if got := read(DirOrEmbedded(dir, embedded)); got != "embedded" {
+		t.Errorf("without images: read %q, want embedded", got)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "images"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(DirOrEmbedded(dir, embedded)); got != "disk" {
+		t.Errorf("with all directories: read %q, want disk", got)
+	}
+}
+
+//testRoots 两个目录之间夹一个文件系统, 各有同名的文件
+func testRoots(t *testing.T) (m *Manager, first, last string) {
+	t.Helper()
+	first, last = t.TempDir(), t.TempDir()
+	files := map[string]string{
+		filepath.Join(first, "a.txt"):        "first a",
+		filepath.Join(first, "sub", "b.txt"): "first b",
+		filepath.Join(last, "c.txt"):         "last c",
+		filepath.Join(last, "d.txt"):         "last d",
+	}
+	for file, data := range files {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fsys := fstest.MapFS{
+		"a.txt": {Data: []byte("fs a")},
+		"c.txt": {Data: []byte("fs c")},
+	}
+	m = NewManager(first)
+	m.AddFS(fsys)
+	m.AddDir(last)
+	return m, first, last
+}
+
+//realPath 目录文件的缓存键: 绝对路径并解析符号链接(临时目录可能在符号链接下)
+func realPath(t *testing.T, file string) string {
+	t.Helper()
+	real, err := filepath.EvalSymlinks(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	abs, err := filepath.Abs(real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return abs
+}
+
+//TestResolve 按添加顺序查找, 先找到的位置优先, 同一文件的不同写法得到同一个缓存键
+func TestResolve(t *testing.T) {
+	m, first, last := testRoots(t)
+	tests := []struct {
+		name, key, data string
+	}{
+		{"a.txt", realPath(t, filepath.Join(first, "a.txt")), "first a"},
+		{"c.txt", "fs1:c.txt", "fs c"},
+		{"d.txt", realPath(t, filepath.Join(last, "d.txt")), "last d"},
+		{"sub/b.txt", realPath(t, filepath.Join(first, "sub", "b.txt")), "first b"},
+		{"./sub/b.txt", realPath(t, filepath.Join(first, "sub", "b.txt")), "first b"},
+		{"sub/./b.txt", realPath(t, filepath.Join(first, "sub", "b.txt")), "first b"},
+		{"sub/../c.txt", "fs1:c.txt", "fs c"},
+		{"./sub//../d.txt", realPath(t, filepath.Join(last, "d.txt")), "last d"},
+	}
+	for _, tt := range tests {
+		loc, err := m.resolve(tt.name)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if loc.key != tt.key {
+			t.Errorf("%s: key %q, want %q", tt.name, loc.key, tt.key)
+		}
+		f, err := loc.open()
+		if err != nil {
+			t.Errorf("%s: open: %v", tt.name, err)
+			continue
+		}
+		data, err := ioutil.ReadAll(f)
+		f.Close()
+		if err != nil || string(data) != tt.data {
+			t.Errorf("%s: read %q %v, want %q", tt.name, data, err, tt.data)
+		}
+	}
+
+	invalid := []struct {
+		name, want string
+	}{
+		{"../a.txt", "resource ../a.txt: invalid path"},
+		{"sub/../../a.txt", "resource sub/../../a.txt: invalid path"},
+		{"/a.txt", "resource /a.txt: invalid path"},
+		{"missing.txt", "resource missing.txt: not found in 3 search roots"},
+	}
+	for _, tt := range invalid {
+		if _, err := m.resolve(tt.name); err == nil || err.Error() != tt.want {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+//TestResolveSameFile 从不同的搜索目录或经符号链接找到的同一文件只有一个缓存键
+func TestResolveSameFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "sub", "b.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager(dir, filepath.Join(dir, "sub"), filepath.Join(dir, "sub", "..", "sub"))
+	if err := os.Symlink(filepath.Join(dir, "sub"), filepath.Join(dir, "link")); err == nil {
+		m.AddDir(filepath.Join(dir, "link"))
+	}
+
+	want := realPath(t, filepath.Join(dir, "sub", "b.txt"))
+	for _, name := range []string{"sub/b.txt", "b.txt", "link/b.txt"} {
+		loc, err := m.resolve(name)
+		if name == "link/b.txt" && err != nil {
+			continue // 不支持符号链接
+		}
+		if err != nil || loc.key != want {
+			t.Errorf("%s: key %q %v, want %q", name, loc.key, err, want)
+		}
+	}
+}
+
+//TestAcquireRelease 同一缓存键只加载一次, 最后一个引用释放时才释放资源
+func TestAcquireRelease(t *testing.T) {
+	m := NewManager()
+	loads, frees := 0, 0
+	load := func() (interface{}, func(), error) {
+		loads++
+		return loads, func() { frees++ }, nil
+	}
+
+	a, err := m.acquire("a", load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := m.acquire("a", load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != 1 || b != 1 || loads != 1 {
+		t.Fatalf("acquired %v and %v after %d loads, want the first value once", a, b, loads)
+	}
+	if _, err := m.acquire("b", load); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Loaded(); !reflect.DeepEqual(got, []string{"a (2)", "b (1)"}) {
+		t.Errorf("loaded %v", got)
+	}
+
+	h1, h2 := handle{m: m, key: "a"}, handle{m: m, key: "a"}
+	h1.release()
+	h1.release() // 同一句柄重复释放无效
+	if frees != 0 {
+		t.Errorf("freed %d times with one reference left", frees)
+	}
+	h2.release()
+	if frees != 1 {
+		t.Errorf("freed %d times after the last reference, want 1", frees)
+	}
+	m.release("a") // 已删除的键
+	if frees != 1 {
+		t.Errorf("freed %d times after releasing a deleted key", frees)
+	}
+	if got := m.Loaded(); !reflect.DeepEqual(got, []string{"b (1)"}) {
+		t.Errorf("loaded %v after release", got)
+	}
+
+	// 释放后再请求重新加载
+	if v, err := m.acquire("a", load); err != nil || v != 3 {
+		t.Errorf("reacquired %v %v, want a new value 3", v, err)
+	}
+	m.Close()
+	if frees != 3 || len(m.Loaded()) != 0 {
+		t.Errorf("after Close: freed %d times, loaded %v", frees, m.Loaded())
+	}
+}
+
+//TestAcquireError 加载失败时不缓存, 下次请求重新加载
+func TestAcquireError(t *testing.T) {
+	m := NewManager()
+	loads := 0
+	fail := func() (interface{}, func(), error) {
+		loads++
+		return nil, nil, fmt.Errorf("load %d failed", loads)
+	}
+	for i := 1; i <= 2; i++ {
+		if _, err := m.acquire("a", fail); err == nil || err.Error() != fmt.Sprintf("load %d failed", i) {
+			t.Errorf("acquire %d: error %v", i, err)
+		}
+	}
+	if len(m.Loaded()) != 0 {
+		t.Errorf("loaded %v after failed loads", m.Loaded())
+	}
+}
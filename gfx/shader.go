@@ -0,0 +1,74 @@
+/*
+创建着色器对象
+将源码字符串赋予着色器对象
+编译着色器
+*/
+
+package gfx
+
+import (
+	"io/fs"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+//Shader 编译好的单个着色器对象
+type Shader struct {
+	handle  uint32
+	sType   uint32
+	fsys    fs.FS    // 源码文件所在的文件系统, nil为操作系统的文件
+	file    string   // 源码文件, 从字符串创建时为空
+	defines []Define // 创建时传入的宏定义, 重新加载时沿用
+	files   []string // 源码文件及其#include的文件
+}
+
+//NewShader 从源码字符串生成并编译着色器, 编译失败时返回*ShaderError
+//src 着色器源码(无需以"\x00"结尾)
+//sType 着色器类型,如:gl.VERTEX_SHADER,gl.FRAGMENT_SHADER
+func NewShader(src string, sType uint32) (*Shader, error) {
+	return compileShader(stringSource(src), sType, "")
+}
+
+//NewShaderFromFile 从文件中读取源码生成并编译着色器
+//源码中的 #include "file" 相对于所在文件展开, defines 插入到#version行之后
+//编译失败时返回*ShaderError, 其中的行号已映射回原文件和行号
+func NewShaderFromFile(file string, sType uint32, defines ...Define) (*Shader, error) {
+	return NewShaderFromFS(nil, file, sType, defines...)
+}
+
+//NewShaderFromFS 从文件系统fsys(如 embed.FS)中读取源码生成并编译着色器, 其余同NewShaderFromFile
+//fsys为nil时等同于NewShaderFromFile
+func NewShaderFromFS(fsys fs.FS, file string, sType uint32, defines ...Define) (*Shader, error) {
+	src, err := PreprocessFS(fsys, file, defines...)
+	if err != nil {
+		return nil, err
+	}
+	shader, err := compileShader(src, sType, file)
+	if err != nil {
+		return nil, err
+	}
+	shader.fsys = fsys
+	shader.file = file
+	shader.defines = defines
+	shader.files = src.Files
+	return shader, nil
+}
+
+//Delete 删除着色器对象
+func (shader *Shader) Delete() {
+	gl.DeleteShader(shader.handle)
+}
+
+//compileShader 编译预处理后的源码, file 仅用于错误信息
+func compileShader(src *Source, sType uint32, file string) (*Shader, error) {
+	handle := gl.CreateShader(sType)
+	glSrc, freeFn := gl.Strs(src.Code + "\x00")
+	defer freeFn()
+	gl.ShaderSource(handle, 1, glSrc, nil)
+	gl.CompileShader(handle)
+	if log, ok := getInfoLog(handle, gl.COMPILE_STATUS, gl.GetShaderiv, gl.GetShaderInfoLog); !ok {
+		gl.DeleteShader(handle)
+		return nil, &ShaderError{Stage: sType, File: file, Log: log, Messages: parseShaderLog(log, src)}
+	}
+	return &Shader{handle: handle, sType: sType}, nil
+}
@@ -0,0 +1,181 @@
+/*
+着色器编译与链接错误
+解析驱动日志中的每条信息(文件、行、列、级别), 行号经预处理映射回原文件,
+并可输出标注了出错位置的源码片段
+*/
+
+package gfx
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+//ShaderError 着色器编译或程序链接失败
+type ShaderError struct {
+	Stage    uint32 // 如:gl.VERTEX_SHADER, 链接失败时为0
+	File     string // 入口源码文件, 从字符串创建或链接失败时为空
+	Log      string // 驱动返回的原始日志
+	Messages []ShaderMessage
+}
+
+//ShaderMessage 驱动日志中的一条信息
+type ShaderMessage struct {
+	File     string // 所在的原文件, #include的文件为被包含的文件
+	Line     int    // 从1开始, 0表示没有位置信息或位于预处理器生成的行
+	Column   int    // 从1开始, 按字节计, 0表示驱动没有给出列
+	Severity string // 小写, 如:"error","warning"
+	Text     string
+	code     string // 出错行的源码
+}
+
+//Error 每条信息一行, 没有解析出信息时为原始日志
+func (e *ShaderError) Error() string {
+	var b strings.Builder
+	if e.Stage == 0 {
+		b.WriteString("link program")
+	} else {
+		b.WriteString("compile " + stageName(e.Stage) + " shader")
+		if e.File != "" {
+			b.WriteString(" " + e.File)
+		}
+	}
+	if len(e.Messages) == 0 {
+		b.WriteString(": " + strings.TrimSpace(e.Log))
+		return b.String()
+	}
+	for i, msg := range e.Messages {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("\n")
+		}
+		b.WriteString(msg.String())
+	}
+	return b.String()
+}
+
+//Snippet 每条信息后附上出错的源码行, 并用^标出驱动给出的列
+func (e *ShaderError) Snippet() string {
+	var b strings.Builder
+	for _, msg := range e.Messages {
+		b.WriteString(msg.String() + "\n")
+		if msg.Line == 0 {
+			continue
+		}
+		gutter := strconv.Itoa(msg.Line)
+		fmt.Fprintf(&b, " %s | %s\n", gutter, msg.code)
+		if msg.Column > 0 {
+			fmt.Fprintf(&b, " %s | %s^\n", strings.Repeat(" ", len(gutter)), caretIndent(msg.code, msg.Column))
+		}
+	}
+	if len(e.Messages) == 0 {
+		b.WriteString(strings.TrimSpace(e.Log) + "\n")
+	}
+	return b.String()
+}
+
+//String 如:"common.glsl:3:17: error: syntax error"
+func (msg ShaderMessage) String() string {
+	var pos string
+	if msg.Line > 0 {
+		pos = msg.File
+		if pos == "" {
+			pos = "shader"
+		}
+		pos += ":" + strconv.Itoa(msg.Line)
+		if msg.Column > 0 {
+			pos += ":" + strconv.Itoa(msg.Column)
+		}
+		pos += ": "
+	}
+	if msg.Severity == "" {
+		return pos + msg.Text
+	}
+	return pos + msg.Severity + ": " + msg.Text
+}
+
+//caretIndent 生成与源码第column列对齐的缩进, 保留制表符
+//驱动给出的列是字节偏移, 多字节字符(只会出现在注释中)按一个字符宽度缩进
+func caretIndent(code string, column int) string {
+	indent := []rune{}
+	for i, r := range code {
+		if i >= column-1 {
+			break
+		}
+		if r == '\t' {
+			indent = append(indent, '\t')
+		} else {
+			indent = append(indent, ' ')
+		}
+	}
+	return string(indent)
+}
+
+func stageName(stage uint32) string {
+	switch stage {
+	case gl.VERTEX_SHADER:
+		return "vertex"
+	case gl.FRAGMENT_SHADER:
+		return "fragment"
+	case gl.GEOMETRY_SHADER:
+		return "geometry"
+	case gl.TESS_CONTROL_SHADER:
+		return "tessellation control"
+	case gl.TESS_EVALUATION_SHADER:
+		return "tessellation evaluation"
+	}
+	return "unknown"
+}
+
+// 各驱动的日志格式, 整个源码作为第0个源字符串传入
+var (
+	// Mesa: 0:12(5): error: syntax error
+	mesaMessage = regexp.MustCompile(`^\d+:(\d+)\((\d+)\):\s*([A-Za-z ]+?)\s*:\s*(.*)$`)
+	// NVIDIA: 0(12) : error C0000: syntax error
+	nvidiaMessage = regexp.MustCompile(`^\d+\((\d+)\)\s*:\s*([A-Za-z ]+?)\s*(?:[A-Z]\d+)?\s*:\s*(.*)$`)
+	// AMD/Intel/Apple: ERROR: 0:12: 'x' : syntax error
+	prefixMessage = regexp.MustCompile(`^([A-Za-z]+):\s*\d+:(\d+):\s*(.*)$`)
+	// 没有位置的信息, 如链接错误 error: ...
+	plainMessage = regexp.MustCompile(`^(?i)(error|warning|info)\s*:\s*(.*)$`)
+)
+
+//parseShaderLog 逐行解析驱动日志, src不为nil时把行号映射回原文件
+func parseShaderLog(log string, src *Source) []ShaderMessage {
+	var msgs []ShaderMessage
+	for _, line := range strings.Split(log, "\n") {
+		line = strings.TrimSpace(strings.TrimRight(line, "\x00"))
+		if line == "" {
+			continue
+		}
+		var msg ShaderMessage
+		var outLine int
+		if m := mesaMessage.FindStringSubmatch(line); m != nil {
+			outLine, _ = strconv.Atoi(m[1])
+			msg.Column, _ = strconv.Atoi(m[2])
+			msg.Severity, msg.Text = m[3], m[4]
+		} else if m := nvidiaMessage.FindStringSubmatch(line); m != nil {
+			outLine, _ = strconv.Atoi(m[1])
+			msg.Severity, msg.Text = m[2], m[3]
+		} else if m := prefixMessage.FindStringSubmatch(line); m != nil {
+			outLine, _ = strconv.Atoi(m[2])
+			msg.Severity, msg.Text = m[1], m[3]
+		} else if m := plainMessage.FindStringSubmatch(line); m != nil {
+			msg.Severity, msg.Text = m[1], m[2]
+		} else {
+			msg.Text = line
+		}
+		msg.Severity = strings.ToLower(msg.Severity)
+		if src != nil {
+			if file, origLine, ok := src.Origin(outLine); ok {
+				msg.File, msg.Line, msg.code = file, origLine, src.text[outLine-1]
+			}
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
@@ -0,0 +1,125 @@
+package gfx
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+//TestParseShaderLog 每种驱动日志格式一个例子, 不映射行号
+func TestParseShaderLog(t *testing.T) {
+	tests := []struct {
+		driver, log string
+		want        ShaderMessage
+	}{
+		{"mesa", "0:12(5): error: syntax error, unexpected IDENTIFIER",
+			ShaderMessage{Severity: "error", Column: 5, Text: "syntax error, unexpected IDENTIFIER"}},
+		{"mesa warning", "0:3(10): warning: `x' used uninitialized",
+			ShaderMessage{Severity: "warning", Column: 10, Text: "`x' used uninitialized"}},
+		{"nvidia", `0(12) : error C1008: undefined variable "x"`,
+			ShaderMessage{Severity: "error", Text: `undefined variable "x"`}},
+		{"nvidia without code", "0(7) : warning : implicit cast",
+			ShaderMessage{Severity: "warning", Text: "implicit cast"}},
+		{"prefixed", "ERROR: 0:12: 'x' : undeclared identifier",
+			ShaderMessage{Severity: "error", Text: "'x' : undeclared identifier"}},
+		{"prefixed warning", "WARNING: 0:4: extension not supported",
+			ShaderMessage{Severity: "warning", Text: "extension not supported"}},
+		{"plain", "error: vertex shader output 'color' not read by fragment shader\x00",
+			ShaderMessage{Severity: "error", Text: "vertex shader output 'color' not read by fragment shader"}},
+		{"unknown", "Vertex info",
+			ShaderMessage{Text: "Vertex info"}},
+	}
+	for _, tt := range tests {
+		msgs := parseShaderLog(tt.log, nil)
+		if len(msgs) != 1 || !reflect.DeepEqual(msgs[0], tt.want) {
+			t.Errorf("%s: parsed %+v, want %+v", tt.driver, msgs, tt.want)
+		}
+	}
+
+	if msgs := parseShaderLog("\n  \n\x00", nil); len(msgs) != 0 {
+		t.Errorf("empty log: %+v", msgs)
+	}
+}
+
+//TestShaderLogOrigin 日志中的行号经预处理映射回被包含的文件, 预处理器生成的行没有位置
+func TestShaderLogOrigin(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.frag":  {Data: []byte("#version 330 core\n#include \"light.glsl\"\nvoid main() {}\n")},
+		"light.glsl": {Data: []byte("// 光照\n\tvec3 l = normalize(dir);\n")},
+	}
+	src, err := PreprocessFS(fsys, "main.frag", Define{Name: "SHADOWS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 输出: 1 #version, 2 #define, 3 // 光照, 4 vec3 l = ..., 5 void main
+	log := "0:4(20): error: `dir' undeclared\n" +
+		"0:2(1): warning: macro redefined\n" +
+		"0:5(1): error: bad main\n" +
+		"0:9(1): error: past the end\n"
+	got := parseShaderLog(log, src)
+	want := []ShaderMessage{
+		{File: "light.glsl", Line: 2, Column: 20, Severity: "error", Text: "`dir' undeclared", code: "\tvec3 l = normalize(dir);"},
+		{Column: 1, Severity: "warning", Text: "macro redefined"},
+		{File: "main.frag", Line: 3, Column: 1, Severity: "error", Text: "bad main", code: "void main() {}"},
+		{Column: 1, Severity: "error", Text: "past the end"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parsed %+v\nwant %+v", got, want)
+	}
+
+	e := &ShaderError{Stage: gl.FRAGMENT_SHADER, File: "main.frag", Log: log, Messages: got[:1]}
+	if s := e.Error(); s != "compile fragment shader main.frag: light.glsl:2:20: error: `dir' undeclared" {
+		t.Errorf("Error() = %q", s)
+	}
+	wantSnippet := "light.glsl:2:20: error: `dir' undeclared\n" +
+		" 2 | \tvec3 l = normalize(dir);\n" +
+		"   | \t                  ^\n"
+	if s := e.Snippet(); s != wantSnippet {
+		t.Errorf("Snippet() =\n%s\nwant\n%s", s, wantSnippet)
+	}
+}
+
+func TestShaderErrorString(t *testing.T) {
+	tests := []struct {
+		err  *ShaderError
+		want string
+	}{
+		{&ShaderError{Log: "error: no main\n", Messages: []ShaderMessage{{Severity: "error", Text: "no main"}}},
+			"link program: error: no main"},
+		{&ShaderError{Stage: gl.VERTEX_SHADER, Log: " driver crashed \n"},
+			"compile vertex shader: driver crashed"},
+		{&ShaderError{Stage: gl.VERTEX_SHADER, Messages: []ShaderMessage{
+			{Line: 3, Severity: "error", Text: "a"},
+			{File: "b.glsl", Line: 1, Column: 2, Severity: "warning", Text: "b"},
+		}}, "compile vertex shader: shader:3: error: a\nb.glsl:1:2: warning: b"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+//TestCaretIndent 列按字节计, 制表符保留, 其余字符(包括多字节字符)各缩进一格
+func TestCaretIndent(t *testing.T) {
+	tests := []struct {
+		code   string
+		column int
+		want   string
+	}{
+		{"float x = y;", 1, ""},
+		{"float x = y;", 11, "          "},
+		{"\t\tx = y;", 3, "\t\t"},
+		{"\tx = y; // 注释", 5, "\t   "},
+		// "注"占3个字节, x是第11个字节
+		{"/* 注 */ x = 1;", 11, "        "},
+		{"x", 10, " "},
+	}
+	for _, tt := range tests {
+		if got := caretIndent(tt.code, tt.column); got != tt.want {
+			t.Errorf("caretIndent(%q, %d) = %q, want %q", tt.code, tt.column, got, tt.want)
+		}
+	}
+}
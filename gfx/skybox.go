@@ -0,0 +1,110 @@
+/*
+天空盒
+以相机为中心绘制立方体贴图, 视图矩阵去掉平移后天空不随相机移动,
+顶点深度固定为1.0(gl_Position = pos.xyww), 在所有物体之后(或之前)绘制都位于最远处
+*/
+
+package gfx
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx/texture"
+)
+
+var skyboxVertexShader = `
+#version 330 core
+layout (location = 0) in vec3 aPos;
+out vec3 TexCoords;
+uniform mat4 view;
+uniform mat4 projection;
+void main() {
+	TexCoords = aPos;
+	vec4 pos = projection * view * vec4(aPos, 1.0);
+	gl_Position = pos.xyww;
+}
+`
+
+var skyboxFragmentShader = `
+#version 330 core
+in vec3 TexCoords;
+out vec4 FragColor;
+uniform samplerCube skybox;
+void main() {
+	FragColor = texture(skybox, TexCoords);
+}
+`
+
+// 边长为2的立方体, 从内部看为逆时针
+var skyboxVertices = []float32{
+	-1, -1, -1, 1, -1, -1, 1, 1, -1, -1, 1, -1,
+	-1, -1, 1, 1, -1, 1, 1, 1, 1, -1, 1, 1,
+}
+
+var skyboxIndices = []uint32{
+	0, 1, 2, 2, 3, 0, // -Z
+	5, 4, 7, 7, 6, 5, // +Z
+	4, 0, 3, 3, 7, 4, // -X
+	1, 5, 6, 6, 2, 1, // +X
+	3, 2, 6, 6, 7, 3, // +Y
+	4, 5, 1, 1, 0, 4, // -Y
+}
+
+//Skybox 天空盒渲染器
+type Skybox struct {
+	prog    *Program
+	cube    *Mesh
+	cubemap *texture.Texture
+}
+
+//NewSkybox 用立方体贴图创建天空盒, 立方体贴图见texture.NewCubemapFromFiles
+func NewSkybox(cubemap *texture.Texture) (*Skybox, error) {
+	vertShader, err := NewShader(skyboxVertexShader, gl.VERTEX_SHADER)
+	if err != nil {
+		return nil, err
+	}
+	fragShader, err := NewShader(skyboxFragmentShader, gl.FRAGMENT_SHADER)
+	if err != nil {
+		vertShader.Delete()
+		return nil, err
+	}
+	prog, err := NewProgram(vertShader, fragShader)
+	if err != nil {
+		return nil, err
+	}
+	cube, err := NewMesh(gl.TRIANGLES, NewVertexLayout(FloatAttrib("aPos", 3)), skyboxVertices, skyboxIndices)
+	if err != nil {
+		prog.Delete()
+		return nil, err
+	}
+	return &Skybox{prog: prog, cube: cube, cubemap: cubemap}, nil
+}
+
+//Draw 绘制天空盒, view 为相机的视图矩阵(平移部分会被去掉)
+//绘制期间深度比较改为gl.LEQUAL并关闭深度写入, 结束后恢复
+func (sky *Skybox) Draw(view, projection mgl32.Mat4) {
+	var depthFunc int32
+	var depthMask bool
+	gl.GetIntegerv(gl.DEPTH_FUNC, &depthFunc)
+	gl.GetBooleanv(gl.DEPTH_WRITEMASK, &depthMask)
+	gl.DepthFunc(gl.LEQUAL)
+	gl.DepthMask(false)
+
+	sky.prog.Use()
+	sky.prog.SetMat4("view", view.Mat3().Mat4())
+	sky.prog.SetMat4("projection", projection)
+	sky.cubemap.Bind(gl.TEXTURE0)
+	sky.cubemap.SetUniform(sky.prog.GetUniformLocation("skybox"))
+	sky.cube.Draw()
+	sky.cubemap.UnBind()
+
+	gl.DepthMask(depthMask)
+	gl.DepthFunc(uint32(depthFunc))
+}
+
+//Delete 删除着色器程序和网格, 立方体贴图由调用者删除
+func (sky *Skybox) Delete() {
+	sky.prog.Delete()
+	sky.cube.Delete()
+}
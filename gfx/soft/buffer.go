@@ -0,0 +1,206 @@
+package soft
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//buffer 缓冲对象,与GL一样以字节保存数据
+type buffer struct {
+	data []byte
+}
+
+//MaxVertexAttribs 每个VAO支持的顶点属性个数,与GL规定的GL_MAX_VERTEX_ATTRIBS最小值相同
+const MaxVertexAttribs = 16
+
+//vertexAttrib 顶点属性指针,stride与offset以字节为单位
+type vertexAttrib struct {
+	enabled bool
+	size    int
+	stride  int
+	offset  int
+	buffer  uint32
+}
+
+//vertexArray 顶点数组对象,记录顶点属性与绑定的索引缓冲
+type vertexArray struct {
+	attribs       []vertexAttrib
+	elementBuffer uint32
+}
+
+//GenVertexArray 生成顶点数组对象(VAO)
+func (ctx *Context) GenVertexArray() uint32 {
+	ctx.nextHandle++
+	ctx.vertexArray[ctx.nextHandle] = &vertexArray{}
+	return ctx.nextHandle
+}
+
+//DeleteVertexArray 删除顶点数组对象
+func (ctx *Context) DeleteVertexArray(handle uint32) {
+	if ctx.boundVAO == handle {
+		ctx.boundVAO = 0
+	}
+	delete(ctx.vertexArray, handle)
+}
+
+//BindVertexArray 绑定顶点数组对象,0表示解绑
+func (ctx *Context) BindVertexArray(handle uint32) {
+	if _, ok := ctx.vertexArray[handle]; handle != 0 && !ok {
+		ctx.setError(errInvalidOperation)
+		return
+	}
+	ctx.boundVAO = handle
+}
+
+//GenBuffer 生成缓冲对象(VBO/EBO)
+func (ctx *Context) GenBuffer() uint32 {
+	ctx.nextHandle++
+	ctx.buffers[ctx.nextHandle] = &buffer{}
+	return ctx.nextHandle
+}
+
+//DeleteBuffer 删除缓冲对象
+func (ctx *Context) DeleteBuffer(handle uint32) {
+	if ctx.arrayBuffer == handle {
+		ctx.arrayBuffer = 0
+	}
+	delete(ctx.buffers, handle)
+}
+
+//BindBuffer 绑定缓冲对象到ARRAY_BUFFER或ELEMENT_ARRAY_BUFFER
+//与GL一样,ELEMENT_ARRAY_BUFFER的绑定记录在当前VAO中
+func (ctx *Context) BindBuffer(target, handle uint32) {
+	if _, ok := ctx.buffers[handle]; handle != 0 && !ok {
+		ctx.setError(errInvalidOperation)
+		return
+	}
+	switch target {
+	case ARRAY_BUFFER:
+		ctx.arrayBuffer = handle
+	case ELEMENT_ARRAY_BUFFER:
+		vao := ctx.vertexArray[ctx.boundVAO]
+		if vao == nil {
+			ctx.setError(errInvalidOperation)
+			return
+		}
+		vao.elementBuffer = handle
+	default:
+		ctx.setError(errInvalidEnum)
+	}
+}
+
+//BufferData 将数据复制到target上绑定的缓冲中
+//data 支持[]float32,[]uint32,[]byte
+func (ctx *Context) BufferData(target uint32, data interface{}) {
+	var handle uint32
+	switch target {
+	case ARRAY_BUFFER:
+		handle = ctx.arrayBuffer
+	case ELEMENT_ARRAY_BUFFER:
+		if vao := ctx.vertexArray[ctx.boundVAO]; vao != nil {
+			handle = vao.elementBuffer
+		}
+	default:
+		ctx.setError(errInvalidEnum)
+		return
+	}
+	buf := ctx.buffers[handle]
+	if buf == nil {
+		ctx.setError(errInvalidOperation)
+		return
+	}
+	switch v := data.(type) {
+	case []float32:
+		buf.data = make([]byte, len(v)*4)
+		for i, f := range v {
+			binary.LittleEndian.PutUint32(buf.data[i*4:], math.Float32bits(f))
+		}
+	case []uint32:
+		buf.data = make([]byte, len(v)*4)
+		for i, u := range v {
+			binary.LittleEndian.PutUint32(buf.data[i*4:], u)
+		}
+	case []byte:
+		buf.data = append([]byte(nil), v...)
+	default:
+		ctx.setError(errInvalidValue)
+	}
+}
+
+//VertexAttribPointer 设置当前VAO中index号属性的读取方式(仅支持float分量)
+//index 小于MaxVertexAttribs, size 分量个数(1~4), stride与offset以字节为单位,stride为0表示紧密排列
+func (ctx *Context) VertexAttribPointer(index uint32, size int32, stride, offset int) {
+	if index >= MaxVertexAttribs || size < 1 || size > 4 || stride < 0 || offset < 0 {
+		ctx.setError(errInvalidValue)
+		return
+	}
+	vao := ctx.vertexArray[ctx.boundVAO]
+	if vao == nil || ctx.arrayBuffer == 0 {
+		ctx.setError(errInvalidOperation)
+		return
+	}
+	if stride == 0 {
+		stride = int(size) * 4
+	}
+	attrib := vao.attrib(index)
+	attrib.size = int(size)
+	attrib.stride = stride
+	attrib.offset = offset
+	attrib.buffer = ctx.arrayBuffer
+}
+
+//EnableVertexAttribArray 启用当前VAO中index号顶点属性
+func (ctx *Context) EnableVertexAttribArray(index uint32) {
+	ctx.setAttribEnabled(index, true)
+}
+
+//DisableVertexAttribArray 禁用当前VAO中index号顶点属性
+func (ctx *Context) DisableVertexAttribArray(index uint32) {
+	ctx.setAttribEnabled(index, false)
+}
+
+func (ctx *Context) setAttribEnabled(index uint32, enabled bool) {
+	if index >= MaxVertexAttribs {
+		ctx.setError(errInvalidValue)
+		return
+	}
+	vao := ctx.vertexArray[ctx.boundVAO]
+	if vao == nil {
+		ctx.setError(errInvalidOperation)
+		return
+	}
+	vao.attrib(index).enabled = enabled
+}
+
+//attrib 返回index号属性,index由调用方保证小于MaxVertexAttribs
+func (vao *vertexArray) attrib(index uint32) *vertexAttrib {
+	for int(index) >= len(vao.attribs) {
+		vao.attribs = append(vao.attribs, vertexAttrib{})
+	}
+	return &vao.attribs[index]
+}
+
+//fetch 读取第vertex个顶点的全部属性,未提供的分量按GL规则补为(0,0,0,1)
+func (ctx *Context) fetch(vao *vertexArray, vertex int, in []mgl32.Vec4) bool {
+	for i := range in {
+		in[i] = mgl32.Vec4{0, 0, 0, 1}
+		if i >= len(vao.attribs) || !vao.attribs[i].enabled {
+			continue
+		}
+		a := vao.attribs[i]
+		buf := ctx.buffers[a.buffer]
+		if buf == nil {
+			return false
+		}
+		start := a.offset + vertex*a.stride
+		if start < 0 || start+a.size*4 > len(buf.data) {
+			return false
+		}
+		for c := 0; c < a.size; c++ {
+			in[i][c] = math.Float32frombits(binary.LittleEndian.Uint32(buf.data[start+c*4:]))
+		}
+	}
+	return true
+}
@@ -0,0 +1,305 @@
+package soft
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//clipVertex 裁剪空间中的顶点
+type clipVertex struct {
+	pos  mgl32.Vec4
+	vary []float32
+}
+
+//screenVertex 窗口坐标中的顶点(y轴向上,与GL一致)
+type screenVertex struct {
+	x, y float64
+	z    float64 // 深度,范围[0,1]
+	invW float64
+	vary []float32
+}
+
+//DrawArrays 按顺序使用first开始的count个顶点绘制图元,mode只支持TRIANGLES
+func (ctx *Context) DrawArrays(mode uint32, first, count int32) {
+	if first < 0 || count < 0 {
+		ctx.setError(errInvalidValue)
+		return
+	}
+	ctx.draw(mode, int(count), func(i int) (int, bool) {
+		return int(first) + i, true
+	})
+}
+
+//DrawElements 使用当前VAO的索引缓冲绘制图元,索引类型为uint32
+//mode只支持TRIANGLES, offset为索引缓冲中的字节偏移
+func (ctx *Context) DrawElements(mode uint32, count int32, offset int) {
+	if count < 0 || offset < 0 || offset%4 != 0 {
+		ctx.setError(errInvalidValue)
+		return
+	}
+	vao := ctx.vertexArray[ctx.boundVAO]
+	if vao == nil || ctx.buffers[vao.elementBuffer] == nil {
+		ctx.setError(errInvalidOperation)
+		return
+	}
+	indices := ctx.buffers[vao.elementBuffer].data
+	ctx.draw(mode, int(count), func(i int) (int, bool) {
+		at := offset + i*4
+		if at+4 > len(indices) {
+			return 0, false
+		}
+		return int(uint32(indices[at]) | uint32(indices[at+1])<<8 |
+			uint32(indices[at+2])<<16 | uint32(indices[at+3])<<24), true
+	})
+}
+
+func (ctx *Context) draw(mode uint32, count int, index func(int) (int, bool)) {
+	if mode != TRIANGLES {
+		ctx.setError(errInvalidEnum)
+		return
+	}
+	vao := ctx.vertexArray[ctx.boundVAO]
+	prog := ctx.program
+	if vao == nil || prog == nil || prog.Vertex == nil || prog.Fragment == nil {
+		ctx.setError(errInvalidOperation)
+		return
+	}
+	in := make([]mgl32.Vec4, len(vao.attribs))
+	var tri [3]clipVertex
+	for i := 0; i+3 <= count; i += 3 {
+		for k := 0; k < 3; k++ {
+			vertex, ok := index(i + k)
+			if !ok || !ctx.fetch(vao, vertex, in) {
+				ctx.setError(errInvalidOperation)
+				return
+			}
+			out := make([]float32, prog.Varyings)
+			tri[k] = clipVertex{pos: prog.Vertex(in, out), vary: out}
+		}
+		ctx.drawTriangle(prog, tri)
+	}
+}
+
+func (ctx *Context) drawTriangle(prog *Program, tri [3]clipVertex) {
+	poly := clipPolygon(tri[:])
+	if len(poly) < 3 {
+		return
+	}
+	verts := make([]screenVertex, len(poly))
+	for i, v := range poly {
+		verts[i] = ctx.toScreen(v)
+	}
+
+	// 面剔除依据裁剪后多边形在窗口坐标中的有向面积
+	area := polygonArea(verts)
+	if area == 0 {
+		return
+	}
+	if ctx.cullFace && ctx.culled(area) {
+		return
+	}
+
+	switch ctx.polygonMode {
+	case FILL:
+		for i := 1; i+1 < len(verts); i++ {
+			ctx.fillTriangle(prog, verts[0], verts[i], verts[i+1])
+		}
+	case LINE:
+		for i := range verts {
+			ctx.drawLine(prog, verts[i], verts[(i+1)%len(verts)])
+		}
+	case POINT:
+		for _, v := range verts {
+			ctx.shade(prog, int(math.Floor(v.x)), int(math.Floor(v.y)), v.z, v.vary)
+		}
+	}
+}
+
+func (ctx *Context) culled(area float64) bool {
+	front := (area > 0) == (ctx.frontFace == CCW)
+	switch ctx.cullMode {
+	case FRONT:
+		return front
+	case BACK:
+		return !front
+	}
+	return true
+}
+
+//clipPolygon 用近平面(z>=-w)和远平面(z<=w)裁剪多边形
+func clipPolygon(poly []clipVertex) []clipVertex {
+	poly = clipPlane(poly, func(p mgl32.Vec4) float32 { return p.Z() + p.W() })
+	return clipPlane(poly, func(p mgl32.Vec4) float32 { return p.W() - p.Z() })
+}
+
+func clipPlane(poly []clipVertex, dist func(mgl32.Vec4) float32) []clipVertex {
+	var out []clipVertex
+	for i := range poly {
+		a, b := poly[i], poly[(i+1)%len(poly)]
+		da, db := dist(a.pos), dist(b.pos)
+		if da >= 0 {
+			out = append(out, a)
+		}
+		if (da >= 0) != (db >= 0) {
+			t := da / (da - db)
+			out = append(out, lerpVertex(a, b, t))
+		}
+	}
+	return out
+}
+
+func lerpVertex(a, b clipVertex, t float32) clipVertex {
+	v := clipVertex{
+		pos:  a.pos.Add(b.pos.Sub(a.pos).Mul(t)),
+		vary: make([]float32, len(a.vary)),
+	}
+	for i := range v.vary {
+		v.vary[i] = a.vary[i] + (b.vary[i]-a.vary[i])*t
+	}
+	return v
+}
+
+//toScreen 透视除法与视口变换
+func (ctx *Context) toScreen(v clipVertex) screenVertex {
+	w := float64(v.pos.W())
+	vp := ctx.viewport
+	return screenVertex{
+		x:    float64(vp[0]) + (float64(v.pos.X())/w+1)*float64(vp[2])/2,
+		y:    float64(vp[1]) + (float64(v.pos.Y())/w+1)*float64(vp[3])/2,
+		z:    (float64(v.pos.Z())/w + 1) / 2,
+		invW: 1 / w,
+		vary: v.vary,
+	}
+}
+
+func signedArea(a, b, c screenVertex) float64 {
+	return (b.x-a.x)*(c.y-a.y) - (c.x-a.x)*(b.y-a.y)
+}
+
+func polygonArea(verts []screenVertex) float64 {
+	var area float64
+	for i := 1; i+1 < len(verts); i++ {
+		area += signedArea(verts[0], verts[i], verts[i+1])
+	}
+	return area
+}
+
+//edge 点(px,py)相对有向边a->b的边函数
+func edge(a, b screenVertex, px, py float64) float64 {
+	return (b.x-a.x)*(py-a.y) - (b.y-a.y)*(px-a.x)
+}
+
+//isTopLeft 逆时针三角形的上边或左边,用于像素恰好落在边上时的归属判断
+func isTopLeft(a, b screenVertex) bool {
+	return (a.y == b.y && b.x < a.x) || b.y < a.y
+}
+
+//fillTriangle 以像素中心采样光栅化三角形
+func (ctx *Context) fillTriangle(prog *Program, v0, v1, v2 screenVertex) {
+	area := signedArea(v0, v1, v2)
+	if area == 0 {
+		return
+	}
+	if area < 0 {
+		v1, v2 = v2, v1
+		area = -area
+	}
+	minX, maxX := ctx.clampX(math.Min(v0.x, math.Min(v1.x, v2.x)), math.Max(v0.x, math.Max(v1.x, v2.x)))
+	minY, maxY := ctx.clampY(math.Min(v0.y, math.Min(v1.y, v2.y)), math.Max(v0.y, math.Max(v1.y, v2.y)))
+
+	tl0, tl1, tl2 := isTopLeft(v1, v2), isTopLeft(v2, v0), isTopLeft(v0, v1)
+	vary := make([]float32, prog.Varyings)
+	for y := minY; y <= maxY; y++ {
+		py := float64(y) + 0.5
+		for x := minX; x <= maxX; x++ {
+			px := float64(x) + 0.5
+			w0 := edge(v1, v2, px, py)
+			w1 := edge(v2, v0, px, py)
+			w2 := edge(v0, v1, px, py)
+			if w0 < 0 || w1 < 0 || w2 < 0 ||
+				(w0 == 0 && !tl0) || (w1 == 0 && !tl1) || (w2 == 0 && !tl2) {
+				continue
+			}
+			l0, l1, l2 := w0/area, w1/area, w2/area
+			z := l0*v0.z + l1*v1.z + l2*v2.z
+			interpolate(vary, l0, l1, l2, v0, v1, v2)
+			ctx.shade(prog, x, y, z, vary)
+		}
+	}
+}
+
+//drawLine 线框模式下绘制一条边
+func (ctx *Context) drawLine(prog *Program, a, b screenVertex) {
+	dx, dy := b.x-a.x, b.y-a.y
+	steps := int(math.Ceil(math.Max(math.Abs(dx), math.Abs(dy))))
+	if steps == 0 {
+		steps = 1
+	}
+	vary := make([]float32, prog.Varyings)
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x := int(math.Floor(a.x + dx*t))
+		y := int(math.Floor(a.y + dy*t))
+		if x < ctx.viewport[0] || x >= ctx.viewport[0]+ctx.viewport[2] ||
+			y < ctx.viewport[1] || y >= ctx.viewport[1]+ctx.viewport[3] {
+			continue
+		}
+		z := a.z + (b.z-a.z)*t
+		interpolate(vary, 1-t, t, 0, a, b, b)
+		ctx.shade(prog, x, y, z, vary)
+	}
+}
+
+//interpolate 透视校正插值输出变量,l0,l1,l2为窗口坐标下的重心坐标
+func interpolate(out []float32, l0, l1, l2 float64, v0, v1, v2 screenVertex) {
+	p0, p1, p2 := l0*v0.invW, l1*v1.invW, l2*v2.invW
+	sum := p0 + p1 + p2
+	for i := range out {
+		out[i] = float32((p0*float64(v0.vary[i]) + p1*float64(v1.vary[i]) + p2*float64(v2.vary[i])) / sum)
+	}
+}
+
+//shade 深度测试(GL_LESS)并执行片段着色器写入颜色
+func (ctx *Context) shade(prog *Program, x, y int, z float64, vary []float32) {
+	if x < 0 || y < 0 || x >= ctx.width || y >= ctx.height {
+		return
+	}
+	// 帧缓冲以左下角为原点,image.RGBA以左上角为原点
+	row := ctx.height - 1 - y
+	di := row*ctx.width + x
+	if ctx.depthTest {
+		if float32(z) >= ctx.depth[di] {
+			return
+		}
+		ctx.depth[di] = float32(z)
+	}
+	c := prog.Fragment(vary)
+	ctx.color.SetRGBA(x, row, toRGBA(c[0], c[1], c[2], c[3]))
+}
+
+func (ctx *Context) clampX(lo, hi float64) (int, int) {
+	return clampRange(lo, hi, ctx.viewport[0], ctx.viewport[0]+ctx.viewport[2], ctx.width)
+}
+
+func (ctx *Context) clampY(lo, hi float64) (int, int) {
+	return clampRange(lo, hi, ctx.viewport[1], ctx.viewport[1]+ctx.viewport[3], ctx.height)
+}
+
+func clampRange(lo, hi float64, vpMin, vpMax, size int) (int, int) {
+	min := int(math.Floor(lo))
+	max := int(math.Ceil(hi))
+	if min < vpMin {
+		min = vpMin
+	}
+	if min < 0 {
+		min = 0
+	}
+	if max > vpMax-1 {
+		max = vpMax - 1
+	}
+	if max > size-1 {
+		max = size - 1
+	}
+	return min, max
+}
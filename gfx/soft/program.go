@@ -0,0 +1,17 @@
+package soft
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//Program 软件着色器程序,以Go函数代替GLSL着色器
+//uniform 由函数闭包捕获的变量充当
+type Program struct {
+	//Varyings 顶点着色器传给片段着色器的float分量个数
+	Varyings int
+	//Vertex 顶点着色器: in[i]为location=i的顶点属性,
+	//输出变量写入out,返回值为gl_Position
+	Vertex func(in []mgl32.Vec4, out []float32) mgl32.Vec4
+	//Fragment 片段着色器: in为经过透视校正插值的输出变量,返回值为FragColor
+	Fragment func(in []float32) mgl32.Vec4
+}
@@ -0,0 +1,224 @@
+/*
+软件光栅化渲染后端
+不依赖GPU与OpenGL上下文，实现例程中用到的OpenGL子集:
+顶点/索引缓冲、DrawArrays/DrawElements三角形绘制、
+深度测试、背面剔除以及线框模式(PolygonMode)
+渲染结果输出为image.RGBA
+*/
+
+package soft
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+)
+
+// 与OpenGL同值的常量，方便与gl包的调用一一对应
+const (
+	DEPTH_BUFFER_BIT = 0x00000100
+	COLOR_BUFFER_BIT = 0x00004000
+
+	TRIANGLES = 0x0004
+
+	CULL_FACE  = 0x0B44
+	DEPTH_TEST = 0x0B71
+
+	FRONT          = 0x0404
+	BACK           = 0x0405
+	FRONT_AND_BACK = 0x0408
+
+	CW  = 0x0900
+	CCW = 0x0901
+
+	POINT = 0x1B00
+	LINE  = 0x1B01
+	FILL  = 0x1B02
+
+	ARRAY_BUFFER         = 0x8892
+	ELEMENT_ARRAY_BUFFER = 0x8893
+)
+
+var (
+	errInvalidEnum      = errors.New("soft: invalid enum")
+	errInvalidValue     = errors.New("soft: invalid value")
+	errInvalidOperation = errors.New("soft: invalid operation")
+)
+
+//Context 软件渲染上下文，对应一个带深度缓冲的离屏帧缓冲
+type Context struct {
+	width  int
+	height int
+	color  *image.RGBA
+	depth  []float32
+
+	clearColor  [4]float32
+	viewport    [4]int
+	depthTest   bool
+	cullFace    bool
+	cullMode    uint32
+	frontFace   uint32
+	polygonMode uint32
+
+	nextHandle  uint32
+	buffers     map[uint32]*buffer
+	vertexArray map[uint32]*vertexArray
+	arrayBuffer uint32
+	boundVAO    uint32
+	program     *Program
+
+	err error
+}
+
+//NewContext 创建宽width高height的软件渲染上下文
+func NewContext(width, height int) *Context {
+	ctx := &Context{
+		width:       width,
+		height:      height,
+		color:       image.NewRGBA(image.Rect(0, 0, width, height)),
+		depth:       make([]float32, width*height),
+		viewport:    [4]int{0, 0, width, height},
+		cullMode:    BACK,
+		frontFace:   CCW,
+		polygonMode: FILL,
+		buffers:     make(map[uint32]*buffer),
+		vertexArray: make(map[uint32]*vertexArray),
+	}
+	for i := range ctx.depth {
+		ctx.depth[i] = 1
+	}
+	return ctx
+}
+
+//Image 返回渲染结果(第0行为图像顶部)
+func (ctx *Context) Image() *image.RGBA {
+	return ctx.color
+}
+
+//Err 返回第一个非法调用产生的错误，对应gl.GetError
+func (ctx *Context) Err() error {
+	return ctx.err
+}
+
+func (ctx *Context) setError(err error) {
+	if ctx.err == nil {
+		ctx.err = err
+	}
+}
+
+//Viewport 指定视口(左下角为原点)
+func (ctx *Context) Viewport(x, y, width, height int32) {
+	if width < 0 || height < 0 {
+		ctx.setError(errInvalidValue)
+		return
+	}
+	ctx.viewport = [4]int{int(x), int(y), int(width), int(height)}
+}
+
+//ClearColor 设置清屏颜色
+func (ctx *Context) ClearColor(red, green, blue, alpha float32) {
+	ctx.clearColor = [4]float32{clamp01(red), clamp01(green), clamp01(blue), clamp01(alpha)}
+}
+
+//Clear 清空颜色缓冲和(或)深度缓冲
+func (ctx *Context) Clear(mask uint32) {
+	if mask&^(COLOR_BUFFER_BIT|DEPTH_BUFFER_BIT) != 0 {
+		ctx.setError(errInvalidValue)
+		return
+	}
+	if mask&COLOR_BUFFER_BIT != 0 {
+		c := toRGBA(ctx.clearColor[0], ctx.clearColor[1], ctx.clearColor[2], ctx.clearColor[3])
+		pix := ctx.color.Pix
+		for i := 0; i < len(pix); i += 4 {
+			pix[i+0] = c.R
+			pix[i+1] = c.G
+			pix[i+2] = c.B
+			pix[i+3] = c.A
+		}
+	}
+	if mask&DEPTH_BUFFER_BIT != 0 {
+		for i := range ctx.depth {
+			ctx.depth[i] = 1
+		}
+	}
+}
+
+//Enable 开启功能,支持DEPTH_TEST和CULL_FACE
+func (ctx *Context) Enable(capability uint32) {
+	ctx.setCapability(capability, true)
+}
+
+//Disable 关闭功能
+func (ctx *Context) Disable(capability uint32) {
+	ctx.setCapability(capability, false)
+}
+
+func (ctx *Context) setCapability(capability uint32, enabled bool) {
+	switch capability {
+	case DEPTH_TEST:
+		ctx.depthTest = enabled
+	case CULL_FACE:
+		ctx.cullFace = enabled
+	default:
+		ctx.setError(errInvalidEnum)
+	}
+}
+
+//CullFace 指定剔除的面: FRONT, BACK 或 FRONT_AND_BACK
+func (ctx *Context) CullFace(mode uint32) {
+	switch mode {
+	case FRONT, BACK, FRONT_AND_BACK:
+		ctx.cullMode = mode
+	default:
+		ctx.setError(errInvalidEnum)
+	}
+}
+
+//FrontFace 指定正面的环绕方向: CW 或 CCW
+func (ctx *Context) FrontFace(mode uint32) {
+	switch mode {
+	case CW, CCW:
+		ctx.frontFace = mode
+	default:
+		ctx.setError(errInvalidEnum)
+	}
+}
+
+//PolygonMode 指定多边形光栅化模式,face只支持FRONT_AND_BACK(与core profile一致)
+func (ctx *Context) PolygonMode(face, mode uint32) {
+	if face != FRONT_AND_BACK {
+		ctx.setError(errInvalidEnum)
+		return
+	}
+	switch mode {
+	case POINT, LINE, FILL:
+		ctx.polygonMode = mode
+	default:
+		ctx.setError(errInvalidEnum)
+	}
+}
+
+//UseProgram 使用着色器程序,传入nil表示不使用任何程序
+func (ctx *Context) UseProgram(prog *Program) {
+	ctx.program = prog
+}
+
+func clamp01(v float32) float32 {
+	if v < 0 || v != v {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+func toRGBA(r, g, b, a float32) color.RGBA {
+	return color.RGBA{
+		R: uint8(math.Floor(float64(clamp01(r))*255 + 0.5)),
+		G: uint8(math.Floor(float64(clamp01(g))*255 + 0.5)),
+		B: uint8(math.Floor(float64(clamp01(b))*255 + 0.5)),
+		A: uint8(math.Floor(float64(clamp01(a))*255 + 0.5)),
+	}
+}
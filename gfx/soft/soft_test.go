@@ -0,0 +1,282 @@
+package soft
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// 测试用帧缓冲为8x8, 窗口坐标与像素一一对应
+const size = 8
+
+var (
+	red   = mgl32.Vec4{1, 0, 0, 1}
+	green = mgl32.Vec4{0, 1, 0, 1}
+)
+
+//pixel 窗口坐标(x, y)对应的裁剪空间坐标, w为1
+func pixel(x, y float32) mgl32.Vec4 {
+	return mgl32.Vec4{x/size*2 - 1, y/size*2 - 1, 0, 1}
+}
+
+//solid 原样输出裁剪空间坐标、片段为单一颜色的着色器
+func solid(c mgl32.Vec4) *Program {
+	return &Program{
+		Vertex:   func(in []mgl32.Vec4, out []float32) mgl32.Vec4 { return in[0] },
+		Fragment: func(in []float32) mgl32.Vec4 { return c },
+	}
+}
+
+//drawArrays 以属性0为裁剪空间坐标绘制三角形
+func drawArrays(t *testing.T, ctx *Context, prog *Program, positions ...mgl32.Vec4) {
+	t.Helper()
+	var data []float32
+	for _, p := range positions {
+		data = append(data, p[:]...)
+	}
+	ctx.BindVertexArray(ctx.GenVertexArray())
+	ctx.BindBuffer(ARRAY_BUFFER, ctx.GenBuffer())
+	ctx.BufferData(ARRAY_BUFFER, data)
+	ctx.VertexAttribPointer(0, 4, 0, 0)
+	ctx.EnableVertexAttribArray(0)
+	ctx.UseProgram(prog)
+	ctx.DrawArrays(TRIANGLES, 0, int32(len(positions)))
+	if err := ctx.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+//coverage 绘制三角形并返回每个像素被着色的次数, 下标为[y][x]
+//片段的窗口坐标由透视校正插值后的裁剪空间坐标x, y, w还原
+func coverage(t *testing.T, ctx *Context, positions ...mgl32.Vec4) [size][size]int {
+	t.Helper()
+	var counts [size][size]int
+	prog := &Program{
+		Varyings: 3,
+		Vertex: func(in []mgl32.Vec4, out []float32) mgl32.Vec4 {
+			out[0], out[1], out[2] = in[0].X(), in[0].Y(), in[0].W()
+			return in[0]
+		},
+		Fragment: func(in []float32) mgl32.Vec4 {
+			x := (in[0]/in[2] + 1) * size / 2
+			y := (in[1]/in[2] + 1) * size / 2
+			counts[int(y)][int(x)]++
+			return red
+		},
+	}
+	drawArrays(t, ctx, prog, positions...)
+	return counts
+}
+
+//TestFillRule 以像素中心采样, 恰好落在公共边上的像素只属于其中一个三角形(上边和左边规则)
+func TestFillRule(t *testing.T) {
+	full := func(x, y int) int { return 1 }
+	tests := []struct {
+		name      string
+		positions []mgl32.Vec4
+		want      func(x, y int) int
+	}{
+		{"shared diagonal", []mgl32.Vec4{
+			pixel(0, 0), pixel(8, 0), pixel(8, 8),
+			pixel(0, 0), pixel(8, 8), pixel(0, 8),
+		}, full},
+		{"clockwise", []mgl32.Vec4{
+			pixel(0, 0), pixel(8, 8), pixel(8, 0),
+			pixel(0, 0), pixel(0, 8), pixel(8, 8),
+		}, full},
+		// 公共边穿过一列像素中心
+		{"vertical edge through centers", []mgl32.Vec4{
+			pixel(0, 0), pixel(4.5, 0), pixel(4.5, 8),
+			pixel(0, 0), pixel(4.5, 8), pixel(0, 8),
+			pixel(4.5, 0), pixel(8, 0), pixel(8, 8),
+			pixel(4.5, 0), pixel(8, 8), pixel(4.5, 8),
+		}, full},
+		{"horizontal edge through centers", []mgl32.Vec4{
+			pixel(0, 0), pixel(8, 0), pixel(8, 3.5),
+			pixel(0, 0), pixel(8, 3.5), pixel(0, 3.5),
+			pixel(0, 3.5), pixel(8, 3.5), pixel(8, 8),
+			pixel(0, 3.5), pixel(8, 8), pixel(0, 8),
+		}, full},
+		// 四个三角形的公共顶点恰好是像素中心
+		{"fan around a center", []mgl32.Vec4{
+			pixel(0, 0), pixel(8, 0), pixel(4.5, 4.5),
+			pixel(8, 0), pixel(8, 8), pixel(4.5, 4.5),
+			pixel(8, 8), pixel(0, 8), pixel(4.5, 4.5),
+			pixel(0, 8), pixel(0, 0), pixel(4.5, 4.5),
+		}, full},
+		// 斜边x+y=4穿过x+y=3的像素中心, 斜边是右边, 这些像素不属于三角形
+		{"right edge excluded", []mgl32.Vec4{
+			pixel(0, 0), pixel(4, 0), pixel(0, 4),
+		}, func(x, y int) int {
+			if x+y <= 2 {
+				return 1
+			}
+			return 0
+		}},
+		{"outside the viewport", []mgl32.Vec4{
+			pixel(-8, -8), pixel(-1, -8), pixel(-8, -1),
+		}, func(x, y int) int { return 0 }},
+	}
+	for _, tt := range tests {
+		counts := coverage(t, NewContext(size, size), tt.positions...)
+		for y := range counts {
+			for x, n := range counts[y] {
+				if want := tt.want(x, y); n != want {
+					t.Errorf("%s: pixel (%d, %d) shaded %d times, want %d", tt.name, x, y, n, want)
+				}
+			}
+		}
+	}
+}
+
+func TestCulling(t *testing.T) {
+	ccw := []mgl32.Vec4{pixel(0, 0), pixel(8, 0), pixel(0, 8)}
+	cw := []mgl32.Vec4{pixel(0, 0), pixel(0, 8), pixel(8, 0)}
+	tests := []struct {
+		frontFace, cullMode uint32
+		ccwDrawn, cwDrawn   bool
+	}{
+		{CCW, BACK, true, false},
+		{CCW, FRONT, false, true},
+		{CW, BACK, false, true},
+		{CCW, FRONT_AND_BACK, false, false},
+	}
+	drawn := func(frontFace, cullMode uint32, positions []mgl32.Vec4) bool {
+		ctx := NewContext(size, size)
+		ctx.Enable(CULL_FACE)
+		ctx.FrontFace(frontFace)
+		ctx.CullFace(cullMode)
+		counts := coverage(t, ctx, positions...)
+		return counts[0][0] != 0
+	}
+	for _, tt := range tests {
+		if got := drawn(tt.frontFace, tt.cullMode, ccw); got != tt.ccwDrawn {
+			t.Errorf("front %#x cull %#x: counter-clockwise drawn %v, want %v", tt.frontFace, tt.cullMode, got, tt.ccwDrawn)
+		}
+		if got := drawn(tt.frontFace, tt.cullMode, cw); got != tt.cwDrawn {
+			t.Errorf("front %#x cull %#x: clockwise drawn %v, want %v", tt.frontFace, tt.cullMode, got, tt.cwDrawn)
+		}
+	}
+}
+
+//TestDepth 深度测试为GL_LESS: 开启时较近的片段保留, 与绘制顺序无关; 关闭时后绘制的覆盖先绘制的
+func TestDepth(t *testing.T) {
+	quad := func(z float32) []mgl32.Vec4 {
+		var positions []mgl32.Vec4
+		for _, p := range []mgl32.Vec4{pixel(0, 0), pixel(8, 0), pixel(8, 8), pixel(0, 0), pixel(8, 8), pixel(0, 8)} {
+			p[2] = z
+			positions = append(positions, p)
+		}
+		return positions
+	}
+	type layer struct {
+		z     float32
+		color mgl32.Vec4
+	}
+	tests := []struct {
+		name          string
+		depthTest     bool
+		first, second layer
+		want          color.RGBA
+	}{
+		{"near drawn last", true, layer{0.5, red}, layer{-0.5, green}, color.RGBA{0, 255, 0, 255}},
+		{"near drawn first", true, layer{-0.5, green}, layer{0.5, red}, color.RGBA{0, 255, 0, 255}},
+		{"equal depth keeps the first", true, layer{0, green}, layer{0, red}, color.RGBA{0, 255, 0, 255}},
+		{"disabled", false, layer{-0.5, green}, layer{0.5, red}, color.RGBA{255, 0, 0, 255}},
+	}
+	for _, tt := range tests {
+		ctx := NewContext(size, size)
+		if tt.depthTest {
+			ctx.Enable(DEPTH_TEST)
+		}
+		drawArrays(t, ctx, solid(tt.first.color), quad(tt.first.z)...)
+		drawArrays(t, ctx, solid(tt.second.color), quad(tt.second.z)...)
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				if got := ctx.Image().RGBAAt(x, y); got != tt.want {
+					t.Fatalf("%s: pixel (%d, %d) = %v, want %v", tt.name, x, y, got, tt.want)
+				}
+			}
+		}
+	}
+}
+
+//TestClipping 近平面和远平面之外的部分被裁掉, 裁剪后写入的深度都在[0, 1]内
+func TestClipping(t *testing.T) {
+	projection := mgl32.Perspective(mgl32.DegToRad(90), 1, 1, 100)
+	eye := func(x, y, z float32) mgl32.Vec4 {
+		return projection.Mul4x1(mgl32.Vec4{x, y, z, 1})
+	}
+	// z从左边的-3线性变化到右边的1, 近平面z=-w=-1恰好在屏幕中间
+	crossing := []mgl32.Vec4{
+		{-1, -1, -3, 1}, {1, -1, 1, 1}, {1, 1, 1, 1},
+		{-1, -1, -3, 1}, {1, 1, 1, 1}, {-1, 1, -3, 1},
+	}
+	tests := []struct {
+		name      string
+		positions []mgl32.Vec4
+		want      func(x, y int) bool
+	}{
+		{"crossing the near plane", crossing, func(x, y int) bool { return x >= 4 }},
+		{"before the near plane", []mgl32.Vec4{
+			{-1, -1, -2, 1}, {1, -1, -2, 1}, {0, 1, -2, 1},
+		}, func(x, y int) bool { return false }},
+		{"beyond the far plane", []mgl32.Vec4{
+			{-1, -1, 2, 1}, {1, -1, 2, 1}, {0, 1, 2, 1},
+		}, func(x, y int) bool { return false }},
+		// 地面上的三角形有一个顶点在摄像机背后(w<0), 不裁剪时这个顶点会翻到屏幕上半部分
+		// 裁剪后为窗口坐标中(1, 0), (7, 0), (6, 2), (2, 2)围成的梯形
+		{"vertex behind the camera", []mgl32.Vec4{
+			eye(-1, -1, -2), eye(1, -1, -2), eye(0, -1, 2),
+		}, func(x, y int) bool { return y == 0 && x >= 1 && x <= 6 || y == 1 && x >= 2 && x <= 5 }},
+	}
+	for _, tt := range tests {
+		ctx := NewContext(size, size)
+		ctx.Enable(DEPTH_TEST)
+		counts := coverage(t, ctx, tt.positions...)
+		for y := range counts {
+			for x, n := range counts[y] {
+				if want := tt.want(x, y); (n != 0) != want {
+					t.Errorf("%s: pixel (%d, %d) shaded %d times, want drawn %v", tt.name, x, y, n, want)
+				}
+			}
+		}
+		for i, d := range ctx.depth {
+			if d < 0 || d > 1 {
+				t.Errorf("%s: depth %d = %v", tt.name, i, d)
+			}
+		}
+	}
+}
+
+//TestVertexAttribIndex 属性下标不小于MaxVertexAttribs时报错, 不会扩展VAO的属性表
+func TestVertexAttribIndex(t *testing.T) {
+	calls := map[string]func(ctx *Context){
+		"VertexAttribPointer":      func(ctx *Context) { ctx.VertexAttribPointer(MaxVertexAttribs, 4, 0, 0) },
+		"EnableVertexAttribArray":  func(ctx *Context) { ctx.EnableVertexAttribArray(1 << 31) },
+		"DisableVertexAttribArray": func(ctx *Context) { ctx.DisableVertexAttribArray(MaxVertexAttribs) },
+	}
+	for name, call := range calls {
+		ctx := NewContext(size, size)
+		vao := ctx.GenVertexArray()
+		ctx.BindVertexArray(vao)
+		ctx.BindBuffer(ARRAY_BUFFER, ctx.GenBuffer())
+		call(ctx)
+		if ctx.Err() != errInvalidValue {
+			t.Errorf("%s: error %v, want %v", name, ctx.Err(), errInvalidValue)
+		}
+		if n := len(ctx.vertexArray[vao].attribs); n != 0 {
+			t.Errorf("%s: VAO has %d attributes", name, n)
+		}
+	}
+
+	ctx := NewContext(size, size)
+	ctx.BindVertexArray(ctx.GenVertexArray())
+	ctx.BindBuffer(ARRAY_BUFFER, ctx.GenBuffer())
+	ctx.VertexAttribPointer(MaxVertexAttribs-1, 4, 0, 0)
+	ctx.EnableVertexAttribArray(MaxVertexAttribs - 1)
+	if err := ctx.Err(); err != nil {
+		t.Errorf("last attribute: %v", err)
+	}
+}
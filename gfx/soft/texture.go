@@ -0,0 +1,89 @@
+package soft
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//Texture 软件纹理,供片段着色器闭包采样
+//与gl.TexImage2D相同,图像的第0行对应纹理坐标t=0
+type Texture struct {
+	width  int
+	height int
+	texels []mgl32.Vec4
+
+	//Linear 为true时双线性过滤(GL_LINEAR),否则为最近邻(GL_NEAREST)
+	Linear bool
+}
+
+//NewTexture 从image.Image创建纹理,环绕方式为CLAMP_TO_EDGE
+//sRGB 为true时按SRGB_ALPHA内部格式把颜色分量转换到线性空间
+func NewTexture(img image.Image, sRGB bool) *Texture {
+	b := img.Bounds()
+	rgba := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+
+	tex := &Texture{
+		width:  b.Dx(),
+		height: b.Dy(),
+		texels: make([]mgl32.Vec4, b.Dx()*b.Dy()),
+		Linear: true,
+	}
+	for i := range tex.texels {
+		p := rgba.Pix[i*4 : i*4+4]
+		c := mgl32.Vec4{float32(p[0]) / 255, float32(p[1]) / 255, float32(p[2]) / 255, float32(p[3]) / 255}
+		if sRGB {
+			c[0], c[1], c[2] = srgbToLinear(c[0]), srgbToLinear(c[1]), srgbToLinear(c[2])
+		}
+		tex.texels[i] = c
+	}
+	return tex
+}
+
+//Sample 按纹理坐标(s,t)采样,对应GLSL的texture()
+func (tex *Texture) Sample(s, t float32) mgl32.Vec4 {
+	if tex.width == 0 || tex.height == 0 {
+		return mgl32.Vec4{0, 0, 0, 1}
+	}
+	u := float64(s)*float64(tex.width) - 0.5
+	v := float64(t)*float64(tex.height) - 0.5
+	if !tex.Linear {
+		return tex.texel(int(math.Floor(u+0.5)), int(math.Floor(v+0.5)))
+	}
+	x0, y0 := math.Floor(u), math.Floor(v)
+	fx, fy := float32(u-x0), float32(v-y0)
+	i, j := int(x0), int(y0)
+	top := lerp4(tex.texel(i, j), tex.texel(i+1, j), fx)
+	bottom := lerp4(tex.texel(i, j+1), tex.texel(i+1, j+1), fx)
+	return lerp4(top, bottom, fy)
+}
+
+func (tex *Texture) texel(x, y int) mgl32.Vec4 {
+	x = clampInt(x, 0, tex.width-1)
+	y = clampInt(y, 0, tex.height-1)
+	return tex.texels[y*tex.width+x]
+}
+
+func lerp4(a, b mgl32.Vec4, t float32) mgl32.Vec4 {
+	return a.Add(b.Sub(a).Mul(t))
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+func srgbToLinear(c float32) float32 {
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return float32(math.Pow((float64(c)+0.055)/1.055, 2.4))
+}
@@ -0,0 +1,128 @@
+/*
+std140布局
+按GLSL std140规则计算Go结构体各字段的偏移并编码为字节:
+标量4字节对齐; vec2 8字节; vec3 16字节对齐但只占12字节, 后面的标量可以紧跟其后;
+数组元素、矩阵列和结构体都按16字节对齐, 大小向上取整到16字节
+*/
+
+package gfx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+var (
+	vec2Type = reflect.TypeOf(mgl32.Vec2{})
+	vec3Type = reflect.TypeOf(mgl32.Vec3{})
+	vec4Type = reflect.TypeOf(mgl32.Vec4{})
+	mat2Type = reflect.TypeOf(mgl32.Mat2{})
+	mat3Type = reflect.TypeOf(mgl32.Mat3{})
+	mat4Type = reflect.TypeOf(mgl32.Mat4{})
+)
+
+//std140Layout 返回类型在std140布局下的对齐和大小
+//支持float32、int32、uint32、bool、mgl32的向量与方阵、定长数组以及由它们组成的结构体
+func std140Layout(t reflect.Type) (align, size int, err error) {
+	switch t {
+	case vec2Type:
+		return 8, 8, nil
+	case vec3Type:
+		return 16, 12, nil
+	case vec4Type:
+		return 16, 16, nil
+	case mat2Type:
+		return 16, 2 * 16, nil
+	case mat3Type:
+		return 16, 3 * 16, nil
+	case mat4Type:
+		return 16, 4 * 16, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Float32, reflect.Int32, reflect.Uint32, reflect.Bool:
+		return 4, 4, nil
+	case reflect.Array:
+		_, elemSize, err := std140Layout(t.Elem())
+		if err != nil {
+			return 0, 0, err
+		}
+		return 16, roundUp(elemSize, 16) * t.Len(), nil
+	case reflect.Struct:
+		offset := 0
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				return 0, 0, fmt.Errorf("std140: unexported field %s.%s", t.Name(), field.Name)
+			}
+			a, s, err := std140Layout(field.Type)
+			if err != nil {
+				return 0, 0, err
+			}
+			offset = roundUp(offset, a) + s
+		}
+		return 16, roundUp(offset, 16), nil
+	}
+	return 0, 0, fmt.Errorf("std140: unsupported type %s", t)
+}
+
+//putStd140 将v按std140布局写入buf[offset:], v的类型必须已经通过std140Layout检查
+func putStd140(buf []byte, offset int, v reflect.Value) {
+	switch v.Type() {
+	case vec2Type, vec3Type, vec4Type:
+		for i := 0; i < v.Len(); i++ {
+			putFloat32(buf[offset+4*i:], float32(v.Index(i).Float()))
+		}
+		return
+	case mat2Type, mat3Type, mat4Type:
+		// 列主序, 每列按vec4对齐
+		n := int(math.Sqrt(float64(v.Len())))
+		for col := 0; col < n; col++ {
+			for row := 0; row < n; row++ {
+				putFloat32(buf[offset+16*col+4*row:], float32(v.Index(col*n+row).Float()))
+			}
+		}
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Float32:
+		putFloat32(buf[offset:], float32(v.Float()))
+	case reflect.Int32:
+		binary.LittleEndian.PutUint32(buf[offset:], uint32(v.Int()))
+	case reflect.Uint32:
+		binary.LittleEndian.PutUint32(buf[offset:], uint32(v.Uint()))
+	case reflect.Bool:
+		var b uint32
+		if v.Bool() {
+			b = 1
+		}
+		binary.LittleEndian.PutUint32(buf[offset:], b)
+	case reflect.Array:
+		_, elemSize, _ := std140Layout(v.Type().Elem())
+		stride := roundUp(elemSize, 16)
+		for i := 0; i < v.Len(); i++ {
+			putStd140(buf, offset+i*stride, v.Index(i))
+		}
+	case reflect.Struct:
+		fieldOffset := 0
+		for i := 0; i < v.NumField(); i++ {
+			a, s, _ := std140Layout(v.Field(i).Type())
+			fieldOffset = roundUp(fieldOffset, a)
+			putStd140(buf, offset+fieldOffset, v.Field(i))
+			fieldOffset += s
+		}
+	}
+}
+
+func putFloat32(buf []byte, f float32) {
+	binary.LittleEndian.PutUint32(buf, math.Float32bits(f))
+}
+
+func roundUp(n, align int) int {
+	return (n + align - 1) / align * align
+}
@@ -0,0 +1,169 @@
+package gfx
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type vec3Float struct {
+	Position  mgl32.Vec3
+	Intensity float32 // 紧跟在vec3之后, 偏移12
+}
+
+type floatVec3 struct {
+	Intensity float32
+	Position  mgl32.Vec3 // 按16字节对齐, 偏移16
+}
+
+type light struct {
+	Color mgl32.Vec3
+	On    bool
+}
+
+type lights struct {
+	Ambient float32
+	Lights  [2]light
+	Count   int32
+}
+
+func TestStd140Layout(t *testing.T) {
+	tests := []struct {
+		value       interface{}
+		align, size int
+	}{
+		{float32(0), 4, 4},
+		{int32(0), 4, 4},
+		{uint32(0), 4, 4},
+		{false, 4, 4},
+		{mgl32.Vec2{}, 8, 8},
+		{mgl32.Vec3{}, 16, 12},
+		{mgl32.Vec4{}, 16, 16},
+		{mgl32.Mat2{}, 16, 32},
+		{mgl32.Mat3{}, 16, 48},
+		{mgl32.Mat4{}, 16, 64},
+		// 数组元素的步长向上取整到16字节
+		{[3]float32{}, 16, 48},
+		{[2]mgl32.Vec2{}, 16, 32},
+		{[2]mgl32.Vec3{}, 16, 32},
+		{[2]mgl32.Mat3{}, 16, 96},
+		{[2][3]float32{}, 16, 96},
+		{vec3Float{}, 16, 16},
+		{floatVec3{}, 16, 32},
+		// Ambient 0, Lights 16与32(每个light占16字节), Count 48
+		{lights{}, 16, 64},
+		{[2]light{}, 16, 32},
+		{struct{}{}, 16, 0},
+	}
+	for _, tt := range tests {
+		typ := reflect.TypeOf(tt.value)
+		align, size, err := std140Layout(typ)
+		if err != nil || align != tt.align || size != tt.size {
+			t.Errorf("%s: align %d size %d %v, want %d %d", typ, align, size, err, tt.align, tt.size)
+		}
+	}
+}
+
+func TestStd140LayoutErrors(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{float64(0), "unsupported type float64"},
+		{0, "unsupported type int"},
+		{[]float32{}, "unsupported type []float32"},
+		{[2]int64{}, "unsupported type int64"},
+		{struct{ A struct{ B string } }{}, "unsupported type string"},
+		{struct{ x float32 }{}, "unexported field .x"},
+	}
+	for _, tt := range tests {
+		_, _, err := std140Layout(reflect.TypeOf(tt.value))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%T: error %v, want %q", tt.value, err, tt.want)
+		}
+	}
+}
+
+//TestPutStd140 按偏移检查写入的每个4字节字, 其余的填充字节保持为0
+func TestPutStd140(t *testing.T) {
+	type block struct {
+		Position mgl32.Vec3
+		Radius   float32
+		Normal   mgl32.Mat3
+		Weights  [2]float32
+		Corners  [2]mgl32.Vec3
+		Light    light
+		Scale    mgl32.Vec2
+		Count    int32
+	}
+	v := block{
+		Position: mgl32.Vec3{1, 2, 3},
+		Radius:   4,
+		Normal:   mgl32.Mat3{5, 6, 7, 8, 9, 10, 11, 12, 13},
+		Weights:  [2]float32{14, 15},
+		Corners:  [2]mgl32.Vec3{{16, 17, 18}, {19, 20, 21}},
+		Light:    light{Color: mgl32.Vec3{22, 23, 24}, On: true},
+		Scale:    mgl32.Vec2{25, 26},
+		Count:    -1,
+	}
+	floats := map[int]float32{
+		0: 1, 4: 2, 8: 3, 12: 4,
+		// mat3 每列占16字节
+		16: 5, 20: 6, 24: 7,
+		32: 8, 36: 9, 40: 10,
+		48: 11, 52: 12, 56: 13,
+		// 数组元素步长16
+		64: 14, 80: 15,
+		96: 16, 100: 17, 104: 18,
+		112: 19, 116: 20, 120: 21,
+		// 嵌套的结构体从16字节边界开始, 大小取整到16字节
+		128: 22, 132: 23, 136: 24,
+		144: 25, 148: 26,
+	}
+	uints := map[int]uint32{
+		140: 1,          // Light.On
+		152: 0xffffffff, // Count
+	}
+
+	_, size, err := std140Layout(reflect.TypeOf(v))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 160 {
+		t.Fatalf("size %d, want 160", size)
+	}
+	buf := make([]byte, size)
+	putStd140(buf, 0, reflect.ValueOf(v))
+	for offset := 0; offset < size; offset += 4 {
+		word := binary.LittleEndian.Uint32(buf[offset:])
+		want := uints[offset]
+		if f, ok := floats[offset]; ok {
+			want = math.Float32bits(f)
+		}
+		if word != want {
+			t.Errorf("offset %d: %#x, want %#x", offset, word, want)
+		}
+	}
+}
+
+func TestUniformBufferErrors(t *testing.T) {
+	tests := []struct {
+		block interface{}
+		want  string
+	}{
+		{struct{}{}, "has no data"},
+		{&struct{ Lights [0]light }{}, "has no data"},
+		{mgl32.Mat4{}, "block must be a struct"},
+		{nil, "block must be a struct"},
+		{struct{ A float64 }{}, "unsupported type float64"},
+	}
+	for _, tt := range tests {
+		if _, err := NewUniformBuffer(0, tt.block); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%T: error %v, want %q", tt.block, err, tt.want)
+		}
+	}
+}
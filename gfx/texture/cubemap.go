@@ -0,0 +1,322 @@
+package texture
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io/fs"
+	"math"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// 立方体贴图各面的下标, 与gl.TEXTURE_CUBE_MAP_POSITIVE_X起的6个面顺序相同
+const (
+	PositiveX = iota // 右
+	NegativeX        // 左
+	PositiveY        // 上
+	NegativeY        // 下
+	PositiveZ        // 前
+	NegativeZ        // 后
+)
+
+//NewCubemapFromFiles 从6张图片文件创建立方体贴图, 顺序为+X,-X,+Y,-Y,+Z,-Z(右、左、上、下、前、后)
+func NewCubemapFromFiles(files [6]string, opts TextureOptions) (*Texture, error) {
+	var faces [6]image.Image
+	for i, file := range files {
+		img, err := loadImageFile(file)
+		if err != nil {
+			return nil, err
+		}
+		faces[i] = img
+	}
+	return NewCubemap(faces, opts)
+}
+
+//NewCubemapFromFile 从单张图片创建立方体贴图, 按宽高比识别布局, 见NewCubemapFromImage
+func NewCubemapFromFile(file string, opts TextureOptions) (*Texture, error) {
+	img, err := loadImageFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return newCubemapFromImageFile(img, file, opts)
+}
+
+//NewCubemapFromFS 从文件系统fsys(如 embed.FS)中的单张图片创建立方体贴图
+func NewCubemapFromFS(fsys fs.FS, file string, opts TextureOptions) (*Texture, error) {
+	img, err := loadImageFS(fsys, file)
+	if err != nil {
+		return nil, err
+	}
+	return newCubemapFromImageFile(img, file, opts)
+}
+
+func newCubemapFromImageFile(img image.Image, file string, opts TextureOptions) (*Texture, error) {
+	tex, err := NewCubemapFromImage(img, opts)
+	if err != nil {
+		return nil, fmt.Errorf("load cubemap file %s: %v", file, err)
+	}
+	return tex, nil
+}
+
+//NewCubemapFromImage 从单张图片创建立方体贴图:
+//宽高比2:1为等距柱状投影(全景图), 4:3为横向十字, 3:4为纵向十字
+func NewCubemapFromImage(img image.Image, opts TextureOptions) (*Texture, error) {
+	b := img.Bounds()
+	var faces [6]image.Image
+	var err error
+	switch {
+	case b.Dx() == 2*b.Dy():
+		faces = EquirectFaces(img, b.Dx()/4)
+	case b.Dx()*3 == b.Dy()*4, b.Dx()*4 == b.Dy()*3:
+		faces, err = CrossFaces(img)
+	default:
+		err = fmt.Errorf("cubemap: can't tell layout of %dx%d image (want 2:1, 4:3 or 3:4)", b.Dx(), b.Dy())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewCubemap(faces, opts)
+}
+
+//NewCubemap 从6个面创建立方体贴图, 各面必须是大小相同、像素格式相同的正方形
+//环绕方式未指定时为gl.CLAMP_TO_EDGE, 避免面与面之间出现接缝
+func NewCubemap(faces [6]image.Image, opts TextureOptions) (*Texture, error) {
+	if opts.WrapS == 0 && opts.WrapT == 0 && opts.WrapR == 0 {
+		opts.WrapS, opts.WrapT, opts.WrapR = gl.CLAMP_TO_EDGE, gl.CLAMP_TO_EDGE, gl.CLAMP_TO_EDGE
+	}
+	if _, err := opts.minFilter(); err != nil {
+		return nil, err
+	}
+
+	var pixels [6]*Pixels
+	var internalFmt int32
+	var format, pixType uint32
+	for i, face := range faces {
+		p := PixelsFromImage(face).expandGray(opts.Linear)
+		f, fm, pt, err := p.format(opts.Linear)
+		if err != nil {
+			return nil, err
+		}
+		if p.Width != p.Height {
+			return nil, fmt.Errorf("cubemap: face %d is %dx%d, not square", i, p.Width, p.Height)
+		}
+		if i > 0 && (p.Width != pixels[0].Width || f != internalFmt || fm != format || pt != pixType) {
+			return nil, fmt.Errorf("cubemap: face %d differs in size or format from face 0", i)
+		}
+		pixels[i], internalFmt, format, pixType = p, f, fm, pt
+	}
+
+	var handle uint32
+	gl.GenTextures(1, &handle)
+	texture := Texture{
+		handle: handle,
+		target: gl.TEXTURE_CUBE_MAP,
+	}
+	texture.Bind(gl.TEXTURE0)
+	defer texture.UnBind()
+
+	if err := opts.apply(texture.target); err != nil {
+		texture.Delete()
+		return nil, err
+	}
+	if pixels[0].gray {
+		swizzle := [4]int32{gl.RED, gl.RED, gl.RED, gl.ONE}
+		gl.TexParameteriv(texture.target, gl.TEXTURE_SWIZZLE_RGBA, &swizzle[0])
+	}
+
+	var alignment int32
+	gl.GetIntegerv(gl.UNPACK_ALIGNMENT, &alignment)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	for i, p := range pixels {
+		gl.TexImage2D(gl.TEXTURE_CUBE_MAP_POSITIVE_X+uint32(i), 0, internalFmt,
+			int32(p.Width), int32(p.Height), 0, format, pixType, gl.Ptr(p.Data))
+	}
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, alignment)
+
+	if opts.Mipmap {
+		gl.GenerateMipmap(texture.target)
+	}
+	return &texture, nil
+}
+
+//CrossFaces 从十字布局的图片中切出6个面
+//横向十字(4:3):                纵向十字(3:4):
+//	     +Y                         +Y
+//	-X   +Z   +X   -Z          -X   +Z   +X
+//	     -Y                         -Y
+//	                                -Z (旋转180度)
+func CrossFaces(img image.Image) ([6]image.Image, error) {
+	var faces [6]image.Image
+	b := img.Bounds()
+	// 各面在十字中的格子坐标(列, 行)
+	var cells [6][2]int
+	var size int
+	switch {
+	case b.Empty():
+		return faces, fmt.Errorf("cubemap: cross image is empty")
+	case b.Dx()*3 == b.Dy()*4:
+		size = b.Dx() / 4
+		cells = [6][2]int{{2, 1}, {0, 1}, {1, 0}, {1, 2}, {1, 1}, {3, 1}}
+	case b.Dx()*4 == b.Dy()*3:
+		size = b.Dx() / 3
+		cells = [6][2]int{{2, 1}, {0, 1}, {1, 0}, {1, 2}, {1, 1}, {1, 3}}
+	default:
+		return faces, fmt.Errorf("cubemap: %dx%d image is not a 4:3 or 3:4 cross", b.Dx(), b.Dy())
+	}
+	vertical := b.Dx() < b.Dy()
+
+	for i, cell := range cells {
+		min := b.Min.Add(image.Pt(cell[0]*size, cell[1]*size))
+		rect := image.Rectangle{Min: min, Max: min.Add(image.Pt(size, size))}
+		if vertical && i == NegativeZ {
+			faces[i] = rotate180(img, rect)
+			continue
+		}
+		sub, ok := img.(interface {
+			SubImage(image.Rectangle) image.Image
+		})
+		if !ok {
+			return faces, fmt.Errorf("cubemap: %T does not support SubImage", img)
+		}
+		faces[i] = sub.SubImage(rect)
+	}
+	return faces, nil
+}
+
+//rotate180 复制img中rect区域并旋转180度
+//结果与img的类型相同, PixelsFromImage为它和其他SubImage得到的面选择相同的像素格式;
+//PixelsFromImage不单独处理的类型本来就会转换为NRGBA, 这时结果也是NRGBA
+func rotate180(img image.Image, rect image.Rectangle) image.Image {
+	w, h := rect.Dx(), rect.Dy()
+	bounds := image.Rect(0, 0, w, h)
+	switch m := img.(type) {
+	case *image.Gray:
+		out := image.NewGray(bounds)
+		rotatePix(out.Pix, out.Stride, m.Pix[m.PixOffset(rect.Min.X, rect.Min.Y):], m.Stride, w, h, 1)
+		return out
+	case *image.Gray16:
+		out := image.NewGray16(bounds)
+		rotatePix(out.Pix, out.Stride, m.Pix[m.PixOffset(rect.Min.X, rect.Min.Y):], m.Stride, w, h, 2)
+		return out
+	case *image.RGBA:
+		out := image.NewRGBA(bounds)
+		rotatePix(out.Pix, out.Stride, m.Pix[m.PixOffset(rect.Min.X, rect.Min.Y):], m.Stride, w, h, 4)
+		return out
+	case *image.NRGBA:
+		out := image.NewNRGBA(bounds)
+		rotatePix(out.Pix, out.Stride, m.Pix[m.PixOffset(rect.Min.X, rect.Min.Y):], m.Stride, w, h, 4)
+		return out
+	case *image.RGBA64:
+		out := image.NewRGBA64(bounds)
+		rotatePix(out.Pix, out.Stride, m.Pix[m.PixOffset(rect.Min.X, rect.Min.Y):], m.Stride, w, h, 8)
+		return out
+	case *image.NRGBA64:
+		out := image.NewNRGBA64(bounds)
+		rotatePix(out.Pix, out.Stride, m.Pix[m.PixOffset(rect.Min.X, rect.Min.Y):], m.Stride, w, h, 8)
+		return out
+	case *image.YCbCr:
+		// 旋转后色度采样的位置会错开, 改用不降采样的4:4:4
+		out := image.NewYCbCr(bounds, image.YCbCrSubsampleRatio444)
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				yi, ci := m.YOffset(rect.Min.X+x, rect.Min.Y+y), m.COffset(rect.Min.X+x, rect.Min.Y+y)
+				oy, oc := out.YOffset(w-1-x, h-1-y), out.COffset(w-1-x, h-1-y)
+				out.Y[oy], out.Cb[oc], out.Cr[oc] = m.Y[yi], m.Cb[ci], m.Cr[ci]
+			}
+		}
+		return out
+	}
+	out := image.NewNRGBA(bounds)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			out.Set(w-1-x, h-1-y, img.At(rect.Min.X+x, rect.Min.Y+y))
+		}
+	}
+	return out
+}
+
+//rotatePix 将src中w*h个像素旋转180度写入dst, 每个像素bpp字节
+func rotatePix(dst []uint8, dstStride int, src []uint8, srcStride, w, h, bpp int) {
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			d := (h-1-y)*dstStride + (w-1-x)*bpp
+			s := y*srcStride + x*bpp
+			copy(dst[d:d+bpp], src[s:s+bpp])
+		}
+	}
+}
+
+//EquirectFaces 将等距柱状投影(经度为横轴、纬度为纵轴)的全景图重采样为6个size*size的面
+//全景图的水平中心对应-Z方向(OpenGL中相机默认的朝向), 上边缘对应+Y
+func EquirectFaces(img image.Image, size int) [6]image.Image {
+	var faces [6]image.Image
+	for face := range faces {
+		out := image.NewRGBA(image.Rect(0, 0, size, size))
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				dir := cubeDirection(face, (float64(x)+0.5)/float64(size), (float64(y)+0.5)/float64(size))
+				out.SetRGBA(x, y, sampleEquirect(img, dir))
+			}
+		}
+		faces[face] = out
+	}
+	return faces
+}
+
+//cubeDirection 立方体贴图第face个面上纹理坐标(s, t)对应的方向, t从上传数据的第一行开始
+//与OpenGL规范中立方体贴图的选面规则互逆
+func cubeDirection(face int, s, t float64) [3]float64 {
+	sc, tc := 2*s-1, 2*t-1
+	switch face {
+	case PositiveX:
+		return [3]float64{1, -tc, -sc}
+	case NegativeX:
+		return [3]float64{-1, -tc, sc}
+	case PositiveY:
+		return [3]float64{sc, 1, tc}
+	case NegativeY:
+		return [3]float64{sc, -1, -tc}
+	case PositiveZ:
+		return [3]float64{sc, -tc, 1}
+	default:
+		return [3]float64{-sc, -tc, -1}
+	}
+}
+
+//sampleEquirect 沿dir方向对全景图做双线性采样, 水平方向首尾相接
+func sampleEquirect(img image.Image, dir [3]float64) color.RGBA {
+	b := img.Bounds()
+	length := math.Sqrt(dir[0]*dir[0] + dir[1]*dir[1] + dir[2]*dir[2])
+	u := 0.5 + math.Atan2(dir[0], -dir[2])/(2*math.Pi)
+	v := math.Acos(dir[1]/length) / math.Pi
+
+	fx := u*float64(b.Dx()) - 0.5
+	fy := math.Max(0, math.Min(v*float64(b.Dy())-0.5, float64(b.Dy()-1)))
+	x0, y0 := int(math.Floor(fx)), int(fy)
+	wx, wy := fx-float64(x0), fy-float64(y0)
+	y1 := y0 + 1
+	if y1 >= b.Dy() {
+		y1 = y0
+	}
+
+	var sum [4]float64
+	for _, tap := range [4]struct {
+		x, y int
+		w    float64
+	}{
+		{x0, y0, (1 - wx) * (1 - wy)}, {x0 + 1, y0, wx * (1 - wy)},
+		{x0, y1, (1 - wx) * wy}, {x0 + 1, y1, wx * wy},
+	} {
+		x := ((tap.x % b.Dx()) + b.Dx()) % b.Dx()
+		r, g, bl, a := img.At(b.Min.X+x, b.Min.Y+tap.y).RGBA()
+		sum[0] += tap.w * float64(r)
+		sum[1] += tap.w * float64(g)
+		sum[2] += tap.w * float64(bl)
+		sum[3] += tap.w * float64(a)
+	}
+	return color.RGBA{
+		R: uint8(sum[0]/257 + 0.5), G: uint8(sum[1]/257 + 0.5),
+		B: uint8(sum[2]/257 + 0.5), A: uint8(sum[3]/257 + 0.5),
+	}
+}
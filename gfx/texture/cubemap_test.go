@@ -0,0 +1,248 @@
+package texture
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+//grayImage 生成kind类型的w*h图片, (x, y)处的灰度为f(x, y)
+func grayImage(kind string, w, h int, f func(x, y int) uint8) image.Image {
+	r := image.Rect(0, 0, w, h)
+	var img interface {
+		image.Image
+		Set(x, y int, c color.Color)
+	}
+	switch kind {
+	case "Gray":
+		img = image.NewGray(r)
+	case "Gray16":
+		img = image.NewGray16(r)
+	case "RGBA":
+		img = image.NewRGBA(r)
+	case "NRGBA":
+		img = image.NewNRGBA(r)
+	case "RGBA64":
+		img = image.NewRGBA64(r)
+	case "NRGBA64":
+		img = image.NewNRGBA64(r)
+	case "Paletted":
+		palette := make(color.Palette, 256)
+		for i := range palette {
+			palette[i] = color.Gray{uint8(i)}
+		}
+		img = image.NewPaletted(r, palette)
+	default:
+		// YCbCr没有Set, 色度取中间值时颜色就是亮度
+		ratio := image.YCbCrSubsampleRatio444
+		if kind == "YCbCr420" {
+			ratio = image.YCbCrSubsampleRatio420
+		}
+		m := image.NewYCbCr(r, ratio)
+		for i := range m.Cb {
+			m.Cb[i], m.Cr[i] = 128, 128
+		}
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				m.Y[m.YOffset(x, y)] = f(x, y)
+			}
+		}
+		return m
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.Gray{f(x, y)})
+		}
+	}
+	return img
+}
+
+//TestCrossFaces 每个面取自十字中对应的格子, 纵向十字的-Z旋转180度, 6个面的像素格式一致
+func TestCrossFaces(t *testing.T) {
+	const size = 2
+	layouts := []struct {
+		name  string
+		w, h  int
+		cells [6][2]int
+	}{
+		{"horizontal", 4 * size, 3 * size, [6][2]int{{2, 1}, {0, 1}, {1, 0}, {1, 2}, {1, 1}, {3, 1}}},
+		{"vertical", 3 * size, 4 * size, [6][2]int{{2, 1}, {0, 1}, {1, 0}, {1, 2}, {1, 1}, {1, 3}}},
+	}
+	kinds := []string{"Gray", "Gray16", "RGBA", "NRGBA", "RGBA64", "NRGBA64", "YCbCr", "YCbCr420", "Paletted"}
+	// 格子(列, 行)中每个像素的灰度都不同
+	value := func(x, y int) uint8 {
+		return uint8((y/size*4+x/size)*size*size + y%size*size + x%size)
+	}
+	for _, layout := range layouts {
+		for _, kind := range kinds {
+			img := grayImage(kind, layout.w, layout.h, value)
+			faces, err := CrossFaces(img)
+			if err != nil {
+				t.Errorf("%s %s: %v", layout.name, kind, err)
+				continue
+			}
+			var formats [6][3]uint32
+			for i, face := range faces {
+				b := face.Bounds()
+				if b.Dx() != size || b.Dy() != size {
+					t.Errorf("%s %s: face %d is %v", layout.name, kind, i, b)
+					continue
+				}
+				for y := 0; y < size; y++ {
+					for x := 0; x < size; x++ {
+						sx, sy := x, y
+						if layout.name == "vertical" && i == NegativeZ {
+							sx, sy = size-1-x, size-1-y
+						}
+						want := img.At(layout.cells[i][0]*size+sx, layout.cells[i][1]*size+sy)
+						got := face.At(b.Min.X+x, b.Min.Y+y)
+						if !sameColor(got, want) {
+							t.Errorf("%s %s: face %d (%d, %d) is %v, want %v", layout.name, kind, i, x, y, got, want)
+						}
+					}
+				}
+				internalFmt, format, pixType, err := PixelsFromImage(face).expandGray(false).format(false)
+				if err != nil {
+					t.Errorf("%s %s: face %d: %v", layout.name, kind, i, err)
+				}
+				formats[i] = [3]uint32{uint32(internalFmt), format, pixType}
+				if formats[i] != formats[0] {
+					t.Errorf("%s %s: face %d format %v differs from face 0 %v", layout.name, kind, i, formats[i], formats[0])
+				}
+			}
+		}
+	}
+
+	for _, r := range []image.Rectangle{image.Rect(0, 0, 8, 4), image.Rect(0, 0, 6, 6), image.Rect(0, 0, 0, 0)} {
+		if _, err := CrossFaces(image.NewGray(r)); err == nil {
+			t.Errorf("%v: no error for a non-cross image", r)
+		}
+	}
+	if _, err := CrossFaces(image.NewUniform(color.Black)); err == nil {
+		t.Errorf("Uniform: no error for an image without SubImage")
+	}
+}
+
+//TestCrossFacesOffset 图片边界不从原点开始时按边界切分
+func TestCrossFacesOffset(t *testing.T) {
+	img := grayImage("NRGBA", 6, 8, func(x, y int) uint8 { return uint8(y*6 + x) })
+	sub := img.(*image.NRGBA).SubImage(image.Rect(0, 0, 6, 8)).(*image.NRGBA)
+	sub.Rect = image.Rect(10, 20, 16, 28)
+	faces, err := CrossFaces(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// -Z在第4行中间的格子, 旋转后左上角是格子的右下角(3, 7)
+	if got, want := faces[NegativeZ].At(0, 0), sub.At(13, 27); !sameColor(got, want) {
+		t.Errorf("-Z (0, 0) is %v, want %v", got, want)
+	}
+	if got, want := faces[PositiveY].At(12, 20), sub.At(12, 20); !sameColor(got, want) {
+		t.Errorf("+Y (12, 20) is %v, want %v", got, want)
+	}
+}
+
+func sameColor(a, b color.Color) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
+
+//glFace 按OpenGL规范中立方体贴图的选面规则求方向dir落在的面和纹理坐标
+func glFace(dir [3]float64) (face int, s, t float64) {
+	x, y, z := dir[0], dir[1], dir[2]
+	var sc, tc, ma float64
+	switch ax, ay, az := math.Abs(x), math.Abs(y), math.Abs(z); {
+	case ax >= ay && ax >= az && x > 0:
+		face, sc, tc, ma = PositiveX, -z, -y, ax
+	case ax >= ay && ax >= az:
+		face, sc, tc, ma = NegativeX, z, -y, ax
+	case ay >= az && y > 0:
+		face, sc, tc, ma = PositiveY, x, z, ay
+	case ay >= az:
+		face, sc, tc, ma = NegativeY, x, -z, ay
+	case z > 0:
+		face, sc, tc, ma = PositiveZ, x, -y, az
+	default:
+		face, sc, tc, ma = NegativeZ, -x, -y, az
+	}
+	return face, (sc/ma + 1) / 2, (tc/ma + 1) / 2
+}
+
+func TestCubeDirection(t *testing.T) {
+	axes := [6][3]float64{{1, 0, 0}, {-1, 0, 0}, {0, 1, 0}, {0, -1, 0}, {0, 0, 1}, {0, 0, -1}}
+	for face, axis := range axes {
+		if got := cubeDirection(face, 0.5, 0.5); got != axis {
+			t.Errorf("face %d center: %v, want %v", face, got, axis)
+		}
+		// 避开面的边缘, 那里的方向同时属于两个面
+		for _, s := range []float64{0.1, 0.3, 0.7, 0.9} {
+			for _, tt := range []float64{0.1, 0.4, 0.8} {
+				f, gs, gt := glFace(cubeDirection(face, s, tt))
+				if f != face || math.Abs(gs-s) > 1e-9 || math.Abs(gt-tt) > 1e-9 {
+					t.Errorf("face %d (%g, %g): OpenGL samples face %d (%g, %g)", face, s, tt, f, gs, gt)
+				}
+			}
+		}
+	}
+}
+
+//TestEquirectFaces 全景图按经度分为4个扇区, 上下两端各一种颜色, 每个面的中心取到对应的颜色
+func TestEquirectFaces(t *testing.T) {
+	const w, h = 64, 32
+	top, bottom := color.RGBA{255, 255, 255, 255}, color.RGBA{0, 0, 0, 255}
+	// 全景图中心(u=0.5)是-Z, u=0.75是+X, u=0.25是-X, 左右两端是+Z
+	negZ, posX, posZ, negX := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 255, 0, 255}, color.RGBA{0, 0, 255, 255}, color.RGBA{255, 255, 0, 255}
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := posZ
+			switch {
+			case y < h/4:
+				c = top
+			case y >= h*3/4:
+				c = bottom
+			case x >= w*3/8 && x < w*5/8:
+				c = negZ
+			case x >= w*5/8 && x < w*7/8:
+				c = posX
+			case x >= w/8 && x < w*3/8:
+				c = negX
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	const size = 8
+	faces := EquirectFaces(img, size)
+	want := [6]color.RGBA{posX, negX, top, bottom, posZ, negZ}
+	for i, face := range faces {
+		rgba, ok := face.(*image.RGBA)
+		if !ok || rgba.Bounds() != image.Rect(0, 0, size, size) {
+			t.Errorf("face %d: %T %v, want %dx%d RGBA", i, face, face.Bounds(), size, size)
+			continue
+		}
+		for _, p := range []image.Point{{3, 3}, {4, 4}, {3, 4}, {4, 3}} {
+			if got := rgba.RGBAAt(p.X, p.Y); got != want[i] {
+				t.Errorf("face %d %v: %v, want %v", i, p, got, want[i])
+			}
+		}
+	}
+
+	uniform := image.NewUniform(color.RGBA{10, 20, 30, 255})
+	uniformImg := image.NewRGBA(image.Rect(0, 0, 16, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 16; x++ {
+			uniformImg.Set(x, y, uniform.C)
+		}
+	}
+	for i, face := range EquirectFaces(uniformImg, 4) {
+		rgba := face.(*image.RGBA)
+		for j := 0; j < len(rgba.Pix); j += 4 {
+			if got := (color.RGBA{rgba.Pix[j], rgba.Pix[j+1], rgba.Pix[j+2], rgba.Pix[j+3]}); got != uniform.C {
+				t.Errorf("uniform face %d pixel %d: %v", i, j/4, got)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package texture
+
+import (
+	"image"
+	"io/fs"
+	"sync"
+	"time"
+)
+
+//Loader 异步纹理加载器: 图片在后台goroutine中读取和解码,
+//渲染线程每帧调用Upload, 在限定时间内把解码好的像素上传到显存
+type Loader struct {
+	sem chan struct{} // 限制同时解码的goroutine个数
+
+	mu       sync.Mutex
+	decoded  []*Pending // 已解码、等待上传
+	inFlight int        // 已请求、尚未上传完成
+}
+
+//Pending 正在异步加载的纹理, 上传完成(或失败)后变为就绪
+type Pending struct {
+	file   string
+	opts   TextureOptions
+	pixels *Pixels
+	tex    *Texture
+	err    error
+	done   chan struct{}
+}
+
+//NewLoader 创建异步加载器, workers 为同时解码的图片数, 不大于0时为1
+func NewLoader(workers int) *Loader {
+	if workers <= 0 {
+		workers = 1
+	}
+	return &Loader{sem: make(chan struct{}, workers)}
+}
+
+//Load 在后台读取并解码图片文件, 立即返回; 纹理在之后的Upload中创建
+func (l *Loader) Load(file string, opts TextureOptions) *Pending {
+	return l.load(file, opts, loadImageFile)
+}
+
+//LoadFS 与Load相同, 但从文件系统fsys(如 embed.FS)中读取
+func (l *Loader) LoadFS(fsys fs.FS, file string, opts TextureOptions) *Pending {
+	return l.load(file, opts, func(file string) (image.Image, error) {
+		return loadImageFS(fsys, file)
+	})
+}
+
+func (l *Loader) load(file string, opts TextureOptions, open func(string) (image.Image, error)) *Pending {
+	p := &Pending{file: file, opts: opts, done: make(chan struct{})}
+	l.mu.Lock()
+	l.inFlight++
+	l.mu.Unlock()
+
+	go func() {
+		l.sem <- struct{}{}
+		img, err := open(file)
+		if err != nil {
+			p.err = err
+		} else {
+			p.pixels = PixelsFromImage(img)
+		}
+		<-l.sem
+
+		l.mu.Lock()
+		l.decoded = append(l.decoded, p)
+		l.mu.Unlock()
+	}()
+	return p
+}
+
+//Upload 上传已解码的图片, 必须在渲染线程调用
+//至少上传一张(如果有), 用时超过budget后停止, 剩下的留到下一帧; 返回本次就绪的个数
+func (l *Loader) Upload(budget time.Duration) int {
+	start := time.Now()
+	n := 0
+	for {
+		l.mu.Lock()
+		if len(l.decoded) == 0 {
+			l.mu.Unlock()
+			break
+		}
+		p := l.decoded[0]
+		l.decoded = l.decoded[1:]
+		l.mu.Unlock()
+
+		if p.err == nil {
+			p.tex, p.err = NewTextureFromPixels(p.pixels, p.opts)
+		}
+		p.pixels = nil
+		close(p.done)
+		n++
+
+		l.mu.Lock()
+		l.inFlight--
+		l.mu.Unlock()
+		if time.Since(start) >= budget {
+			break
+		}
+	}
+	return n
+}
+
+//Remaining 尚未就绪的纹理个数, 可用于显示加载进度
+func (l *Loader) Remaining() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight
+}
+
+//Ready 纹理是否已经上传完成或加载失败
+func (p *Pending) Ready() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+//Done 就绪时关闭的通道
+//上传发生在渲染线程的Upload中, 渲染线程不能阻塞等待它
+func (p *Pending) Done() <-chan struct{} {
+	return p.done
+}
+
+//Texture 就绪后返回纹理, 未就绪或加载失败时返回nil
+func (p *Pending) Texture() *Texture {
+	if !p.Ready() {
+		return nil
+	}
+	return p.tex
+}
+
+//Err 就绪后返回加载或上传的错误
+func (p *Pending) Err() error {
+	if !p.Ready() {
+		return nil
+	}
+	return p.err
+}
+
+//File 图片文件路径
+func (p *Pending) File() string {
+	return p.file
+}
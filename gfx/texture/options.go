@@ -0,0 +1,90 @@
+package texture
+
+import (
+	"errors"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+//TextureOptions 纹理的过滤、环绕与存储选项, 零值表示线性过滤、重复环绕、无多级渐远纹理、颜色按sRGB存储
+type TextureOptions struct {
+	MinFilter int32 // 缩小过滤, 如:gl.LINEAR_MIPMAP_LINEAR; 0时开启Mipmap为gl.LINEAR_MIPMAP_LINEAR, 否则为gl.LINEAR
+	MagFilter int32 // 放大过滤, 如:gl.NEAREST; 0时为gl.LINEAR
+
+	Anisotropy float32 // 各向异性过滤的倍数, 不大于1时不开启, 超过驱动上限时取上限
+
+	WrapS, WrapT, WrapR int32      // 各轴的环绕方式, 如:gl.CLAMP_TO_EDGE; 0时为gl.REPEAT
+	BorderColor         [4]float32 // 环绕方式为gl.CLAMP_TO_BORDER时的边框颜色
+
+	Mipmap bool // 上传后生成多级渐远纹理
+	Linear bool // 8位颜色数据是线性的(如法线贴图、粗糙度), 否则按sRGB存储, 采样时自动转换到线性空间
+}
+
+var errMipmapFilter = errors.New("texture: mipmap min filter requires Mipmap")
+
+//minFilter 缩小过滤方式, 未指定时按是否生成mipmap选择
+func (opts *TextureOptions) minFilter() (int32, error) {
+	switch opts.MinFilter {
+	case 0:
+		if opts.Mipmap {
+			return gl.LINEAR_MIPMAP_LINEAR, nil
+		}
+		return gl.LINEAR, nil
+	case gl.NEAREST_MIPMAP_NEAREST, gl.LINEAR_MIPMAP_NEAREST,
+		gl.NEAREST_MIPMAP_LINEAR, gl.LINEAR_MIPMAP_LINEAR:
+		// 没有多级渐远纹理时使用mipmap过滤, 纹理是不完整的, 采样结果为黑色
+		if !opts.Mipmap {
+			return 0, errMipmapFilter
+		}
+	}
+	return opts.MinFilter, nil
+}
+
+//apply 将过滤与环绕选项设置到当前绑定在target上的纹理
+func (opts *TextureOptions) apply(target uint32) error {
+	minFilter, err := opts.minFilter()
+	if err != nil {
+		return err
+	}
+	gl.TexParameteri(target, gl.TEXTURE_MIN_FILTER, minFilter)
+	gl.TexParameteri(target, gl.TEXTURE_MAG_FILTER, orDefault(opts.MagFilter, gl.LINEAR))
+
+	gl.TexParameteri(target, gl.TEXTURE_WRAP_S, orDefault(opts.WrapS, gl.REPEAT))
+	gl.TexParameteri(target, gl.TEXTURE_WRAP_T, orDefault(opts.WrapT, gl.REPEAT))
+	gl.TexParameteri(target, gl.TEXTURE_WRAP_R, orDefault(opts.WrapR, gl.REPEAT))
+	gl.TexParameterfv(target, gl.TEXTURE_BORDER_COLOR, &opts.BorderColor[0])
+
+	if opts.Anisotropy > 1 {
+		if max := maxAnisotropy(); max > 1 {
+			anisotropy := opts.Anisotropy
+			if anisotropy > max {
+				anisotropy = max
+			}
+			gl.TexParameterf(target, gl.TEXTURE_MAX_ANISOTROPY, anisotropy)
+		}
+	}
+	return nil
+}
+
+func orDefault(value, def int32) int32 {
+	if value == 0 {
+		return def
+	}
+	return value
+}
+
+//maxAnisotropy 驱动支持的各向异性过滤上限, 不支持时返回0
+//各向异性过滤在OpenGL 4.6之前是扩展, 先检查扩展列表, 避免无效枚举的错误
+func maxAnisotropy() float32 {
+	var count int32
+	gl.GetIntegerv(gl.NUM_EXTENSIONS, &count)
+	for i := int32(0); i < count; i++ {
+		ext := gl.GoStr(gl.GetStringi(gl.EXTENSIONS, uint32(i)))
+		if ext == "GL_EXT_texture_filter_anisotropic" || ext == "GL_ARB_texture_filter_anisotropic" {
+			var max float32
+			gl.GetFloatv(gl.MAX_TEXTURE_MAX_ANISOTROPY, &max)
+			return max
+		}
+	}
+	return 0
+}
@@ -0,0 +1,131 @@
+package texture
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+//Pixels 未压缩的像素数据, 按行存放, 第一行为图片最上面一行(与image.Image一致)
+type Pixels struct {
+	Width, Height int
+	Channels      int         // 通道数1~4, 分别对应R、RG、RGB、RGBA
+	Data          interface{} // []uint8、[]uint16 或 []float32, 长度为Width*Height*Channels
+	gray          bool        // 来自灰度图片, 采样时扩展为(r, r, r, 1)
+}
+
+//PixelsFromImage 按图片的类型取出像素, 不经过image.RGBA转换:
+//灰度图为单通道, jpeg为RGB三通道, 16位图片保留16位, 其他类型转换为8位RGBA
+//纹理使用非预乘的alpha, 有透明像素的image.RGBA/RGBA64先转换为NRGBA/NRGBA64
+func PixelsFromImage(img image.Image) *Pixels {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	switch m := img.(type) {
+	case *image.Gray:
+		return &Pixels{Width: w, Height: h, Channels: 1, Data: packRows(m.Pix, m.Stride, w, h), gray: true}
+	case *image.Gray16:
+		return &Pixels{Width: w, Height: h, Channels: 1, Data: bigEndian16(m.Pix, m.Stride, w*2, h), gray: true}
+	case *image.NRGBA:
+		return &Pixels{Width: w, Height: h, Channels: 4, Data: packRows(m.Pix, m.Stride, w*4, h)}
+	case *image.RGBA:
+		if m.Opaque() {
+			return &Pixels{Width: w, Height: h, Channels: 4, Data: packRows(m.Pix, m.Stride, w*4, h)}
+		}
+	case *image.NRGBA64:
+		return &Pixels{Width: w, Height: h, Channels: 4, Data: bigEndian16(m.Pix, m.Stride, w*8, h)}
+	case *image.RGBA64:
+		if m.Opaque() {
+			return &Pixels{Width: w, Height: h, Channels: 4, Data: bigEndian16(m.Pix, m.Stride, w*8, h)}
+		}
+		nrgba := image.NewNRGBA64(image.Rect(0, 0, w, h))
+		draw.Draw(nrgba, nrgba.Bounds(), img, b.Min, draw.Src)
+		return &Pixels{Width: w, Height: h, Channels: 4, Data: bigEndian16(nrgba.Pix, nrgba.Stride, w*8, h)}
+	case *image.YCbCr:
+		pix := make([]uint8, 0, w*h*3)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				yi, ci := m.YOffset(x, y), m.COffset(x, y)
+				r, g, b := color.YCbCrToRGB(m.Y[yi], m.Cb[ci], m.Cr[ci])
+				pix = append(pix, r, g, b)
+			}
+		}
+		return &Pixels{Width: w, Height: h, Channels: 3, Data: pix}
+	}
+	nrgba := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(nrgba, nrgba.Bounds(), img, b.Min, draw.Src)
+	return &Pixels{Width: w, Height: h, Channels: 4, Data: nrgba.Pix}
+}
+
+//expandGray 8位灰度按sRGB上传时扩展为RGB三通道: 单通道没有sRGB内部格式,
+//用R8会把灰度当作线性值, 与同一张图片的RGB版本亮度不一致
+//其他像素原样返回
+func (p *Pixels) expandGray(linear bool) *Pixels {
+	gray, ok := p.Data.([]uint8)
+	if !p.gray || linear || !ok {
+		return p
+	}
+	rgb := make([]uint8, 0, len(gray)*3)
+	for _, v := range gray {
+		rgb = append(rgb, v, v, v)
+	}
+	return &Pixels{Width: p.Width, Height: p.Height, Channels: 3, Data: rgb}
+}
+
+//packRows 去掉行尾的填充, 每行rowBytes字节
+func packRows(pix []uint8, stride, rowBytes, h int) []uint8 {
+	if stride == rowBytes {
+		return pix[:rowBytes*h]
+	}
+	out := make([]uint8, 0, rowBytes*h)
+	for y := 0; y < h; y++ {
+		out = append(out, pix[y*stride:y*stride+rowBytes]...)
+	}
+	return out
+}
+
+//bigEndian16 image包中16位通道为大端序, 转换为本机的uint16
+func bigEndian16(pix []uint8, stride, rowBytes, h int) []uint16 {
+	out := make([]uint16, 0, rowBytes/2*h)
+	for y := 0; y < h; y++ {
+		row := pix[y*stride : y*stride+rowBytes]
+		for i := 0; i < len(row); i += 2 {
+			out = append(out, uint16(row[i])<<8|uint16(row[i+1]))
+		}
+	}
+	return out
+}
+
+//format 按通道数和数据类型选择内部格式、像素格式和数据类型
+//8位的RGB/RGBA颜色除非linear为true, 否则使用sRGB内部格式
+func (p *Pixels) format(linear bool) (internalFmt int32, format, pixType uint32, err error) {
+	if p.Channels < 1 || p.Channels > 4 {
+		return 0, 0, 0, fmt.Errorf("texture: unsupported channel count %d", p.Channels)
+	}
+	format = [...]uint32{gl.RED, gl.RG, gl.RGB, gl.RGBA}[p.Channels-1]
+
+	var length int
+	switch data := p.Data.(type) {
+	case []uint8:
+		length, pixType = len(data), gl.UNSIGNED_BYTE
+		internalFmt = [...]int32{gl.R8, gl.RG8, gl.SRGB8, gl.SRGB8_ALPHA8}[p.Channels-1]
+		if linear {
+			internalFmt = [...]int32{gl.R8, gl.RG8, gl.RGB8, gl.RGBA8}[p.Channels-1]
+		}
+	case []uint16:
+		length, pixType = len(data), gl.UNSIGNED_SHORT
+		internalFmt = [...]int32{gl.R16, gl.RG16, gl.RGB16, gl.RGBA16}[p.Channels-1]
+	case []float32:
+		length, pixType = len(data), gl.FLOAT
+		internalFmt = [...]int32{gl.R32F, gl.RG32F, gl.RGB32F, gl.RGBA32F}[p.Channels-1]
+	default:
+		return 0, 0, 0, fmt.Errorf("texture: unsupported pixel data %T", p.Data)
+	}
+	if length != p.Width*p.Height*p.Channels {
+		return 0, 0, 0, fmt.Errorf("texture: %d values for %dx%d pixels with %d channels",
+			length, p.Width, p.Height, p.Channels)
+	}
+	return internalFmt, format, pixType, nil
+}
@@ -0,0 +1,61 @@
+package texture
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+//TestPremultiplied 有透明像素的RGBA/RGBA64转换为非预乘的颜色
+func TestPremultiplied(t *testing.T) {
+	rgba := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	rgba.Set(0, 0, color.RGBA{100, 50, 0, 255})
+	rgba.Set(1, 0, color.RGBA{100, 50, 0, 128})
+	p := PixelsFromImage(rgba)
+	want := []uint8{100, 50, 0, 255, 199, 99, 0, 128}
+	if got := p.Data.([]uint8); string(got) != string(want) {
+		t.Errorf("RGBA: %v, want %v", got, want)
+	}
+
+	opaque := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	opaque.Set(0, 0, color.RGBA{10, 20, 30, 255})
+	if got := PixelsFromImage(opaque).Data.([]uint8); string(got) != string([]uint8{10, 20, 30, 255}) {
+		t.Errorf("opaque RGBA: %v", got)
+	}
+
+	rgba64 := image.NewRGBA64(image.Rect(0, 0, 1, 1))
+	rgba64.Set(0, 0, color.RGBA64{0x4000, 0x2000, 0, 0x8000})
+	got := PixelsFromImage(rgba64).Data.([]uint16)
+	if len(got) != 4 || got[0] != 0x7fff || got[1] != 0x3fff || got[2] != 0 || got[3] != 0x8000 {
+		t.Errorf("RGBA64: %#x", got)
+	}
+}
+
+//TestExpandGray 8位灰度按sRGB上传时扩展为RGB, 线性与16位灰度保持单通道
+func TestExpandGray(t *testing.T) {
+	gray := image.NewGray(image.Rect(0, 0, 2, 1))
+	gray.Pix = []uint8{7, 200}
+	p := PixelsFromImage(gray)
+
+	srgb := p.expandGray(false)
+	internalFmt, _, _, err := srgb.format(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if srgb.Channels != 3 || srgb.gray || string(srgb.Data.([]uint8)) != string([]uint8{7, 7, 7, 200, 200, 200}) {
+		t.Errorf("sRGB gray: %d channels %v", srgb.Channels, srgb.Data)
+	}
+	if internalFmt != gl.SRGB8 {
+		t.Errorf("sRGB gray: internal format %#x, want SRGB8", internalFmt)
+	}
+
+	if linear := p.expandGray(true); linear != p {
+		t.Errorf("linear gray was expanded to %d channels", linear.Channels)
+	}
+	gray16 := PixelsFromImage(image.NewGray16(image.Rect(0, 0, 2, 1)))
+	if gray16.expandGray(false) != gray16 {
+		t.Error("16-bit gray was expanded")
+	}
+}
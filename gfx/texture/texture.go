@@ -0,0 +1,149 @@
+package texture
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+//Texture 二维纹理对象
+type Texture struct {
+	handle  uint32
+	target  uint32 // same target as gl.BindTexture(<this param>, ...)
+	texUnit uint32 // Texture unit that is currently bound to ex: gl.TEXTURE0
+}
+
+var errTextureNotBound = errors.New("texture not bound")
+
+//NewTextureFromFile 从图片文件(png/jpeg)创建纹理
+func NewTextureFromFile(file string, opts TextureOptions) (*Texture, error) {
+	img, err := loadImageFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return NewTexture(img, opts)
+}
+
+//NewTextureFromFS 从文件系统fsys中的图片文件(png/jpeg)创建纹理, 如 embed.FS
+func NewTextureFromFS(fsys fs.FS, file string, opts TextureOptions) (*Texture, error) {
+	img, err := loadImageFS(fsys, file)
+	if err != nil {
+		return nil, err
+	}
+	return NewTexture(img, opts)
+}
+
+//NewTexture 从image.Image创建纹理, 内部格式由图片类型决定, 见PixelsFromImage
+func NewTexture(img image.Image, opts TextureOptions) (*Texture, error) {
+	return NewTextureFromPixels(PixelsFromImage(img), opts)
+}
+
+//NewTextureFromPixels 从像素数据创建纹理
+//内部格式按通道数与数据类型选择: 8位、16位或32位浮点, 8位颜色按opts.Linear选择sRGB或线性
+//8位灰度图片不是线性时扩展为SRGB8
+func NewTextureFromPixels(p *Pixels, opts TextureOptions) (*Texture, error) {
+	p = p.expandGray(opts.Linear)
+	internalFmt, format, pixType, err := p.format(opts.Linear)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := opts.minFilter(); err != nil {
+		return nil, err
+	}
+
+	var handle uint32
+	gl.GenTextures(1, &handle)
+
+	texture := Texture{
+		handle: handle,
+		target: gl.TEXTURE_2D,
+	}
+
+	texture.Bind(gl.TEXTURE0)
+	defer texture.UnBind()
+
+	// set the texture wrapping/filtering options (applies to current bound texture obj)
+	if err := opts.apply(texture.target); err != nil {
+		texture.Delete()
+		return nil, err
+	}
+	if p.gray {
+		swizzle := [4]int32{gl.RED, gl.RED, gl.RED, gl.ONE}
+		gl.TexParameteriv(texture.target, gl.TEXTURE_SWIZZLE_RGBA, &swizzle[0])
+	}
+
+	// RGB等格式的行长度不一定是4字节的倍数
+	var alignment int32
+	gl.GetIntegerv(gl.UNPACK_ALIGNMENT, &alignment)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	gl.TexImage2D(texture.target, 0, internalFmt, int32(p.Width), int32(p.Height), 0, format, pixType, gl.Ptr(p.Data))
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, alignment)
+
+	if opts.Mipmap {
+		gl.GenerateMipmap(texture.target)
+	}
+
+	return &texture, nil
+}
+
+//Bind 将纹理绑定到纹理单元texUnit,如:gl.TEXTURE0
+func (tex *Texture) Bind(texUnit uint32) {
+	gl.ActiveTexture(texUnit)
+	gl.BindTexture(tex.target, tex.handle)
+	tex.texUnit = texUnit
+}
+
+//UnBind 解绑纹理
+func (tex *Texture) UnBind() {
+	tex.texUnit = 0
+	gl.BindTexture(tex.target, 0)
+}
+
+//SetUniform 将当前绑定的纹理单元赋值给采样器uniform
+func (tex *Texture) SetUniform(uniformLoc int32) error {
+	if tex.texUnit == 0 {
+		return errTextureNotBound
+	}
+	gl.Uniform1i(uniformLoc, int32(tex.texUnit-gl.TEXTURE0))
+	return nil
+}
+
+//Delete 删除纹理对象
+func (tex *Texture) Delete() {
+	gl.DeleteTextures(1, &tex.handle)
+}
+
+func loadImageFile(file string) (image.Image, error) {
+	infile, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer infile.Close()
+	return decodeImage(infile, file)
+}
+
+func loadImageFS(fsys fs.FS, file string) (image.Image, error) {
+	infile, err := fsys.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer infile.Close()
+	return decodeImage(infile, file)
+}
+
+func decodeImage(r io.Reader, file string) (image.Image, error) {
+	// Decode automatically figures out the type of immage in the file
+	// as long as its image/<type> is imported
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("load texture file %s: %v", file, err)
+	}
+	return img, nil
+}
@@ -0,0 +1,116 @@
+/*
+uniform缓冲对象(UBO)
+由Go结构体按std140布局生成缓冲, 绑定到绑定点后可被多个着色器程序共享,
+例如相机的view/projection每帧只需上传一次
+*/
+
+package gfx
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+//CameraBinding 相机uniform块使用的绑定点
+const CameraBinding = 0
+
+//CameraBlock 相机的uniform块, 对应GLSL:
+//	layout (std140) uniform Camera {
+//		mat4 view;
+//		mat4 projection;
+//	};
+type CameraBlock struct {
+	View       mgl32.Mat4
+	Projection mgl32.Mat4
+}
+
+//UniformBuffer std140布局的uniform缓冲
+type UniformBuffer struct {
+	handle  uint32
+	binding uint32
+	typ     reflect.Type
+	data    []byte
+}
+
+//NewUniformBuffer 按block的类型创建uniform缓冲, 上传block作为初始数据并绑定到binding
+//block 为结构体或结构体指针, 字段类型见std140Layout, 大小为0的结构体返回错误
+func NewUniformBuffer(binding uint32, block interface{}) (*UniformBuffer, error) {
+	v := reflect.Indirect(reflect.ValueOf(block))
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("uniform buffer: block must be a struct, got %T", block)
+	}
+	_, size, err := std140Layout(v.Type())
+	if err != nil {
+		return nil, fmt.Errorf("uniform buffer: %v", err)
+	}
+	if size == 0 {
+		return nil, fmt.Errorf("uniform buffer: %s has no data", v.Type())
+	}
+
+	ub := &UniformBuffer{binding: binding, typ: v.Type(), data: make([]byte, size)}
+	putStd140(ub.data, 0, v)
+	gl.GenBuffers(1, &ub.handle)
+	gl.BindBuffer(gl.UNIFORM_BUFFER, ub.handle)
+	gl.BufferData(gl.UNIFORM_BUFFER, size, gl.Ptr(ub.data), gl.DYNAMIC_DRAW)
+	gl.BindBuffer(gl.UNIFORM_BUFFER, 0)
+	gl.BindBufferBase(gl.UNIFORM_BUFFER, binding, ub.handle)
+	return ub, nil
+}
+
+//Update 编码block并整体上传, block的类型与创建时不同时返回错误, 缓冲保持不变
+func (ub *UniformBuffer) Update(block interface{}) error {
+	v := reflect.Indirect(reflect.ValueOf(block))
+	if !v.IsValid() || v.Type() != ub.typ {
+		return fmt.Errorf("uniform buffer: update with %T, created with %s", block, ub.typ)
+	}
+	putStd140(ub.data, 0, v)
+	gl.BindBuffer(gl.UNIFORM_BUFFER, ub.handle)
+	gl.BufferSubData(gl.UNIFORM_BUFFER, 0, len(ub.data), gl.Ptr(ub.data))
+	gl.BindBuffer(gl.UNIFORM_BUFFER, 0)
+	return nil
+}
+
+//Binding 缓冲绑定的绑定点
+func (ub *UniformBuffer) Binding() uint32 {
+	return ub.binding
+}
+
+//Size 缓冲的字节数
+func (ub *UniformBuffer) Size() int {
+	return len(ub.data)
+}
+
+//Delete 删除缓冲
+func (ub *UniformBuffer) Delete() {
+	gl.DeleteBuffers(1, &ub.handle)
+}
+
+//BindUniformBlock 将着色器程序中名为name的uniform块连接到ub的绑定点
+//着色器中的块比ub大时返回错误(比ub小是允许的, 多出的部分不会被读取); 重新加载着色器后绑定会自动恢复
+func (prog *Program) BindUniformBlock(name string, ub *UniformBuffer) error {
+	if err := prog.bindUniformBlock(name, ub.binding, ub.Size()); err != nil {
+		return err
+	}
+	if prog.blocks == nil {
+		prog.blocks = make(map[string]*UniformBuffer)
+	}
+	prog.blocks[name] = ub
+	return nil
+}
+
+func (prog *Program) bindUniformBlock(name string, binding uint32, size int) error {
+	index := gl.GetUniformBlockIndex(prog.handle, gl.Str(name+"\x00"))
+	if index == gl.INVALID_INDEX {
+		return fmt.Errorf("uniform block %s is not active in program", name)
+	}
+	var blockSize int32
+	gl.GetActiveUniformBlockiv(prog.handle, index, gl.UNIFORM_BLOCK_DATA_SIZE, &blockSize)
+	if int(blockSize) > size {
+		return fmt.Errorf("uniform block %s is %d bytes in shader, %d bytes in buffer", name, blockSize, size)
+	}
+	gl.UniformBlockBinding(prog.handle, index, binding)
+	return nil
+}
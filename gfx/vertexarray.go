@@ -0,0 +1,80 @@
+/*
+网格对象
+持有VAO、VBO与EBO, 记录绘制方式和顶点(或索引)个数
+*/
+
+package gfx
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+//Mesh 上传到显存的网格
+type Mesh struct {
+	vao, vbo, ebo uint32
+	mode          uint32 // 如:gl.TRIANGLES
+	count         int32  // 有索引时为索引个数, 否则为顶点个数
+}
+
+//NewMesh 上传顶点数据并按layout设置顶点属性
+//indices 为空时使用 gl.DrawArrays 绘制, 否则使用 gl.DrawElements
+//vertices 为空时不上传数据, 得到的网格不绘制任何图元; layout 的步长为0时返回错误
+func NewMesh(mode uint32, layout *VertexLayout, vertices []float32, indices []uint32) (*Mesh, error) {
+	if layout.Stride() == 0 {
+		return nil, fmt.Errorf("mesh: vertex layout has zero stride")
+	}
+	m := &Mesh{mode: mode}
+	gl.GenVertexArrays(1, &m.vao)
+	gl.BindVertexArray(m.vao)
+
+	// gl.Ptr 不接受空切片
+	gl.GenBuffers(1, &m.vbo)
+	gl.BindBuffer(gl.ARRAY_BUFFER, m.vbo)
+	if len(vertices) > 0 {
+		gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
+	}
+
+	if len(indices) > 0 {
+		gl.GenBuffers(1, &m.ebo)
+		gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, m.ebo)
+		gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, gl.Ptr(indices), gl.STATIC_DRAW)
+		m.count = int32(len(indices))
+	} else {
+		m.count = int32(len(vertices) * 4 / int(layout.Stride()))
+	}
+
+	layout.Apply()
+
+	// 先解绑VAO, 否则解绑EBO会被记录到VAO中
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0)
+	return m, nil
+}
+
+//Count 每次绘制的索引个数(无索引时为顶点个数)
+func (m *Mesh) Count() int32 {
+	return m.count
+}
+
+//Draw 绑定VAO并绘制整个网格
+func (m *Mesh) Draw() {
+	gl.BindVertexArray(m.vao)
+	if m.ebo != 0 {
+		gl.DrawElements(m.mode, m.count, gl.UNSIGNED_INT, gl.PtrOffset(0))
+	} else {
+		gl.DrawArrays(m.mode, 0, m.count)
+	}
+	gl.BindVertexArray(0)
+}
+
+//Delete 删除VAO、VBO与EBO
+func (m *Mesh) Delete() {
+	gl.DeleteVertexArrays(1, &m.vao)
+	gl.DeleteBuffers(1, &m.vbo)
+	if m.ebo != 0 {
+		gl.DeleteBuffers(1, &m.ebo)
+	}
+}
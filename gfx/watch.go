@@ -0,0 +1,147 @@
+/*
+着色器热重载
+后台goroutine轮询源码文件的修改时间, 渲染线程每帧调用Update,
+在渲染线程上重新编译链接, 成功后原地替换程序句柄, 失败时保留旧程序并记录错误
+从fs.FS加载的源码通过fs.Stat检查, embed.FS中的文件修改时间不变, 不会触发重新加载
+*/
+
+package gfx
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+var errNoShaderFiles = errors.New("program has no shaders loaded from files")
+
+//ShaderWatcher 监视着色器程序的源码文件
+type ShaderWatcher struct {
+	mu       sync.Mutex
+	programs []*watchedProgram
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+type watchedProgram struct {
+	prog    *Program
+	files   []sourceFile
+	modTime []time.Time // 与files一一对应
+	dirty   bool
+}
+
+//sourceFile 着色器的一个源码文件, fsys为nil时为操作系统的文件
+type sourceFile struct {
+	fsys fs.FS
+	name string
+}
+
+func (f sourceFile) stat() (fs.FileInfo, error) {
+	if f.fsys == nil {
+		return os.Stat(f.name)
+	}
+	return fs.Stat(f.fsys, f.name)
+}
+
+func (f sourceFile) String() string {
+	return f.name
+}
+
+//NewShaderWatcher 创建监视器, 每隔interval检查一次文件修改时间
+func NewShaderWatcher(interval time.Duration) *ShaderWatcher {
+	w := &ShaderWatcher{done: make(chan struct{})}
+	go w.poll(interval)
+	return w
+}
+
+//Watch 监视由文件创建的着色器程序
+func (w *ShaderWatcher) Watch(prog *Program) error {
+	files := prog.sourceFiles()
+	if len(files) == 0 {
+		return errNoShaderFiles
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.programs = append(w.programs, &watchedProgram{prog: prog, files: files, modTime: modTimes(files)})
+	return nil
+}
+
+//Update 重新加载源码发生变化的程序, 必须在渲染线程(持有OpenGL上下文的线程)调用
+//返回是否有程序被替换; 新程序中的uniform为默认值, 只设置一次的uniform需要重新设置
+func (w *ShaderWatcher) Update() bool {
+	w.mu.Lock()
+	var dirty []*watchedProgram
+	for _, wp := range w.programs {
+		if wp.dirty {
+			wp.dirty = false
+			dirty = append(dirty, wp)
+		}
+	}
+	w.mu.Unlock()
+
+	reloaded := false
+	for _, wp := range dirty {
+		if err := wp.prog.reload(); err != nil {
+			if shaderErr, ok := err.(*ShaderError); ok {
+				log.Printf("shader reload failed, keeping previous program:\n%s", shaderErr.Snippet())
+			} else {
+				log.Printf("shader reload failed, keeping previous program: %v", err)
+			}
+			continue
+		}
+		// 重新记录文件列表, 源码依赖的文件可能已经变化
+		files := wp.prog.sourceFiles()
+		log.Printf("shader reloaded: %v", files)
+		reloaded = true
+		modTime := modTimes(files)
+		w.mu.Lock()
+		wp.files, wp.modTime = files, modTime
+		w.mu.Unlock()
+	}
+	return reloaded
+}
+
+//Close 停止轮询
+func (w *ShaderWatcher) Close() {
+	w.stopOnce.Do(func() { close(w.done) })
+}
+
+func (w *ShaderWatcher) poll(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		}
+		w.mu.Lock()
+		for _, wp := range w.programs {
+			for i, file := range wp.files {
+				info, err := file.stat()
+				if err != nil {
+					continue // 编辑器保存时文件可能短暂不存在
+				}
+				if !info.ModTime().Equal(wp.modTime[i]) {
+					wp.modTime[i] = info.ModTime()
+					wp.dirty = true
+				}
+			}
+		}
+		w.mu.Unlock()
+	}
+}
+
+//modTimes 记录各文件当前的修改时间, 无法访问的文件记为零值
+func modTimes(files []sourceFile) []time.Time {
+	times := make([]time.Time, len(files))
+	for i, file := range files {
+		if info, err := file.stat(); err == nil {
+			times[i] = info.ModTime()
+		}
+	}
+	return times
+}
@@ -0,0 +1,105 @@
+package golden
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx/atlas"
+	"gfx/soft"
+)
+
+//atlasImages 打包进图集的例程图片, 尺寸各不相同
+var atlasImages = []struct {
+	name, file string
+}{
+	{"crate", "../textures/images/RTS_Crate.png"},
+	{"container", "../textures/images/container2.png"},
+	{"troll", "../textures/images/trollface-transparent.png"},
+	{"wall", "../cube/src/1.png"},
+	{"face", "../cube/src/2.png"},
+	{"checker", "../gfx/gltf/testdata/checker.png"},
+}
+
+//renderAtlas 打包例程图片, 左侧绘制整张图集, 右侧按名字查询纹理坐标绘制每个精灵,
+//所有四边形放在同一个顶点缓冲中一次绘制
+func renderAtlas(ctx *soft.Context, width, height int) error {
+	builder := atlas.NewBuilder(2, 2)
+	for _, src := range atlasImages {
+		img, err := decodeImageFile(src.file)
+		if err != nil {
+			return err
+		}
+		if err := builder.Add(src.name, img); err != nil {
+			return err
+		}
+	}
+	a, err := builder.Build()
+	if err != nil {
+		return err
+	}
+	tex := soft.NewTexture(a.Image, true)
+
+	// 每个顶点: 屏幕像素坐标(x, y) + 纹理坐标(u, v)
+	var vertices []float32
+	var indices []uint32
+	quad := func(x0, y0, x1, y1 float32, uvMin, uvMax mgl32.Vec2) {
+		base := uint32(len(vertices) / 4)
+		vertices = append(vertices,
+			x0, y0, uvMin.X(), uvMin.Y(),
+			x1, y0, uvMax.X(), uvMin.Y(),
+			x1, y1, uvMax.X(), uvMax.Y(),
+			x0, y1, uvMin.X(), uvMax.Y(),
+		)
+		indices = append(indices, base, base+2, base+1, base, base+3, base+2)
+	}
+
+	// 整张图集, 保持宽高比
+	aw, ah := float32(a.Image.Rect.Dx()), float32(a.Image.Rect.Dy())
+	scale := 260 / aw
+	if ah > aw {
+		scale = 260 / ah
+	}
+	quad(20, 70, 20+aw*scale, 70+ah*scale, mgl32.Vec2{0, 0}, mgl32.Vec2{1, 1})
+	// 各精灵排成3列
+	for i, r := range a.Regions() {
+		x := float32(310 + (i%3)*95)
+		y := float32(70 + (i/3)*130)
+		quad(x, y, x+85, y+85, r.UVMin, r.UVMax)
+	}
+
+	background := mgl32.Vec3{0.2, 0.3, 0.3}
+	program := &soft.Program{
+		Varyings: 2,
+		Vertex: func(in []mgl32.Vec4, out []float32) mgl32.Vec4 {
+			out[0], out[1] = in[1].X(), in[1].Y()
+			return mgl32.Vec4{in[0].X()/float32(width)*2 - 1, 1 - in[0].Y()/float32(height)*2, 0, 1}
+		},
+		Fragment: func(in []float32) mgl32.Vec4 {
+			c := tex.Sample(in[0], in[1])
+			return background.Mul(1 - c.W()).Add(c.Vec3().Mul(c.W())).Vec4(1)
+		},
+	}
+
+	ctx.ClearColor(background.X(), background.Y(), background.Z(), 1.0)
+	ctx.Clear(soft.COLOR_BUFFER_BIT)
+	VAO := ctx.GenVertexArray()
+	VBO := ctx.GenBuffer()
+	EBO := ctx.GenBuffer()
+	ctx.BindVertexArray(VAO)
+	ctx.BindBuffer(soft.ARRAY_BUFFER, VBO)
+	ctx.BufferData(soft.ARRAY_BUFFER, vertices)
+	ctx.BindBuffer(soft.ELEMENT_ARRAY_BUFFER, EBO)
+	ctx.BufferData(soft.ELEMENT_ARRAY_BUFFER, indices)
+	ctx.VertexAttribPointer(0, 2, 4*4, 0)
+	ctx.EnableVertexAttribArray(0)
+	ctx.VertexAttribPointer(1, 2, 4*4, 2*4)
+	ctx.EnableVertexAttribArray(1)
+
+	ctx.UseProgram(program)
+	ctx.DrawElements(soft.TRIANGLES, int32(len(indices)), 0)
+
+	ctx.BindVertexArray(0)
+	ctx.DeleteVertexArray(VAO)
+	ctx.DeleteBuffer(VBO)
+	ctx.DeleteBuffer(EBO)
+	return nil
+}
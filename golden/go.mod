@@ -0,0 +1,30 @@
+module golden
+
+go 1.13
+
+require (
+	camera v0.0.0
+	cube v0.0.0
+	gfx v0.0.0
+	github.com/go-gl/mathgl v0.0.0-20190713194549-592312d8590a
+	quadrangle v0.0.0
+	sphere v0.0.0
+	textures v0.0.0
+	triangle v0.0.0
+	triangle2 v0.0.0
+	triangle3 v0.0.0
+	window v0.0.0
+)
+
+replace (
+	camera => ../camera
+	cube => ../cube
+	gfx => ../gfx
+	quadrangle => ../quadrangle
+	sphere => ../sphere
+	textures => ../textures
+	triangle => ../triangle
+	triangle2 => ../triangle2
+	triangle3 => ../triangle3
+	window => ../window
+)
@@ -0,0 +1,40 @@
+package golden
+
+import (
+	"flag"
+	"testing"
+
+	"gfx/golden"
+	"gfx/soft"
+)
+
+var (
+	update    = flag.Bool("update", false, "重新生成黄金图像")
+	dir       = flag.String("dir", "testdata", "黄金图像所在目录")
+	tolerance = flag.Uint("tolerance", 2, "每个颜色通道允许的最大差值")
+)
+
+//TestGolden 每个场景一个子测试, 与testdata中的黄金图像比较或在-update时覆盖它
+func TestGolden(t *testing.T) {
+	for _, s := range scenes {
+		s := s
+		t.Run(s.name, func(t *testing.T) {
+			ctx := soft.NewContext(s.width, s.height)
+			err := s.render(ctx)
+			if err == nil {
+				err = ctx.Err()
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *update {
+				err = golden.Update(*dir, s.name, ctx.Image())
+			} else {
+				err = golden.Check(*dir, s.name, ctx.Image(), uint8(*tolerance))
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
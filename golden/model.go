@@ -0,0 +1,127 @@
+package golden
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx/gltf"
+	"gfx/mesh"
+	"gfx/obj"
+	"gfx/soft"
+)
+
+//模型场景共用的摄像机与光照
+var (
+	modelEye      = mgl32.Vec3{0.0, 1.5, 4.5}
+	modelLightDir = mgl32.Vec3{0.4, 0.8, 0.6}.Normalize()
+)
+
+//renderOBJ 用漫反射光照绘制OBJ模型, 每个Part使用其材质的Kd颜色
+//用于检查加载器的三角化、法线与材质分配
+func renderOBJ(ctx *soft.Context, model *obj.Model, width, height int) {
+	viewProjection := beginModelScene(ctx, width, height)
+	for _, part := range model.Parts {
+		diffuse := mgl32.Vec3{1.0, 1.0, 1.0}
+		if m, ok := model.Materials[part.Material]; ok {
+			diffuse = m.Diffuse
+		}
+		color := diffuse.Vec4(1.0)
+		drawGeometry(ctx, &part.Geometry, viewProjection, mgl32.Ident4(), func(uv mgl32.Vec2) mgl32.Vec4 {
+			return color
+		})
+	}
+}
+
+//renderGLTF 遍历glTF默认场景, 按节点的世界变换绘制每个图元
+//颜色为 baseColorFactor 与 baseColorTexture 的乘积
+func renderGLTF(ctx *soft.Context, model *gltf.Model, width, height int) error {
+	viewProjection := beginModelScene(ctx, width, height)
+	textures := make(map[*gltf.Texture]*soft.Texture)
+	var err error
+	model.Scene.Walk(func(node *gltf.Node, world mgl32.Mat4) {
+		if node.Mesh == nil || err != nil {
+			return
+		}
+		for _, prim := range node.Mesh.Primitives {
+			factor := mgl32.Vec4{1.0, 1.0, 1.0, 1.0}
+			var tex *soft.Texture
+			if m := prim.Material; m != nil {
+				factor = m.BaseColorFactor
+				if ref := m.BaseColorTexture; ref != nil {
+					if tex = textures[ref.Texture]; tex == nil {
+						img, e := ref.Image.Decode()
+						if e != nil {
+							err = e
+							return
+						}
+						tex = soft.NewTexture(img, true)
+						textures[ref.Texture] = tex
+					}
+				}
+			}
+			drawGeometry(ctx, &prim.Geometry, viewProjection, world, func(uv mgl32.Vec2) mgl32.Vec4 {
+				if tex == nil {
+					return factor
+				}
+				c := tex.Sample(uv.X(), uv.Y())
+				return mgl32.Vec4{c[0] * factor[0], c[1] * factor[1], c[2] * factor[2], c[3] * factor[3]}
+			})
+		}
+	})
+	return err
+}
+
+//beginModelScene 清屏并返回观察-投影矩阵
+func beginModelScene(ctx *soft.Context, width, height int) mgl32.Mat4 {
+	ctx.ClearColor(0.0, 0.34, 0.57, 1.0)
+	ctx.Clear(soft.COLOR_BUFFER_BIT | soft.DEPTH_BUFFER_BIT)
+	ctx.Enable(soft.DEPTH_TEST)
+	ctx.Enable(soft.CULL_FACE)
+	projection := mgl32.Perspective(mgl32.DegToRad(45.0), float32(width)/float32(height), 0.1, 100.0)
+	view := mgl32.LookAtV(modelEye, mgl32.Vec3{0.0, 0.0, 0.0}, mgl32.Vec3{0.0, 1.0, 0.0})
+	return projection.Mul4(view)
+}
+
+//drawGeometry 以漫反射光照绘制一个网格, baseColor 根据纹理坐标给出表面颜色
+func drawGeometry(ctx *soft.Context, g *mesh.Geometry, viewProjection, model mgl32.Mat4, baseColor func(uv mgl32.Vec2) mgl32.Vec4) {
+	normalMatrix := model.Mat3().Inv().Transpose()
+	program := &soft.Program{
+		Varyings: 5,
+		Vertex: func(in []mgl32.Vec4, out []float32) mgl32.Vec4 {
+			normal := normalMatrix.Mul3x1(in[1].Vec3())
+			copy(out, normal[:])
+			out[3], out[4] = in[2].X(), in[2].Y()
+			return viewProjection.Mul4(model).Mul4x1(in[0].Vec3().Vec4(1.0))
+		},
+		Fragment: func(in []float32) mgl32.Vec4 {
+			normal := mgl32.Vec3{in[0], in[1], in[2]}.Normalize()
+			light := 0.2 + 0.8*mgl32.Clamp(normal.Dot(modelLightDir), 0.0, 1.0)
+			c := baseColor(mgl32.Vec2{in[3], in[4]})
+			return c.Vec3().Mul(light).Vec4(c.W())
+		},
+	}
+
+	attribs := mesh.Position | mesh.Normal | mesh.UV
+	stride := attribs.Stride() * 4
+	VAO := ctx.GenVertexArray()
+	VBO := ctx.GenBuffer()
+	EBO := ctx.GenBuffer()
+	ctx.BindVertexArray(VAO)
+	ctx.BindBuffer(soft.ARRAY_BUFFER, VBO)
+	ctx.BufferData(soft.ARRAY_BUFFER, g.Interleave(attribs))
+	ctx.BindBuffer(soft.ELEMENT_ARRAY_BUFFER, EBO)
+	ctx.BufferData(soft.ELEMENT_ARRAY_BUFFER, g.Indices)
+	ctx.VertexAttribPointer(0, 3, stride, 0)
+	ctx.EnableVertexAttribArray(0)
+	ctx.VertexAttribPointer(1, 3, stride, 3*4)
+	ctx.EnableVertexAttribArray(1)
+	ctx.VertexAttribPointer(2, 2, stride, 6*4)
+	ctx.EnableVertexAttribArray(2)
+
+	ctx.UseProgram(program)
+	ctx.DrawElements(soft.TRIANGLES, int32(len(g.Indices)), 0)
+
+	ctx.BindVertexArray(0)
+	ctx.DeleteVertexArray(VAO)
+	ctx.DeleteBuffer(VBO)
+	ctx.DeleteBuffer(EBO)
+}
@@ -0,0 +1,143 @@
+/***
+ * 黄金图像回归检查
+ * 用软件光栅化离屏渲染每个例程的一帧,与testdata中提交的PNG逐像素比较,
+ * 不一致时在testdata中写出 <场景>.got.png 与 <场景>.diff.png
+ * 覆盖范围:
+ *   - 例程scene包中的顶点数据、索引、变换矩阵与摄像机, 与main.go共用同一份
+ *   - 顶点属性的步长、偏移与绘制个数: 由main.go使用的scene.Layout通过gfx.VertexLayout.ApplySoft设置,
+ *     与gfx.NewMesh中的VertexLayout.Apply计算方式相同
+ *   - obj、gltf、glb、atlas场景中gfx/obj、gfx/gltf与gfx/atlas的加载结果
+ *   - gfx/soft的光栅化与纹理采样本身
+ * 不覆盖:
+ *   - GLSL着色器: 每个场景用Go函数(soft.Program)重写了对应的着色器, 修改.vs/.fs或main.go中的着色器源码不会改变黄金图像
+ *   - 需要OpenGL上下文的代码: gfx.NewMesh的缓冲上传、VertexLayout.Apply中的gl调用、着色器编译与uniform设置
+ * 用法:
+ *   go test ./...                         检查全部场景
+ *   go test -run TestGolden/cube          只检查一个场景
+ *   go test -run TestGolden -args -update 重新生成黄金图像
+ */
+
+package golden
+
+import (
+	"image"
+	_ "image/png"
+	"os"
+
+	"github.com/go-gl/mathgl/mgl32"
+
+	camerascene "camera/scene"
+	cubescene "cube/scene"
+	"gfx/camera"
+	"gfx/gltf"
+	"gfx/obj"
+	"gfx/soft"
+	quadranglescene "quadrangle/scene"
+	spherescene "sphere/scene"
+	texturesscene "textures/scene"
+	trianglescene "triangle/scene"
+	triangle2scene "triangle2/scene"
+	triangle3scene "triangle3/scene"
+	windowscene "window/scene"
+)
+
+//goldenScene 一个离屏渲染场景,宽高与对应例程的窗口一致
+type goldenScene struct {
+	name   string
+	width  int
+	height int
+	render func(ctx *soft.Context) error
+}
+
+var scenes = []goldenScene{
+	{"window", 600, 400, func(ctx *soft.Context) error {
+		windowscene.Render(ctx)
+		return nil
+	}},
+	{"triangle", 600, 400, func(ctx *soft.Context) error {
+		return trianglescene.Render(ctx)
+	}},
+	{"triangle2", 600, 400, func(ctx *soft.Context) error {
+		return triangle2scene.Render(ctx)
+	}},
+	{"triangle3", 600, 400, func(ctx *soft.Context) error {
+		return triangle3scene.Render(ctx)
+	}},
+	{"quadrangle", 600, 400, func(ctx *soft.Context) error {
+		return quadranglescene.Render(ctx)
+	}},
+	{"textures", 800, 600, func(ctx *soft.Context) error {
+		texture0, err := loadTexture("../textures/images/RTS_Crate.png")
+		if err != nil {
+			return err
+		}
+		texture1, err := loadTexture("../textures/images/trollface.png")
+		if err != nil {
+			return err
+		}
+		return texturesscene.Render(ctx, texture0, texture1)
+	}},
+	{"cube", cubescene.SCRWIDTH, cubescene.SCRHEIGHT, func(ctx *soft.Context) error {
+		texture1, err := loadTexture("../cube/src/1.png")
+		if err != nil {
+			return err
+		}
+		texture2, err := loadTexture("../cube/src/12.png")
+		if err != nil {
+			return err
+		}
+		return cubescene.Render(ctx, texture1, texture2, 0.5)
+	}},
+	{"sphere", 600, 600, func(ctx *soft.Context) error {
+		return spherescene.Render(ctx)
+	}},
+	{"camera", camerascene.ScreenWidth, camerascene.ScreenHeight, func(ctx *soft.Context) error {
+		cam := camera.GetCamera(mgl32.Vec3{0.0, 0.0, 3.0})
+		return camerascene.Render(ctx, cam, 1.0)
+	}},
+	{"obj", 600, 400, func(ctx *soft.Context) error {
+		model, err := obj.Load("../gfx/obj/testdata/shapes.obj")
+		if err != nil {
+			return err
+		}
+		renderOBJ(ctx, model, 600, 400)
+		return nil
+	}},
+	{"gltf", 600, 400, func(ctx *soft.Context) error {
+		model, err := gltf.Load("../gfx/gltf/testdata/shapes.gltf")
+		if err != nil {
+			return err
+		}
+		return renderGLTF(ctx, model, 600, 400)
+	}},
+	{"glb", 600, 400, func(ctx *soft.Context) error {
+		model, err := gltf.Load("../gfx/gltf/testdata/shapes.glb")
+		if err != nil {
+			return err
+		}
+		return renderGLTF(ctx, model, 600, 400)
+	}},
+	{"atlas", 600, 400, func(ctx *soft.Context) error {
+		return renderAtlas(ctx, 600, 400)
+	}},
+}
+
+//loadTexture 与例程一样按SRGB_ALPHA内部格式加载纹理
+func loadTexture(file string) (*soft.Texture, error) {
+	img, err := decodeImageFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return soft.NewTexture(img, true), nil
+}
+
+//decodeImageFile 读取并解码图片文件
+func decodeImageFile(file string) (image.Image, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	return img, err
+}
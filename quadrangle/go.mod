@@ -3,6 +3,10 @@ module quadrangle
 go 1.13
 
 require (
+	gfx v0.0.0
 	github.com/go-gl/gl v0.0.0-20190320180904-bf2b1f2f34d7
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20191125211704-12ad95a8df72
+	github.com/go-gl/mathgl v0.0.0-20190713194549-592312d8590a
 )
+
+replace gfx => ../gfx
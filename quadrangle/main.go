@@ -1,13 +1,14 @@
 package main
 
 import (
-	"fmt"
 	"log"
 	"runtime"
-	"strings"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
+
+	"gfx"
+	"quadrangle/scene"
 )
 
 // 屏幕宽，高
@@ -21,7 +22,7 @@ layout (location = 0) in vec3 aPos;
 void main() {
     gl_Position = vec4(aPos, 1.0);
 }
-` + "\x00"
+`
 
 var fragment_shader_source = `
 #version 330
@@ -29,26 +30,7 @@ out vec4 FragColor;
 void main() {
     FragColor = vec4(1.0f, 0.5f, 0.2f, 1.0f);
 }
-` + "\x00"
-
-// 三角形的顶点数据
-var triangle = []float32{
-	//第一个三角形
-	0.5, 0.5, 0.0, //右上
-	0.5, -0.5, 0.0, //右下
-	-0.5, -0.5, 0.0, //左下
-
-	//第二个三角形
-	-0.5, -0.5, 0.0, //左下
-	0.5, 0.5, 0.0, //右上
-	-0.5, 0.5, 0.0, //左上
-}
-
-//索引数据(注意这里是从0开始的)
-var indices = []uint32{
-	0, 1, 5, //第一个三角形
-	1, 2, 5, //第二个三角形
-}
+`
 
 func init() {
 	// GLFW event handling must run on the main OS thread
@@ -82,79 +64,34 @@ func main() {
 	// 指定当前视口尺寸(前两个参数为左下角位置，后两个参数是渲染窗口宽、高)
 	gl.Viewport(0, 0, screen_width, screen_height)
 
-	//生成并绑定VAO和VBO
-	var vertex_array_object uint32 // VAO
-	gl.GenVertexArrays(1, &vertex_array_object)
-	gl.BindVertexArray(vertex_array_object)
-
-	var vertex_buffer_object uint32 // VBO
-	gl.GenBuffers(1, &vertex_buffer_object)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vertex_buffer_object)
-	// 将顶点数据绑定至当前默认的缓冲中
-	gl.BufferData(gl.ARRAY_BUFFER, len(triangle)*4, gl.Ptr(triangle), gl.STATIC_DRAW)
-
-	var element_buffer_object uint32
-	gl.GenBuffers(1, &element_buffer_object)
-	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, element_buffer_object)
-	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, gl.Ptr(indices), gl.STATIC_DRAW)
-	// 设置顶点属性指针
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 3*4, gl.PtrOffset(0))
-	gl.EnableVertexAttribArray(0)
-	// 解绑VAO和VBO
-	gl.BindVertexArray(0)
-	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	// 上传顶点数据并按顶点布局设置属性指针
+	quadrangle, err := gfx.NewMesh(gl.TRIANGLES, scene.Layout, scene.Quadrangle, scene.Indices)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer quadrangle.Delete()
 
 	// 生成并编译着色器
 	// 顶点着色器
-	var vertex_shader = gl.CreateShader(gl.VERTEX_SHADER)
-	csovert, free := gl.Strs(vertex_shader_source)
-	gl.ShaderSource(vertex_shader, 1, csovert, nil)
-	free()
-	gl.CompileShader(vertex_shader)
-	var success int32
-	// 检查着色器是否成功编译，如果编译失败，打印错误信息
-	gl.GetShaderiv(vertex_shader, gl.COMPILE_STATUS, &success)
-	if success == gl.FALSE {
-		var logLength1 int32
-		gl.GetShaderiv(vertex_shader, gl.INFO_LOG_LENGTH, &logLength1)
-
-		log1 := strings.Repeat("\x00", int(logLength1+1))
-		gl.GetShaderInfoLog(vertex_shader, logLength1, nil, gl.Str(log1))
-		fmt.Println("ERROR::SHADER::VERTEX::COMPILATION_FAILED", gl.Str(log1))
+	vertex_shader, err := gfx.NewShader(vertex_shader_source, gl.VERTEX_SHADER)
+	if err != nil {
+		log.Fatalln(err)
 	}
 	// 片段着色器
-	fragment_shader := gl.CreateShader(gl.FRAGMENT_SHADER)
-	csofragm, free := gl.Strs(fragment_shader_source)
-	gl.ShaderSource(fragment_shader, 1, csofragm, nil)
-	free()
-	gl.CompileShader(fragment_shader)
-	// 检查着色器是否成功编译，如果编译失败，打印错误信息
-	gl.GetShaderiv(fragment_shader, gl.COMPILE_STATUS, &success)
-	if success == gl.FALSE {
-		var logLength2 int32
-		gl.GetShaderiv(fragment_shader, gl.INFO_LOG_LENGTH, &logLength2)
-
-		log2 := strings.Repeat("\x00", int(logLength2+1))
-		gl.GetShaderInfoLog(fragment_shader, logLength2, nil, gl.Str(log2))
-		fmt.Println("ERROR::SHADER::FRAGMENT::COMPILATION_FAILED", gl.Str(log2))
+	fragment_shader, err := gfx.NewShader(fragment_shader_source, gl.FRAGMENT_SHADER)
+	if err != nil {
+		log.Fatalln(err)
 	}
-	shader_program := gl.CreateProgram()
-	gl.AttachShader(shader_program, vertex_shader)
-	gl.AttachShader(shader_program, fragment_shader)
-	gl.LinkProgram(shader_program)
-	// 检查着色器是否成功链接，如果链接失败，打印错误信息
-	gl.GetProgramiv(shader_program, gl.LINK_STATUS, &success)
-	if success == gl.FALSE {
-		var logLength3 int32
-		gl.GetShaderiv(shader_program, gl.INFO_LOG_LENGTH, &logLength3)
-
-		log3 := strings.Repeat("\x00", int(logLength3+1))
-		gl.GetShaderInfoLog(shader_program, logLength3, nil, gl.Str(log3))
-		fmt.Println("ERROR::SHADER::FRAGMENT::LINKING_FAILED", gl.Str(log3))
+	// 链接着色器程序,链接失败时返回错误信息
+	shader_program, err := gfx.NewProgram(vertex_shader, fragment_shader)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer shader_program.Delete()
+	// 检查顶点布局与着色器的输入是否一致
+	if err := scene.Layout.Check(shader_program); err != nil {
+		log.Fatalln(err)
 	}
-	// 删除着色器
-	gl.DeleteShader(vertex_shader)
-	gl.DeleteShader(fragment_shader)
 	// 渲染循环
 	for !window.ShouldClose() {
 		// 清空颜色缓冲
@@ -162,19 +99,13 @@ func main() {
 		gl.Clear(gl.COLOR_BUFFER_BIT)
 
 		// 使用着色器程序
-		gl.UseProgram(shader_program)
+		shader_program.Use()
 		// 绘制四边形
-		gl.BindVertexArray(vertex_array_object)
-		gl.DrawArrays(gl.TRIANGLES, 0, 6)
-		gl.BindVertexArray(0)
+		quadrangle.Draw()
 
 		// 交换缓冲并且检查是否有触发事件(比如键盘输入、鼠标移动等）
 		window.SwapBuffers()
 		glfw.PollEvents()
 
 	}
-	// 删除VAO和VBO
-	gl.DeleteVertexArrays(1, &vertex_array_object)
-	gl.DeleteBuffers(1, &vertex_buffer_object)
-	gl.DeleteBuffers(1, &element_buffer_object)
 }
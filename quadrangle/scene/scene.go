@@ -0,0 +1,73 @@
+/*
+四边形场景的顶点数据与软件渲染版本
+main.go 使用同一份数据通过OpenGL绘制
+*/
+
+package scene
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx"
+	"gfx/soft"
+)
+
+// 四边形(两个三角形)的顶点数据
+var Quadrangle = []float32{
+	//第一个三角形
+	0.5, 0.5, 0.0, //右上
+	0.5, -0.5, 0.0, //右下
+	-0.5, -0.5, 0.0, //左下
+
+	//第二个三角形
+	-0.5, -0.5, 0.0, //左下
+	0.5, 0.5, 0.0, //右上
+	-0.5, 0.5, 0.0, //左上
+}
+
+//索引数据(注意这里是从0开始的)
+var Indices = []uint32{
+	0, 1, 5, //第一个三角形
+	1, 2, 5, //第二个三角形
+}
+
+//Layout 顶点布局: 每个顶点只有位置, 对应 layout (location = 0) in vec3 aPos
+var Layout = gfx.NewVertexLayout(gfx.FloatAttrib("aPos", 3))
+
+//Render 使用软件光栅化绘制与main.go相同的一帧
+func Render(ctx *soft.Context) error {
+	VAO := ctx.GenVertexArray()
+	ctx.BindVertexArray(VAO)
+	VBO := ctx.GenBuffer()
+	ctx.BindBuffer(soft.ARRAY_BUFFER, VBO)
+	ctx.BufferData(soft.ARRAY_BUFFER, Quadrangle)
+	EBO := ctx.GenBuffer()
+	ctx.BindBuffer(soft.ELEMENT_ARRAY_BUFFER, EBO)
+	ctx.BufferData(soft.ELEMENT_ARRAY_BUFFER, Indices)
+	if err := Layout.ApplySoft(ctx); err != nil {
+		return err
+	}
+	ctx.BindVertexArray(0)
+
+	// 对应vertex_shader_source与fragment_shader_source
+	program := &soft.Program{
+		Vertex: func(in []mgl32.Vec4, out []float32) mgl32.Vec4 {
+			return in[0]
+		},
+		Fragment: func(in []float32) mgl32.Vec4 {
+			return mgl32.Vec4{1.0, 0.5, 0.2, 1.0}
+		},
+	}
+
+	ctx.ClearColor(1.0, 1.0, 1.0, 1.0)
+	ctx.Clear(soft.COLOR_BUFFER_BIT)
+	ctx.UseProgram(program)
+	ctx.BindVertexArray(VAO)
+	ctx.DrawElements(soft.TRIANGLES, int32(len(Indices)), 0)
+	ctx.BindVertexArray(0)
+
+	ctx.DeleteVertexArray(VAO)
+	ctx.DeleteBuffer(VBO)
+	ctx.DeleteBuffer(EBO)
+	return nil
+}
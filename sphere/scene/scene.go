@@ -0,0 +1,74 @@
+/*
+球体场景的顶点数据与软件渲染版本
+main.go 使用同一份数据通过OpenGL绘制
+*/
+
+package scene
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx"
+	"gfx/mesh"
+	"gfx/soft"
+)
+
+//  将球横纵划分成50X50的网格
+
+const Y_SEGMENTS = 50
+const X_SEGMENTS = 50
+
+//Sphere 生成球的顶点与索引,顶点只含位置
+func Sphere() ([]float32, []uint32) {
+	sphere := mesh.UVSphere(1.0, X_SEGMENTS, Y_SEGMENTS)
+	return sphere.Interleave(mesh.Position), sphere.Indices
+}
+
+//Layout 顶点布局: 每个顶点只有位置, 对应shader/task3.vs的输入
+var Layout = gfx.NewVertexLayout(gfx.FloatAttrib("aPos", 3))
+
+//Render 使用软件光栅化绘制与main.go相同的一帧
+func Render(ctx *soft.Context) error {
+	vertices, indices := Sphere()
+
+	VAO := ctx.GenVertexArray()
+	VBO := ctx.GenBuffer()
+	EBO := ctx.GenBuffer()
+	ctx.BindVertexArray(VAO)
+	ctx.BindBuffer(soft.ARRAY_BUFFER, VBO)
+	ctx.BufferData(soft.ARRAY_BUFFER, vertices)
+	ctx.BindBuffer(soft.ELEMENT_ARRAY_BUFFER, EBO)
+	ctx.BufferData(soft.ELEMENT_ARRAY_BUFFER, indices)
+	if err := Layout.ApplySoft(ctx); err != nil {
+		return err
+	}
+	ctx.BindBuffer(soft.ARRAY_BUFFER, 0)
+
+	// 对应shader/task3.vs与shader/task3.fs
+	program := &soft.Program{
+		Vertex: func(in []mgl32.Vec4, out []float32) mgl32.Vec4 {
+			return in[0]
+		},
+		Fragment: func(in []float32) mgl32.Vec4 {
+			return mgl32.Vec4{1.0, 0.635, 0.345, 1.0}
+		},
+	}
+
+	ctx.ClearColor(0.0, 0.34, 0.57, 1.0)
+	ctx.Clear(soft.COLOR_BUFFER_BIT)
+	ctx.UseProgram(program)
+	//开启面剔除(只需要展示一个面，否则会有重合)
+	ctx.Enable(soft.CULL_FACE)
+	ctx.CullFace(soft.BACK)
+
+	ctx.BindVertexArray(VAO)
+	//使用线框模式绘制
+	ctx.PolygonMode(soft.FRONT_AND_BACK, soft.LINE)
+	ctx.DrawElements(soft.TRIANGLES, int32(len(indices)), 0)
+	ctx.BindVertexArray(0)
+
+	ctx.DeleteVertexArray(VAO)
+	ctx.DeleteBuffer(VBO)
+	ctx.DeleteBuffer(EBO)
+	return nil
+}
@@ -0,0 +1,7 @@
+package main
+
+import "embed"
+
+//embedded 编译进程序的资源文件, 磁盘上找不到时使用, 程序在任意目录下都能运行
+//go:embed shaders images
+var embedded embed.FS
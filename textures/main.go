@@ -3,12 +3,15 @@ package main
 import (
 	"log"
 	"runtime"
-	"unsafe"
+	"time"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.1/glfw"
 
-	"textures/gfx"
+	"gfx"
+	"gfx/resource"
+	"gfx/texture"
+	"textures/scene"
 )
 
 const windowWidth = 800
@@ -49,65 +52,17 @@ func main() {
 	}
 }
 
-/*
- * Creates the Vertex Array Object for a triangle.
- */
-func createVAO(vertices []float32, indices []uint32) uint32 {
-
-	var VAO uint32
-	gl.GenVertexArrays(1, &VAO)
-
-	var VBO uint32
-	gl.GenBuffers(1, &VBO)
-
-	var EBO uint32
-	gl.GenBuffers(1, &EBO)
-
-	// Bind the Vertex Array Object first, then bind and set vertex buffer(s) and attribute pointers()
-	gl.BindVertexArray(VAO)
-
-	// copy vertices data into VBO (it needs to be bound first)
-	gl.BindBuffer(gl.ARRAY_BUFFER, VBO)
-	gl.BufferData(gl.ARRAY_BUFFER, len(vertices)*4, gl.Ptr(vertices), gl.STATIC_DRAW)
-
-	// copy indices into element buffer
-	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, EBO)
-	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*4, gl.Ptr(indices), gl.STATIC_DRAW)
-
-	// size of one whole vertex (sum of attrib sizes)
-	var stride int32 = 3*4 + 3*4 + 2*4
-	var offset int = 0
-
-	// position
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, stride, gl.PtrOffset(offset))
-	gl.EnableVertexAttribArray(0)
-	offset += 3 * 4
-
-	// color
-	gl.VertexAttribPointer(1, 3, gl.FLOAT, false, stride, gl.PtrOffset(offset))
-	gl.EnableVertexAttribArray(1)
-	offset += 3 * 4
-
-	// texture position
-	gl.VertexAttribPointer(2, 2, gl.FLOAT, false, stride, gl.PtrOffset(offset))
-	gl.EnableVertexAttribArray(2)
-	offset += 2 * 4
-
-	// unbind the VAO (safe practice so we don't accidentally (mis)configure it later)
-	gl.BindVertexArray(0)
-
-	return VAO
-}
-
 func programLoop(window *glfw.Window) error {
 
 	// the linked shader program determines how the data will be rendered
-	vertShader, err := gfx.NewShaderFromFile("shaders/basic.vert", gl.VERTEX_SHADER)
+	// shaders and images come from the example directory or the copy embedded in the binary
+	fsys := resource.DirOrEmbedded(".", embedded)
+	vertShader, err := gfx.NewShaderFromFS(fsys, "shaders/basic.vert", gl.VERTEX_SHADER)
 	if err != nil {
 		return err
 	}
 
-	fragShader, err := gfx.NewShaderFromFile("shaders/basic.frag", gl.FRAGMENT_SHADER)
+	fragShader, err := gfx.NewShaderFromFS(fsys, "shaders/basic.frag", gl.FRAGMENT_SHADER)
 	if err != nil {
 		return err
 	}
@@ -117,55 +72,57 @@ func programLoop(window *glfw.Window) error {
 		return err
 	}
 	defer shaderProgram.Delete()
-
-	vertices := []float32{
-		// top left
-		-0.75, 0.75, 0.0, // position
-		1.0, 0.0, 0.0, // Color
-		1.0, 0.0, // texture coordinates
-
-		// top right
-		0.75, 0.75, 0.0,
-		0.0, 1.0, 0.0,
-		0.0, 0.0,
-
-		// bottom right
-		0.75, -0.75, 0.0,
-		0.0, 0.0, 1.0,
-		0.0, 1.0,
-
-		// bottom left
-		-0.75, -0.75, 0.0,
-		1.0, 1.0, 1.0,
-		1.0, 1.0,
+	if err := scene.Layout.Check(shaderProgram); err != nil {
+		return err
 	}
-
-	indices := []uint32{
-		// rectangle
-		0, 1, 2, // top triangle
-		0, 2, 3, // bottom triangle
+	// recompile the shaders whenever their source files change
+	watcher := gfx.NewShaderWatcher(500 * time.Millisecond)
+	defer watcher.Close()
+	if err := watcher.Watch(shaderProgram); err != nil {
+		return err
 	}
 
-	VAO := createVAO(vertices, indices)
-	texture0, err := gfx.NewTextureFromFile("images/RTS_Crate.png",
-		gl.CLAMP_TO_EDGE, gl.CLAMP_TO_EDGE)
-	if err != nil {
-		panic(err.Error())
-	}
-	texture1, err := gfx.NewTextureFromFile("images/trollface.png",
-		gl.CLAMP_TO_EDGE, gl.CLAMP_TO_EDGE)
+	quad, err := gfx.NewMesh(gl.TRIANGLES, scene.Layout, scene.Vertices, scene.Indices)
 	if err != nil {
-		panic(err.Error())
+		return err
 	}
+	defer quad.Delete()
+	textureOptions := texture.TextureOptions{WrapS: gl.CLAMP_TO_EDGE, WrapT: gl.CLAMP_TO_EDGE}
+	// decode the images on background goroutines, upload them from the render loop
+	loader := texture.NewLoader(2)
+	crate := loader.LoadFS(fsys, "images/RTS_Crate.png", textureOptions)
+	troll := loader.LoadFS(fsys, "images/trollface.png", textureOptions)
+	// delete whichever textures were uploaded, also when a load error ends the loop early
+	defer func() {
+		for _, p := range []*texture.Pending{crate, troll} {
+			if tex := p.Texture(); tex != nil {
+				tex.Delete()
+			}
+		}
+	}()
 
 	for !window.ShouldClose() {
 		// poll events and call their registered callbacks
 		glfw.PollEvents()
+		watcher.Update()
+		loader.Upload(2 * time.Millisecond)
 
 		// background color
 		gl.ClearColor(0.2, 0.5, 0.5, 1.0)
 		gl.Clear(gl.COLOR_BUFFER_BIT)
 
+		// keep showing the background until both textures are uploaded
+		for _, p := range []*texture.Pending{crate, troll} {
+			if err := p.Err(); err != nil {
+				return err
+			}
+		}
+		texture0, texture1 := crate.Texture(), troll.Texture()
+		if texture0 == nil || texture1 == nil {
+			window.SwapBuffers()
+			continue
+		}
+
 		// draw vertices
 		shaderProgram.Use()
 
@@ -177,9 +134,7 @@ func programLoop(window *glfw.Window) error {
 		texture1.Bind(gl.TEXTURE1)
 		texture1.SetUniform(shaderProgram.GetUniformLocation("ourTexture1"))
 
-		gl.BindVertexArray(VAO)
-		gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, unsafe.Pointer(nil))
-		gl.BindVertexArray(0)
+		quad.Draw()
 
 		texture0.UnBind()
 		texture1.UnBind()
@@ -0,0 +1,98 @@
+/*
+纹理四边形场景的顶点数据与软件渲染版本
+main.go 使用同一份数据通过OpenGL绘制
+*/
+
+package scene
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx"
+	"gfx/soft"
+)
+
+// 顶点数据: 位置(3) + 颜色(3) + 纹理坐标(2)
+var Vertices = []float32{
+	// top left
+	-0.75, 0.75, 0.0, // position
+	1.0, 0.0, 0.0, // Color
+	1.0, 0.0, // texture coordinates
+
+	// top right
+	0.75, 0.75, 0.0,
+	0.0, 1.0, 0.0,
+	0.0, 0.0,
+
+	// bottom right
+	0.75, -0.75, 0.0,
+	0.0, 0.0, 1.0,
+	0.0, 1.0,
+
+	// bottom left
+	-0.75, -0.75, 0.0,
+	1.0, 1.0, 1.0,
+	1.0, 1.0,
+}
+
+// 索引数据
+var Indices = []uint32{
+	// rectangle
+	0, 1, 2, // top triangle
+	0, 2, 3, // bottom triangle
+}
+
+//Layout 顶点布局: 位置、颜色与纹理坐标交错存放, 对应shaders/basic.vert的输入
+var Layout = gfx.NewVertexLayout(
+	gfx.FloatAttrib("position", 3),
+	gfx.FloatAttrib("color", 3),
+	gfx.FloatAttrib("texCoord", 2),
+)
+
+//Render 使用软件光栅化绘制与main.go相同的一帧
+//texture0, texture1 对应images/RTS_Crate.png与images/trollface.png
+func Render(ctx *soft.Context, texture0, texture1 *soft.Texture) error {
+	VAO := ctx.GenVertexArray()
+	VBO := ctx.GenBuffer()
+	EBO := ctx.GenBuffer()
+	ctx.BindVertexArray(VAO)
+	ctx.BindBuffer(soft.ARRAY_BUFFER, VBO)
+	ctx.BufferData(soft.ARRAY_BUFFER, Vertices)
+	ctx.BindBuffer(soft.ELEMENT_ARRAY_BUFFER, EBO)
+	ctx.BufferData(soft.ELEMENT_ARRAY_BUFFER, Indices)
+
+	if err := Layout.ApplySoft(ctx); err != nil {
+		return err
+	}
+
+	ctx.BindVertexArray(0)
+
+	// 对应shaders/basic.vert与shaders/basic.frag
+	program := &soft.Program{
+		Varyings: 5,
+		Vertex: func(in []mgl32.Vec4, out []float32) mgl32.Vec4 {
+			out[0], out[1], out[2] = in[1].X(), in[1].Y(), in[1].Z()
+			out[3], out[4] = in[2].X(), in[2].Y()
+			return in[0]
+		},
+		Fragment: func(in []float32) mgl32.Vec4 {
+			// mix the two textures together (texture1 is colored with "ourColor")
+			c0 := texture0.Sample(in[3], in[4])
+			c1 := texture1.Sample(in[3], in[4])
+			c1 = mgl32.Vec4{c1[0] * in[0], c1[1] * in[1], c1[2] * in[2], c1[3]}
+			return c0.Mul(0.5).Add(c1.Mul(0.5))
+		},
+	}
+
+	ctx.ClearColor(0.2, 0.5, 0.5, 1.0)
+	ctx.Clear(soft.COLOR_BUFFER_BIT)
+	ctx.UseProgram(program)
+	ctx.BindVertexArray(VAO)
+	ctx.DrawElements(soft.TRIANGLES, int32(len(Indices)), 0)
+	ctx.BindVertexArray(0)
+
+	ctx.DeleteVertexArray(VAO)
+	ctx.DeleteBuffer(VBO)
+	ctx.DeleteBuffer(EBO)
+	return nil
+}
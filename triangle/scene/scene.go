@@ -0,0 +1,57 @@
+/*
+三角形场景的顶点数据与软件渲染版本
+main.go 使用同一份数据通过OpenGL绘制
+*/
+
+package scene
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx"
+	"gfx/soft"
+)
+
+// 三角形的顶点数据
+var Triangle = []float32{
+	-0.5, -0.5, 0.0,
+	0.5, -0.5, 0.0,
+	0.0, 0.5, 0.0,
+}
+
+//Layout 顶点布局: 每个顶点只有位置, 对应 layout (location = 0) in vec3 aPos
+var Layout = gfx.NewVertexLayout(gfx.FloatAttrib("aPos", 3))
+
+//Render 使用软件光栅化绘制与main.go相同的一帧
+func Render(ctx *soft.Context) error {
+	VAO := ctx.GenVertexArray()
+	ctx.BindVertexArray(VAO)
+	VBO := ctx.GenBuffer()
+	ctx.BindBuffer(soft.ARRAY_BUFFER, VBO)
+	ctx.BufferData(soft.ARRAY_BUFFER, Triangle)
+	if err := Layout.ApplySoft(ctx); err != nil {
+		return err
+	}
+	ctx.BindVertexArray(0)
+
+	// 对应vertex_shader_source与fragment_shader_source
+	program := &soft.Program{
+		Vertex: func(in []mgl32.Vec4, out []float32) mgl32.Vec4 {
+			return in[0]
+		},
+		Fragment: func(in []float32) mgl32.Vec4 {
+			return mgl32.Vec4{1.0, 0.5, 0.2, 1.0}
+		},
+	}
+
+	ctx.ClearColor(1.0, 1.0, 1.0, 1.0)
+	ctx.Clear(soft.COLOR_BUFFER_BIT)
+	ctx.UseProgram(program)
+	ctx.BindVertexArray(VAO)
+	ctx.DrawArrays(soft.TRIANGLES, 0, int32(len(Triangle)/Layout.Floats()))
+	ctx.BindVertexArray(0)
+
+	ctx.DeleteVertexArray(VAO)
+	ctx.DeleteBuffer(VBO)
+	return nil
+}
@@ -1,13 +1,14 @@
 package main
 
 import (
-	"fmt"
 	"log"
 	"runtime"
-	"strings"
 
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/glfw/v3.3/glfw"
+
+	"gfx"
+	"triangle3/scene"
 )
 
 // 屏幕宽，高
@@ -24,7 +25,7 @@ void main() {
 	gl_Position = vec4(aPos, 1.0);
 	ourColor = aColor;
 }
-` + "\x00"
+`
 
 var fragmentShaderSource = `
 #version 330
@@ -33,15 +34,7 @@ in vec3 ourColor;
 void main() {
     FragColor = vec4(ourColor, 1.0f);
 }
-` + "\x00"
-
-//三角形的顶点数据
-var triangle = []float32{
-	// positions         // colors
-	0.5, -0.5, 0.0, 1.0, 0.0, 0.0, // bottom right
-	-0.5, -0.5, 0.0, 0.0, 1.0, 0.0, // bottom left
-	0.0, 0.5, 0.0, 0.0, 0.0, 1.0, // top
-}
+`
 
 func init() {
 	// GLFW event handling must run on the main OS thread
@@ -75,78 +68,33 @@ func main() {
 	// 指定当前视口尺寸(前两个参数为左下角位置，后两个参数是渲染窗口宽、高)
 	gl.Viewport(0, 0, screenWidth, screenHeight)
 
-	//生成并绑定VAO和VBO
-	var VAO uint32 // VAO
-	gl.GenVertexArrays(1, &VAO)
-
-	var VBO uint32 // VBO
-	gl.GenBuffers(1, &VBO)
-
-	gl.BindVertexArray(VAO)
-	gl.BindBuffer(gl.ARRAY_BUFFER, VBO)
-	// 将顶点数据绑定至当前默认的缓冲中
-	gl.BufferData(gl.ARRAY_BUFFER, len(triangle)*4, gl.Ptr(triangle), gl.STATIC_DRAW)
-	// 设置顶点属性指针
-	gl.VertexAttribPointer(0, 3, gl.FLOAT, false, 6*4, gl.PtrOffset(0))
-	gl.EnableVertexAttribArray(0)
-	// 设置颜色属性指针
-	gl.VertexAttribPointer(1, 3, gl.FLOAT, false, 6*4, gl.PtrOffset(12))
-	gl.EnableVertexAttribArray(1)
-
-	// 解绑VAO和VBO
-	gl.BindVertexArray(0)
-	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	// 上传顶点数据并按顶点布局设置属性指针
+	triangle, err := gfx.NewMesh(gl.TRIANGLES, scene.Layout, scene.Triangle, nil)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer triangle.Delete()
 	// 生成并编译着色器
 	// 顶点着色器
-	var vertexShader = gl.CreateShader(gl.VERTEX_SHADER)
-	csovert, free := gl.Strs(vertexShaderSource)
-	gl.ShaderSource(vertexShader, 1, csovert, nil)
-	free()
-	gl.CompileShader(vertexShader)
-	var success int32
-	// 检查着色器是否成功编译，如果编译失败，打印错误信息
-	gl.GetShaderiv(vertexShader, gl.COMPILE_STATUS, &success)
-	if success == gl.FALSE {
-		var logLength1 int32
-		gl.GetShaderiv(vertexShader, gl.INFO_LOG_LENGTH, &logLength1)
-
-		log1 := strings.Repeat("\x00", int(logLength1+1))
-		gl.GetShaderInfoLog(vertexShader, logLength1, nil, gl.Str(log1))
-		fmt.Println("ERROR::SHADER::VERTEX::COMPILATION_FAILED", gl.Str(log1))
+	vertexShader, err := gfx.NewShader(vertexShaderSource, gl.VERTEX_SHADER)
+	if err != nil {
+		log.Fatalln(err)
 	}
 	// 片段着色器
-	fragmentShader := gl.CreateShader(gl.FRAGMENT_SHADER)
-	csofragm, free := gl.Strs(fragmentShaderSource)
-	gl.ShaderSource(fragmentShader, 1, csofragm, nil)
-	free()
-	gl.CompileShader(fragmentShader)
-	// 检查着色器是否成功编译，如果编译失败，打印错误信息
-	gl.GetShaderiv(fragmentShader, gl.COMPILE_STATUS, &success)
-	if success == gl.FALSE {
-		var logLength2 int32
-		gl.GetShaderiv(fragmentShader, gl.INFO_LOG_LENGTH, &logLength2)
-
-		log2 := strings.Repeat("\x00", int(logLength2+1))
-		gl.GetShaderInfoLog(fragmentShader, logLength2, nil, gl.Str(log2))
-		fmt.Println("ERROR::SHADER::FRAGMENT::COMPILATION_FAILED", gl.Str(log2))
+	fragmentShader, err := gfx.NewShader(fragmentShaderSource, gl.FRAGMENT_SHADER)
+	if err != nil {
+		log.Fatalln(err)
 	}
-	shaderProgram := gl.CreateProgram()
-	gl.AttachShader(shaderProgram, vertexShader)
-	gl.AttachShader(shaderProgram, fragmentShader)
-	gl.LinkProgram(shaderProgram)
-	// 检查着色器是否成功链接，如果链接失败，打印错误信息
-	gl.GetProgramiv(shaderProgram, gl.LINK_STATUS, &success)
-	if success == gl.FALSE {
-		var logLength3 int32
-		gl.GetShaderiv(shaderProgram, gl.INFO_LOG_LENGTH, &logLength3)
-
-		log3 := strings.Repeat("\x00", int(logLength3+1))
-		gl.GetShaderInfoLog(shaderProgram, logLength3, nil, gl.Str(log3))
-		fmt.Println("ERROR::SHADER::FRAGMENT::LINKING_FAILED", gl.Str(log3))
+	// 链接着色器程序,链接失败时返回错误信息
+	shaderProgram, err := gfx.NewProgram(vertexShader, fragmentShader)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer shaderProgram.Delete()
+	// 检查顶点布局与着色器的输入是否一致
+	if err := scene.Layout.Check(shaderProgram); err != nil {
+		log.Fatalln(err)
 	}
-	// 删除着色器
-	gl.DeleteShader(vertexShader)
-	gl.DeleteShader(fragmentShader)
 	// 渲染循环
 	for !window.ShouldClose() {
 		// 清空颜色缓冲
@@ -154,18 +102,13 @@ func main() {
 		gl.Clear(gl.COLOR_BUFFER_BIT)
 
 		// 使用着色器程序
-		gl.UseProgram(shaderProgram)
+		shaderProgram.Use()
 		// 绘制三角形
-		gl.BindVertexArray(VAO)
-		gl.DrawArrays(gl.TRIANGLES, 0, 3)
+		triangle.Draw()
 
 		// 交换缓冲并且检查是否有触发事件(比如键盘输入、鼠标移动等）
 		glfw.PollEvents()
 		window.SwapBuffers()
 
 	}
-	// 删除VAO和VBO
-	gl.DeleteVertexArrays(1, &VAO)
-
-	gl.DeleteBuffers(1, &VBO)
 }
@@ -0,0 +1,62 @@
+/*
+多彩三角形场景的顶点数据与软件渲染版本
+main.go 使用同一份数据通过OpenGL绘制
+*/
+
+package scene
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+
+	"gfx"
+	"gfx/soft"
+)
+
+//三角形的顶点数据
+var Triangle = []float32{
+	// positions         // colors
+	0.5, -0.5, 0.0, 1.0, 0.0, 0.0, // bottom right
+	-0.5, -0.5, 0.0, 0.0, 1.0, 0.0, // bottom left
+	0.0, 0.5, 0.0, 0.0, 0.0, 1.0, // top
+}
+
+//Layout 顶点布局: 位置与颜色交错存放
+var Layout = gfx.NewVertexLayout(
+	gfx.FloatAttrib("aPos", 3),
+	gfx.FloatAttrib("aColor", 3),
+)
+
+//Render 使用软件光栅化绘制与main.go相同的一帧
+func Render(ctx *soft.Context) error {
+	VAO := ctx.GenVertexArray()
+	VBO := ctx.GenBuffer()
+	ctx.BindVertexArray(VAO)
+	ctx.BindBuffer(soft.ARRAY_BUFFER, VBO)
+	ctx.BufferData(soft.ARRAY_BUFFER, Triangle)
+	if err := Layout.ApplySoft(ctx); err != nil {
+		return err
+	}
+	ctx.BindVertexArray(0)
+
+	// 对应vertexShaderSource与fragmentShaderSource
+	program := &soft.Program{
+		Varyings: 3,
+		Vertex: func(in []mgl32.Vec4, out []float32) mgl32.Vec4 {
+			out[0], out[1], out[2] = in[1].X(), in[1].Y(), in[1].Z()
+			return in[0]
+		},
+		Fragment: func(in []float32) mgl32.Vec4 {
+			return mgl32.Vec4{in[0], in[1], in[2], 1.0}
+		},
+	}
+
+	ctx.ClearColor(0.2, 0.3, 0.3, 1.0)
+	ctx.Clear(soft.COLOR_BUFFER_BIT)
+	ctx.UseProgram(program)
+	ctx.BindVertexArray(VAO)
+	ctx.DrawArrays(soft.TRIANGLES, 0, int32(len(Triangle)/Layout.Floats()))
+
+	ctx.DeleteVertexArray(VAO)
+	ctx.DeleteBuffer(VBO)
+	return nil
+}
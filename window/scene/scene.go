@@ -0,0 +1,17 @@
+/*
+窗口场景的软件渲染版本
+main.go 通过OpenGL清空同样的颜色
+*/
+
+package scene
+
+import (
+	"gfx/soft"
+)
+
+//Render 使用软件光栅化绘制与main.go相同的一帧
+func Render(ctx *soft.Context) {
+	//清空颜色缓存
+	ctx.ClearColor(0.0, 0.34, 0.57, 1.0)
+	ctx.Clear(soft.COLOR_BUFFER_BIT)
+}